@@ -0,0 +1,560 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// adminTokenMinLength keeps the admin token from being guessed
+	adminTokenMinLength = 16
+	// redactedSecret replaces the secrets in the config dump
+	redactedSecret = "REDACTED"
+)
+
+// Config is every setting of the application. Each field is read, in order of precedence, from its command line
+// flag (the yaml keys joined by dots, e.g. --server.port), its env var, the YAML config file and the default
+type Config struct {
+	Server        Server        `yaml:"server"`
+	Database      Database      `yaml:"database"`
+	Cache         Cache         `yaml:"cache"`
+	Treasury      Treasury      `yaml:"treasury"`
+	ExchangeRates ExchangeRates `yaml:"exchange_rates"`
+	Purge         Purge         `yaml:"purge"`
+	Outbox        Outbox        `yaml:"outbox"`
+	Webhooks      Webhooks      `yaml:"webhooks"`
+	Health        Health        `yaml:"health"`
+	Tracing       Tracing       `yaml:"tracing"`
+	Log           Log           `yaml:"log"`
+	Admin         Admin         `yaml:"admin"`
+
+	// PrintConfig dumps the loaded config instead of starting the server
+	PrintConfig bool `yaml:"-"`
+}
+
+// Server drains the requests in flight for up to ShutdownTimeout on SIGTERM or SIGINT, after reporting it is not
+// ready for DrainDelay so the load balancer stops sending new ones
+type Server struct {
+	Port              int           `yaml:"port" env:"PORT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	DrainDelay        time.Duration `yaml:"drain_delay" env:"SERVER_DRAIN_DELAY"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+}
+
+// Database paths are relative to the config file when they come from it, the default ones are relative to the
+// project root so the server starts from any of its directories. Each query of the transactions is canceled after
+// ReadTimeout and each change after WriteTimeout, or earlier when the client disconnects
+type Database struct {
+	Path          string        `yaml:"path" env:"DATABASE_PATH" path:"true"`
+	InitScript    string        `yaml:"init_script" env:"DATABASE_INIT_SCRIPT" path:"true"`
+	MigrationsDir string        `yaml:"migrations_dir" env:"DATABASE_MIGRATIONS_DIR" path:"true"`
+	ReadTimeout   time.Duration `yaml:"read_timeout" env:"DATABASE_READ_TIMEOUT"`
+	WriteTimeout  time.Duration `yaml:"write_timeout" env:"DATABASE_WRITE_TIMEOUT"`
+}
+
+type Cache struct {
+	NumCounters    int64         `yaml:"num_counters" env:"CACHE_NUM_COUNTERS"`
+	MaxCost        int64         `yaml:"max_cost" env:"CACHE_MAX_COST"`
+	BufferItems    int64         `yaml:"buffer_items" env:"CACHE_BUFFER_ITEMS"`
+	TransactionTTL time.Duration `yaml:"transaction_ttl" env:"CACHE_TRANSACTION_TTL"`
+}
+
+// Treasury API calls are retried up to MaxRetries times, each attempt within AttemptTimeout and all of them within
+// Timeout. The circuit breaker fails the calls for BreakerOpenTimeout after BreakerFailures failed calls in a row,
+// and the calls are limited to RateLimit per second with bursts of RateBurst
+type Treasury struct {
+	Domain             string        `yaml:"domain" env:"TREASURY_DOMAIN"`
+	Path               string        `yaml:"path" env:"TREASURY_PATH"`
+	Timeout            time.Duration `yaml:"timeout" env:"TREASURY_TIMEOUT"`
+	SyncInterval       time.Duration `yaml:"sync_interval" env:"TREASURY_SYNC_INTERVAL"`
+	AttemptTimeout     time.Duration `yaml:"attempt_timeout" env:"TREASURY_ATTEMPT_TIMEOUT"`
+	MaxRetries         int           `yaml:"max_retries" env:"TREASURY_MAX_RETRIES"`
+	RetryBaseDelay     time.Duration `yaml:"retry_base_delay" env:"TREASURY_RETRY_BASE_DELAY"`
+	RetryMaxDelay      time.Duration `yaml:"retry_max_delay" env:"TREASURY_RETRY_MAX_DELAY"`
+	BreakerFailures    int           `yaml:"breaker_failures" env:"TREASURY_BREAKER_FAILURES"`
+	BreakerOpenTimeout time.Duration `yaml:"breaker_open_timeout" env:"TREASURY_BREAKER_OPEN_TIMEOUT"`
+	RateLimit          float64       `yaml:"rate_limit" env:"TREASURY_RATE_LIMIT"`
+	RateBurst          int           `yaml:"rate_burst" env:"TREASURY_RATE_BURST"`
+}
+
+// ExchangeRates are asked to the comma separated Providers in order until one has the rate: database, the local
+// copy of the Treasury rates, treasury, the Treasury API, and file, the static csv or json File
+type ExchangeRates struct {
+	Providers string `yaml:"providers" env:"EXCHANGE_RATE_PROVIDERS"`
+	File      string `yaml:"file" env:"EXCHANGE_RATE_FILE" path:"true"`
+}
+
+// ProviderNames returns the providers in order, without blanks
+func (e ExchangeRates) ProviderNames() []string {
+	names := []string{}
+	for _, name := range strings.Split(e.Providers, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Purge keeps the deleted transactions for Retention, a retention of 0 disables the purge
+type Purge struct {
+	Retention time.Duration `yaml:"retention" env:"TRANSACTION_PURGE_RETENTION"`
+	Interval  time.Duration `yaml:"interval" env:"TRANSACTION_PURGE_INTERVAL"`
+}
+
+// Outbox publishes the transaction events to stdout, to File or to WebhookURL
+type Outbox struct {
+	Publisher   string        `yaml:"publisher" env:"EVENT_PUBLISHER"`
+	File        string        `yaml:"file" env:"EVENT_PUBLISHER_FILE" path:"true"`
+	WebhookURL  string        `yaml:"webhook_url" env:"EVENT_PUBLISHER_WEBHOOK_URL"`
+	Interval    time.Duration `yaml:"interval" env:"OUTBOX_INTERVAL"`
+	MaxAttempts int           `yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS"`
+}
+
+// Webhooks are disabled after DisableAfter deliveries failed in a row
+type Webhooks struct {
+	Timeout      time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT"`
+	Interval     time.Duration `yaml:"interval" env:"WEBHOOK_INTERVAL"`
+	MaxAttempts  int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	DisableAfter int           `yaml:"disable_after" env:"WEBHOOK_DISABLE_AFTER"`
+}
+
+// Health gives each dependency check of the readiness probe up to Timeout to answer
+type Health struct {
+	Timeout time.Duration `yaml:"timeout" env:"HEALTH_TIMEOUT"`
+}
+
+// Tracing exports the spans of a SampleRatio of the traces to stdout or to the OTLP/HTTP collector in
+// OTLPEndpoint, the none exporter only propagates the traceparent of the requests to the Treasury API
+type Tracing struct {
+	Exporter     string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	ServiceName  string  `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	SampleRatio  float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// Log is the level the server starts logging at, it can be changed while it runs in /admin/log-level
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+// Admin authorizes the requests to the /admin endpoints sent with the bearer Token, they are not served without it
+type Admin struct {
+	Token string `yaml:"token" env:"ADMIN_TOKEN"`
+}
+
+// Default returns the config used when nothing overrides it, with the paths relative to baseDir
+func Default(baseDir string) *Config {
+	return &Config{
+		Server: Server{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			// longer than the treasury timeout, a conversion may wait for it
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			DrainDelay:      5 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database: Database{
+			Path:          filepath.Join(baseDir, "db", "transactions.db"),
+			InitScript:    filepath.Join(baseDir, "scripts", "init.sql"),
+			MigrationsDir: filepath.Join(baseDir, "scripts", "migrations"),
+			ReadTimeout:   5 * time.Second,
+			// a purge may delete many transactions at once
+			WriteTimeout: 30 * time.Second,
+		},
+		Cache: Cache{
+			NumCounters:    1e7,     // number of keys to track frequency of (10M).
+			MaxCost:        1 << 30, // maximum cost of cache (1GB).
+			BufferItems:    64,      // number of keys per Get buffer.
+			TransactionTTL: time.Hour,
+		},
+		Treasury: Treasury{
+			Domain:  "https://api.fiscaldata.treasury.gov",
+			Path:    "/services/api/fiscal_service/v1/accounting/od/rates_of_exchange",
+			Timeout: 30 * time.Second,
+			// the rates are published quarterly, a daily sync is enough to keep the local table up to date
+			SyncInterval:       24 * time.Hour,
+			AttemptTimeout:     5 * time.Second,
+			MaxRetries:         3,
+			RetryBaseDelay:     200 * time.Millisecond,
+			RetryMaxDelay:      5 * time.Second,
+			BreakerFailures:    5,
+			BreakerOpenTimeout: 30 * time.Second,
+			RateLimit:          10,
+			RateBurst:          10,
+		},
+		ExchangeRates: ExchangeRates{
+			Providers: "database,treasury",
+		},
+		Purge: Purge{
+			Retention: 90 * 24 * time.Hour,
+			Interval:  24 * time.Hour,
+		},
+		Outbox: Outbox{
+			Publisher:   "stdout",
+			Interval:    2 * time.Second,
+			MaxAttempts: 10,
+		},
+		Webhooks: Webhooks{
+			Timeout:      10 * time.Second,
+			Interval:     2 * time.Second,
+			MaxAttempts:  8,
+			DisableAfter: 20,
+		},
+		Health: Health{
+			Timeout: 2 * time.Second,
+		},
+		Tracing: Tracing{
+			Exporter:     "none",
+			OTLPEndpoint: "http://localhost:4318",
+			ServiceName:  "transaction-api",
+			SampleRatio:  1,
+		},
+		Log: Log{
+			Level: "info",
+		},
+	}
+}
+
+// Load builds the config from the defaults, the YAML file in --config or CONFIG_FILE, the env vars and the flags in
+// args, each one overriding the previous, and validates it
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	config := Default(projectRoot())
+
+	flags := flag.NewFlagSet("transaction-api", flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML config file, CONFIG_FILE by env")
+	flags.BoolVar(&config.PrintConfig, "print-config", false, "print the loaded config and exit")
+
+	// the flags are applied last, after the file they may point to and the env vars
+	flagValues := []func() error{}
+	for _, field := range fields(config) {
+		flags.Func(field.key, "overrides "+field.env, func(value string) error {
+			if err := field.set(value); err != nil {
+				return err
+			}
+
+			flagValues = append(flagValues, func() error { return field.set(value) })
+			return nil
+		})
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile == "" {
+		*configFile, _ = lookupEnv("CONFIG_FILE")
+	}
+
+	if *configFile != "" {
+		if err := config.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, field := range fields(config) {
+		if value, found := lookupEnv(field.env); found && value != "" {
+			if err := field.set(value); err != nil {
+				return nil, fmt.Errorf("invalid %s %q, %w", field.env, value, err)
+			}
+		}
+	}
+
+	for _, setFlag := range flagValues {
+		if err := setFlag(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// loadFile overrides the config with the keys present in the file, its relative paths are relative to the file
+func (c *Config) loadFile(name string) error {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	paths := map[string]string{}
+	for _, field := range fields(c) {
+		if field.path {
+			paths[field.key] = field.value.String()
+		}
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(string(content)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", name, err)
+	}
+
+	for _, field := range fields(c) {
+		if value := field.value.String(); field.path && value != paths[field.key] && value != "" && !filepath.IsAbs(value) {
+			field.value.SetString(filepath.Join(filepath.Dir(name), value))
+		}
+	}
+
+	return nil
+}
+
+// Validate reports every invalid setting in the same error
+func (c *Config) Validate() error {
+	errs := []error{}
+	invalid := func(key, reason string) {
+		errs = append(errs, fmt.Errorf("invalid %s, %s", key, reason))
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		invalid("server.port", "it must be between 1 and 65535")
+	}
+
+	if c.Database.Path == "" || c.Database.InitScript == "" || c.Database.MigrationsDir == "" {
+		invalid("database", "path, init_script and migrations_dir are required")
+	}
+
+	if c.Cache.NumCounters <= 0 || c.Cache.MaxCost <= 0 || c.Cache.BufferItems <= 0 {
+		invalid("cache", "num_counters, max_cost and buffer_items must be greater than 0")
+	}
+
+	if domain, err := url.Parse(c.Treasury.Domain); err != nil || domain.Scheme == "" || domain.Host == "" {
+		invalid("treasury.domain", "it must be an absolute URL")
+	}
+
+	if c.Treasury.MaxRetries < 0 {
+		invalid("treasury.max_retries", "it must not be negative, 0 disables the retries")
+	}
+
+	if c.Treasury.BreakerFailures <= 0 || c.Treasury.RateLimit <= 0 || c.Treasury.RateBurst <= 0 {
+		invalid("treasury", "breaker_failures, rate_limit and rate_burst must be greater than 0")
+	}
+
+	providers := c.ExchangeRates.ProviderNames()
+	if len(providers) == 0 {
+		invalid("exchange_rates.providers", "it must not be empty")
+	}
+
+	seen := map[string]bool{}
+	for _, provider := range providers {
+		switch {
+		case provider != "database" && provider != "treasury" && provider != "file":
+			invalid("exchange_rates.providers", fmt.Sprintf("%q must be database, treasury or file", provider))
+		case seen[provider]:
+			invalid("exchange_rates.providers", fmt.Sprintf("%q is repeated", provider))
+		case provider == "file" && c.ExchangeRates.File == "":
+			invalid("exchange_rates.file", "it is required by the file provider")
+		}
+		seen[provider] = true
+	}
+
+	if c.Outbox.MaxAttempts <= 0 {
+		invalid("outbox.max_attempts", "it must be greater than 0")
+	}
+
+	switch c.Outbox.Publisher {
+	case "stdout":
+	case "file":
+		if c.Outbox.File == "" {
+			invalid("outbox.file", "it is required by the file event publisher")
+		}
+	case "webhook":
+		if c.Outbox.WebhookURL == "" {
+			invalid("outbox.webhook_url", "it is required by the webhook event publisher")
+		}
+	default:
+		invalid("outbox.publisher", "it must be stdout, file or webhook")
+	}
+
+	if c.Webhooks.MaxAttempts <= 0 || c.Webhooks.DisableAfter <= 0 {
+		invalid("webhooks", "max_attempts and disable_after must be greater than 0")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if endpoint, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			invalid("tracing.otlp_endpoint", "it must be an absolute URL")
+		}
+	default:
+		invalid("tracing.exporter", "it must be none, stdout or otlp")
+	}
+
+	if c.Tracing.ServiceName == "" {
+		invalid("tracing.service_name", "it must not be empty")
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "it must be between 0 and 1")
+	}
+
+	if c.Admin.Token != "" && len(c.Admin.Token) < adminTokenMinLength {
+		invalid("admin.token", fmt.Sprintf("it must have at least %d characters", adminTokenMinLength))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		invalid("log.level", "it must be debug, info, warn or error")
+	}
+
+	positive := map[string]time.Duration{
+		"server.read_header_timeout":    c.Server.ReadHeaderTimeout,
+		"server.read_timeout":           c.Server.ReadTimeout,
+		"server.write_timeout":          c.Server.WriteTimeout,
+		"server.idle_timeout":           c.Server.IdleTimeout,
+		"server.shutdown_timeout":       c.Server.ShutdownTimeout,
+		"database.read_timeout":         c.Database.ReadTimeout,
+		"database.write_timeout":        c.Database.WriteTimeout,
+		"cache.transaction_ttl":         c.Cache.TransactionTTL,
+		"treasury.timeout":              c.Treasury.Timeout,
+		"treasury.sync_interval":        c.Treasury.SyncInterval,
+		"treasury.attempt_timeout":      c.Treasury.AttemptTimeout,
+		"treasury.retry_base_delay":     c.Treasury.RetryBaseDelay,
+		"treasury.retry_max_delay":      c.Treasury.RetryMaxDelay,
+		"treasury.breaker_open_timeout": c.Treasury.BreakerOpenTimeout,
+		"purge.interval":                c.Purge.Interval,
+		"outbox.interval":               c.Outbox.Interval,
+		"webhooks.timeout":              c.Webhooks.Timeout,
+		"webhooks.interval":             c.Webhooks.Interval,
+		"health.timeout":                c.Health.Timeout,
+	}
+	for _, field := range fields(c) {
+		if duration, found := positive[field.key]; found && duration <= 0 {
+			invalid(field.key, "it must be greater than 0")
+		}
+	}
+
+	if c.Server.DrainDelay < 0 {
+		invalid("server.drain_delay", "it must not be negative")
+	}
+
+	if c.Purge.Retention < 0 {
+		invalid("purge.retention", "it must not be negative, 0 disables the purge")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Write dumps the config as YAML, in the format of the config file, without the secrets
+func (c *Config) Write(writer io.Writer) error {
+	redacted := *c
+	if redacted.Admin.Token != "" {
+		redacted.Admin.Token = redactedSecret
+	}
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&redacted); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// field is a setting of the config, its key is the path of yaml keys used as flag name
+type field struct {
+	key   string
+	env   string
+	path  bool
+	value reflect.Value
+}
+
+// fields lists the settings in the order they are declared
+func fields(config *Config) []field {
+	var walk func(prefix string, value reflect.Value) []field
+	walk = func(prefix string, value reflect.Value) []field {
+		result := []field{}
+		for i := 0; i < value.NumField(); i++ {
+			structField := value.Type().Field(i)
+			name := structField.Tag.Get("yaml")
+			if name == "-" {
+				continue
+			}
+
+			if structField.Type.Kind() == reflect.Struct {
+				result = append(result, walk(prefix+name+".", value.Field(i))...)
+				continue
+			}
+
+			result = append(result, field{
+				key:   prefix + name,
+				env:   structField.Tag.Get("env"),
+				path:  structField.Tag.Get("path") == "true",
+				value: value.Field(i),
+			})
+		}
+
+		return result
+	}
+
+	return walk("", reflect.ValueOf(config).Elem())
+}
+
+func (f field) set(value string) error {
+	switch {
+	case f.value.Type() == reflect.TypeOf(time.Duration(0)):
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("it must be a duration like 720h")
+		}
+
+		f.value.SetInt(int64(duration))
+	case f.value.Kind() == reflect.Int || f.value.Kind() == reflect.Int64:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errors.New("it must be an integer")
+		}
+
+		f.value.SetInt(number)
+	case f.value.Kind() == reflect.Float64:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("it must be a number")
+		}
+
+		f.value.SetFloat(number)
+	default:
+		f.value.SetString(value)
+	}
+
+	return nil
+}
+
+// projectRoot is the first directory, from the working directory up, with the database scripts, or the working
+// directory when there is none
+func projectRoot() string {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+
+	for dir := workingDir; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "scripts", "init.sql")); err == nil {
+			return dir
+		}
+
+		if filepath.Dir(dir) == dir {
+			return workingDir
+		}
+	}
+}
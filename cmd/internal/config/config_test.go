@@ -0,0 +1,303 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Load(t *testing.T) {
+	noEnv := func(string) (string, bool) { return "", false }
+	envOf := func(values map[string]string) func(string) (string, bool) {
+		return func(name string) (string, bool) {
+			value, found := values[name]
+			return value, found
+		}
+	}
+
+	writeFile := func(t *testing.T, content string) string {
+		name := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+		return name
+	}
+
+	t.Run("Load the defaults with the paths relative to the project root", func(t *testing.T) {
+		// When
+		config, err := Load(nil, noEnv)
+
+		// Then
+		assert.NoError(t, err)
+		root, _ := filepath.Abs("../../..")
+		expected := Default(root)
+		assert.Equal(t, expected, config)
+		assert.Equal(t, filepath.Join(root, "db", "transactions.db"), config.Database.Path)
+	})
+
+	t.Run("Load with the file overridden by env overridden by flags", func(t *testing.T) {
+		// Given
+		file := writeFile(t, "server:\n  port: 9000\npurge:\n  retention: 720h\n  interval: 1h\ncache:\n  transaction_ttl: 5m\n")
+		env := envOf(map[string]string{"TRANSACTION_PURGE_INTERVAL": "30m", "PORT": "9001"})
+
+		// When
+		config, err := Load([]string{"--config", file, "--server.port", "9002"}, env)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, 9002, config.Server.Port)
+		assert.Equal(t, 720*time.Hour, config.Purge.Retention)
+		assert.Equal(t, 30*time.Minute, config.Purge.Interval)
+		assert.Equal(t, 5*time.Minute, config.Cache.TransactionTTL)
+		assert.Equal(t, 10, config.Outbox.MaxAttempts)
+	})
+
+	t.Run("Load the file from CONFIG_FILE with its paths relative to it", func(t *testing.T) {
+		// Given
+		file := writeFile(t, "database:\n  path: data/transactions.db\n  init_script: /opt/scripts/init.sql\n")
+
+		// When
+		config, err := Load(nil, envOf(map[string]string{"CONFIG_FILE": file}))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(filepath.Dir(file), "data", "transactions.db"), config.Database.Path)
+		assert.Equal(t, "/opt/scripts/init.sql", config.Database.InitScript)
+	})
+
+	t.Run("Load the event publisher from the env vars", func(t *testing.T) {
+		// Given
+		env := envOf(map[string]string{"EVENT_PUBLISHER": "webhook", "EVENT_PUBLISHER_WEBHOOK_URL": "http://localhost:9090/events", "OUTBOX_INTERVAL": "500ms", "OUTBOX_MAX_ATTEMPTS": "3"})
+
+		// When
+		config, err := Load(nil, env)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, Outbox{Publisher: "webhook", WebhookURL: "http://localhost:9090/events", Interval: 500 * time.Millisecond, MaxAttempts: 3}, config.Outbox)
+	})
+
+	t.Run("Load the tracing from the env vars", func(t *testing.T) {
+		// Given
+		env := envOf(map[string]string{"TRACING_EXPORTER": "otlp", "TRACING_OTLP_ENDPOINT": "http://collector:4318", "TRACING_SAMPLE_RATIO": "0.25"})
+
+		// When
+		config, err := Load(nil, env)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, Tracing{Exporter: "otlp", OTLPEndpoint: "http://collector:4318", ServiceName: "transaction-api", SampleRatio: 0.25}, config.Tracing)
+	})
+
+	t.Run("Load with print config", func(t *testing.T) {
+		// When
+		config, err := Load([]string{"--print-config"}, noEnv)
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, config.PrintConfig)
+	})
+
+	t.Run("Load error invalid env var", func(t *testing.T) {
+		// When
+		config, err := Load(nil, envOf(map[string]string{"TRANSACTION_PURGE_RETENTION": "90 days"}))
+
+		// Then
+		assert.Nil(t, config)
+		assert.EqualError(t, err, `invalid TRANSACTION_PURGE_RETENTION "90 days", it must be a duration like 720h`)
+	})
+
+	t.Run("Load error invalid flag", func(t *testing.T) {
+		// When
+		config, err := Load([]string{"--outbox.max_attempts", "ten"}, noEnv)
+
+		// Then
+		assert.Nil(t, config)
+		assert.EqualError(t, err, `invalid value "ten" for flag -outbox.max_attempts: it must be an integer`)
+	})
+
+	t.Run("Load error invalid number", func(t *testing.T) {
+		// When
+		config, err := Load([]string{"--tracing.sample_ratio", "half"}, noEnv)
+
+		// Then
+		assert.Nil(t, config)
+		assert.EqualError(t, err, `invalid value "half" for flag -tracing.sample_ratio: it must be a number`)
+	})
+
+	t.Run("Load error unknown key in the file", func(t *testing.T) {
+		// Given
+		file := writeFile(t, "server:\n  prot: 9000\n")
+
+		// When
+		config, err := Load([]string{"--config", file}, noEnv)
+
+		// Then
+		assert.Nil(t, config)
+		assert.ErrorContains(t, err, "field prot not found")
+	})
+
+	t.Run("Load error missing file", func(t *testing.T) {
+		// When
+		config, err := Load([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml")}, noEnv)
+
+		// Then
+		assert.Nil(t, config)
+		assert.ErrorContains(t, err, "failed to read config file")
+	})
+}
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		change        func(config *Config)
+		expectedError string
+	}{
+		{
+			name:   "Validate the defaults",
+			change: func(config *Config) {},
+		},
+		{
+			name:   "Validate purge disabled",
+			change: func(config *Config) { config.Purge.Retention = 0 },
+		},
+		{
+			name: "Validate every invalid setting",
+			change: func(config *Config) {
+				config.Server.Port = 70000
+				config.Treasury.Domain = "api.fiscaldata.treasury.gov"
+				config.Outbox.Publisher = "file"
+				config.Webhooks.Interval = 0
+				config.Purge.Retention = -time.Hour
+				config.Tracing.SampleRatio = 1.5
+			},
+			expectedError: "invalid server.port, it must be between 1 and 65535\n" +
+				"invalid treasury.domain, it must be an absolute URL\n" +
+				"invalid outbox.file, it is required by the file event publisher\n" +
+				"invalid tracing.sample_ratio, it must be between 0 and 1\n" +
+				"invalid webhooks.interval, it must be greater than 0\n" +
+				"invalid purge.retention, it must not be negative, 0 disables the purge",
+		},
+		{
+			name:          "Validate admin token too short",
+			change:        func(config *Config) { config.Admin.Token = "secret" },
+			expectedError: "invalid admin.token, it must have at least 16 characters",
+		},
+		{
+			name: "Validate otlp exporter without endpoint",
+			change: func(config *Config) {
+				config.Tracing.Exporter = "otlp"
+				config.Tracing.OTLPEndpoint = ""
+			},
+			expectedError: "invalid tracing.otlp_endpoint, it must be an absolute URL",
+		},
+		{
+			name:          "Validate database without read timeout",
+			change:        func(config *Config) { config.Database.ReadTimeout = 0 },
+			expectedError: "invalid database.read_timeout, it must be greater than 0",
+		},
+		{
+			name: "Validate treasury resilience",
+			change: func(config *Config) {
+				config.Treasury.MaxRetries = -1
+				config.Treasury.RateLimit = 0
+				config.Treasury.RetryMaxDelay = 0
+			},
+			expectedError: "invalid treasury.max_retries, it must not be negative, 0 disables the retries\n" +
+				"invalid treasury, breaker_failures, rate_limit and rate_burst must be greater than 0\n" +
+				"invalid treasury.retry_max_delay, it must be greater than 0",
+		},
+		{
+			name: "Validate exchange rate file provider",
+			change: func(config *Config) {
+				config.ExchangeRates.Providers = "file, database"
+				config.ExchangeRates.File = "/srv/rates.csv"
+			},
+		},
+		{
+			name:          "Validate exchange rate file provider without file",
+			change:        func(config *Config) { config.ExchangeRates.Providers = "database,file" },
+			expectedError: "invalid exchange_rates.file, it is required by the file provider",
+		},
+		{
+			name:   "Validate unknown and repeated exchange rate providers",
+			change: func(config *Config) { config.ExchangeRates.Providers = "database,cache,database" },
+			expectedError: "invalid exchange_rates.providers, \"cache\" must be database, treasury or file\n" +
+				"invalid exchange_rates.providers, \"database\" is repeated",
+		},
+		{
+			name:          "Validate without exchange rate providers",
+			change:        func(config *Config) { config.ExchangeRates.Providers = " , " },
+			expectedError: "invalid exchange_rates.providers, it must not be empty",
+		},
+		{
+			name:          "Validate unknown log level",
+			change:        func(config *Config) { config.Log.Level = "verbose" },
+			expectedError: "invalid log.level, it must be debug, info, warn or error",
+		},
+		{
+			name:          "Validate unknown event publisher",
+			change:        func(config *Config) { config.Outbox.Publisher = "kafka" },
+			expectedError: "invalid outbox.publisher, it must be stdout, file or webhook",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			config := Default("/srv/transaction-api")
+			tt.change(config)
+
+			// When
+			err := config.Validate()
+
+			// Then
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Write(t *testing.T) {
+	// Given
+	var output bytes.Buffer
+	config := Default("/srv/transaction-api")
+
+	// When
+	err := config.Write(&output)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Contains(t, output.String(), "server:\n  port: 8080\n")
+	assert.Contains(t, output.String(), "  path: /srv/transaction-api/db/transactions.db\n")
+	assert.Contains(t, output.String(), "  retention: 2160h0m0s\n")
+	assert.NotContains(t, output.String(), "print")
+
+	// the dump is a valid config file
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(name, output.Bytes(), 0o600))
+	loaded, err := Load([]string{"--config", name}, func(string) (string, bool) { return "", false })
+	assert.NoError(t, err)
+	assert.Equal(t, config, loaded)
+}
+
+func Test_Write_RedactsSecrets(t *testing.T) {
+	// Given
+	var output bytes.Buffer
+	config := Default("/srv/transaction-api")
+	config.Admin.Token = "0123456789abcdef"
+
+	// When
+	err := config.Write(&output)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Contains(t, output.String(), "admin:\n  token: REDACTED\n")
+	assert.NotContains(t, output.String(), "0123456789abcdef")
+	assert.Equal(t, "0123456789abcdef", config.Admin.Token)
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/service"
+)
+
+// HealthController reports whether the server accepts new requests, it is not ready before it starts, while it
+// drains the requests in flight on shutdown and while a critical dependency is down
+type HealthController struct {
+	ready         atomic.Bool
+	healthService service.HealthService
+}
+
+func NewHealthController(healthService service.HealthService) *HealthController {
+	return &HealthController{
+		healthService: healthService,
+	}
+}
+
+func (h *HealthController) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Live only tells the process answers, it does not check the dependencies so a dependency down does not restart it
+func (h *HealthController) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": presentation.HealthUp})
+}
+
+func (h *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !h.ready.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"status": "unavailable"})
+		return
+	}
+
+	health := h.healthService.Check(r.Context())
+	if health.Status == presentation.HealthDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(health)
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	mock_service "github.com/pablorodrigo52/transaction-api/cmd/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthController_Live(t *testing.T) {
+	// Given
+	controller := NewHealthController(nil)
+	req := httptest.NewRequest("GET", "/health/live", nil)
+	rr := httptest.NewRecorder()
+
+	// When
+	http.HandlerFunc(controller.Live).ServeHTTP(rr, req)
+
+	// Then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"up"}`, rr.Body.String())
+}
+
+func TestHealthController_Ready(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockHealthService(mockController)
+	controller := NewHealthController(mockService)
+
+	tests := []struct {
+		name           string
+		ready          bool
+		setupMock      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Ready with every component up",
+			ready: true,
+			setupMock: func() {
+				mockService.EXPECT().Check(gomock.Any()).Return(presentation.NewHealthDTO(map[string]presentation.HealthComponentDTO{
+					"database": {Status: "up", Critical: true, LatencyMs: 0.4},
+				}))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"up","components":{"database":{"status":"up","critical":true,"latency_ms":0.4}}}`,
+		},
+		{
+			name:  "Ready with a non critical component down",
+			ready: true,
+			setupMock: func() {
+				mockService.EXPECT().Check(gomock.Any()).Return(presentation.NewHealthDTO(map[string]presentation.HealthComponentDTO{
+					"treasury": {Status: "down", LatencyMs: 2000, Error: "context deadline exceeded"},
+				}))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"degraded","components":{"treasury":{"status":"down","critical":false,"latency_ms":2000,"error":"context deadline exceeded"}}}`,
+		},
+		{
+			name:  "Not ready with a critical component down",
+			ready: true,
+			setupMock: func() {
+				mockService.EXPECT().Check(gomock.Any()).Return(presentation.NewHealthDTO(map[string]presentation.HealthComponentDTO{
+					"database": {Status: "down", Critical: true, LatencyMs: 1.2, Error: "database is locked"},
+				}))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status":"down","components":{"database":{"status":"down","critical":true,"latency_ms":1.2,"error":"database is locked"}}}`,
+		},
+		{
+			name:           "Not ready while draining without checking the components",
+			ready:          false,
+			setupMock:      func() {},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status":"unavailable"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			controller.SetReady(tt.ready)
+			tt.setupMock()
+			req := httptest.NewRequest("GET", "/health/ready", nil)
+			rr := httptest.NewRecorder()
+
+			// When
+			http.HandlerFunc(controller.Ready).ServeHTTP(rr, req)
+
+			// Then
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+// LogLevelController changes the level of the logs while the server runs, e.g. to debug a problem in production
+type LogLevelController struct {
+	level *slog.LevelVar
+	log   *slog.Logger
+}
+
+func NewLogLevelController(log *slog.Logger, level *slog.LevelVar) *LogLevelController {
+	return &LogLevelController{
+		level: level,
+		log:   log,
+	}
+}
+
+func (c *LogLevelController) GetLogLevel(w http.ResponseWriter, r *http.Request) error {
+	return json.NewEncoder(w).Encode(presentation.NewLogLevelDTO(c.level.Level()))
+}
+
+func (c *LogLevelController) UpdateLogLevel(w http.ResponseWriter, r *http.Request) error {
+	var levelDTO presentation.LogLevelDTO
+	if err := json.NewDecoder(r.Body).Decode(&levelDTO); err != nil {
+		return model.NewValidationError("Error decoding request body: " + err.Error())
+	}
+
+	if err := levelDTO.Validate(); err != nil {
+		return err
+	}
+
+	previous := c.level.Level()
+	c.level.Set(levelDTO.ToLevel())
+	util.Logger(r.Context(), c.log).Warn("Log level changed", "from", previous.String(), "to", c.level.Level().String())
+
+	return json.NewEncoder(w).Encode(presentation.NewLogLevelDTO(c.level.Level()))
+}
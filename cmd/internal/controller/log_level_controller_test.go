@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevelController_GetLogLevel(t *testing.T) {
+	// Given
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+	controller := NewLogLevelController(slog.Default(), level)
+	rr := httptest.NewRecorder()
+
+	// When
+	middleware.HandleErrors(controller.GetLogLevel).ServeHTTP(rr, httptest.NewRequest("GET", "/admin/log-level", nil))
+
+	// Then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"level":"warn"}`, rr.Body.String())
+}
+
+func TestLogLevelController_UpdateLogLevel(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedLevel  slog.Level
+	}{
+		{
+			name:           "Update to debug",
+			body:           `{"level":"debug"}`,
+			expectedStatus: http.StatusOK,
+			expectedLevel:  slog.LevelDebug,
+		},
+		{
+			name:           "Update error unknown level",
+			body:           `{"level":"verbose"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedLevel:  slog.LevelInfo,
+		},
+		{
+			name:           "Update error invalid body",
+			body:           `level=debug`,
+			expectedStatus: http.StatusBadRequest,
+			expectedLevel:  slog.LevelInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			level := new(slog.LevelVar)
+			controller := NewLogLevelController(slog.Default(), level)
+			rr := httptest.NewRecorder()
+
+			// When
+			middleware.HandleErrors(controller.UpdateLogLevel).ServeHTTP(rr, httptest.NewRequest("PUT", "/admin/log-level", strings.NewReader(tt.body)))
+
+			// Then
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedLevel, level.Level())
+		})
+	}
+}
@@ -1,80 +1,367 @@
 package controller
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/middleware"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/service"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	ETagHeader           = "ETag"
+	IfMatchHeader        = "If-Match"
+	AcceptPatchHeader    = "Accept-Patch"
+	// ActorHeader identifies who made the change, there is no authentication yet
+	ActorHeader  = "X-Actor"
+	defaultActor = "anonymous"
+	// IdempotentReplayedHeader marks a response replayed from a previous request with the same idempotency key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
 )
 
 type TransactionController struct {
-	service service.TransactionService
-	log     *slog.Logger
+	service            service.TransactionService
+	idempotencyService service.IdempotencyService
+	log                *slog.Logger
 }
 
-func NewTransactionController(log *slog.Logger, service service.TransactionService) *TransactionController {
+func NewTransactionController(
+	log *slog.Logger,
+	service service.TransactionService,
+	idempotencyService service.IdempotencyService) *TransactionController {
+
 	return &TransactionController{
-		service: service,
-		log:     log,
+		service:            service,
+		idempotencyService: idempotencyService,
+		log:                log,
 	}
 }
 
-func (t *TransactionController) GetTransactionByID(w http.ResponseWriter, r *http.Request) {
-	transactionID := t.validateTransactionID(r)
+func (t *TransactionController) GetTransactionByID(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := t.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
 
-	transaction := t.service.GetTransactionByID(transactionID)
+	transaction, err := t.service.GetTransactionByID(r.Context(), transactionID)
+	if err != nil {
+		return err
+	}
 
-	json.NewEncoder(w).Encode(transaction)
+	w.Header().Set(ETagHeader, presentation.ETag(transaction.Version))
+	return json.NewEncoder(w).Encode(transaction)
 }
 
-func (t *TransactionController) CreateTransaction(w http.ResponseWriter, r *http.Request) {
-	transactionDTO := t.decodeTransactionDTO(r)
+func (t *TransactionController) CreateTransaction(w http.ResponseWriter, r *http.Request) error {
+	transactionDTO, err := t.decodeTransactionDTO(r)
+	if err != nil {
+		return err
+	}
 
-	transaction := t.service.SaveTransaction(transactionDTO.ToTransaction())
+	if idempotencyKey := r.Header.Get(IdempotencyKeyHeader); idempotencyKey != "" {
+		return t.createTransactionIdempotent(w, r, idempotencyKey, transactionDTO)
+	}
 
-	json.NewEncoder(w).Encode(transaction)
+	transaction, err := t.service.SaveTransaction(r.Context(), transactionDTO.ToTransaction(), audit(w, r))
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(transaction)
 }
 
-func (t *TransactionController) UpdateTransaction(w http.ResponseWriter, r *http.Request) {
-	transactionID := t.validateTransactionID(r)
+// createTransactionIdempotent creates the transaction only once for the idempotency key, the retries with the
+// same key and body receive the stored response
+func (t *TransactionController) createTransactionIdempotent(w http.ResponseWriter, r *http.Request, idempotencyKey string, transactionDTO *presentation.TransactionDTO) error {
+	fingerprint, err := requestFingerprint(transactionDTO)
+	if err != nil {
+		return err
+	}
+
+	record, err := t.idempotencyService.Start(r.Context(), idempotencyKey, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	if record != nil {
+		return t.replayIdempotentResponse(w, r, record)
+	}
 
-	transactionDTO := t.decodeTransactionDTO(r)
+	// the key is completed in the same database transaction that creates the transaction
+	transactionAudit := audit(w, r)
+	transactionAudit.IdempotencyKey = idempotencyKey
+	transaction, err := t.service.SaveTransaction(r.Context(), transactionDTO.ToTransaction(), transactionAudit)
+	if err != nil {
+		if releaseErr := t.idempotencyService.Release(r.Context(), idempotencyKey); releaseErr != nil {
+			util.Logger(r.Context(), t.log).Error("Error releasing idempotency key", "idempotency_key", idempotencyKey, "error", releaseErr)
+		}
+		return err
+	}
 
-	transaction := t.service.UpdateTransactionByID(transactionID, transactionDTO.ToTransaction())
+	var response bytes.Buffer
+	if err := json.NewEncoder(&response).Encode(transaction); err != nil {
+		return err
+	}
 
-	json.NewEncoder(w).Encode(transaction)
+	// the key is already completed with the transaction, a failure here only makes the retries replay the
+	// transaction as it is then instead of this response
+	if err := t.idempotencyService.Complete(r.Context(), idempotencyKey, http.StatusOK, response.Bytes()); err != nil {
+		util.Logger(r.Context(), t.log).Error("Error storing idempotent response", "idempotency_key", idempotencyKey, "error", err)
+	}
+
+	_, err = w.Write(response.Bytes())
+	return err
 }
 
-func (t *TransactionController) DeleteTransaction(w http.ResponseWriter, r *http.Request) {
-	transactionID := t.validateTransactionID(r)
+// replayIdempotentResponse sends the response stored for the key, or the transaction created with it when the
+// response was not stored
+func (t *TransactionController) replayIdempotentResponse(w http.ResponseWriter, r *http.Request, record *model.IdempotencyRecord) error {
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	if record.ResponseBody == nil {
+		transaction, err := t.service.GetTransactionByID(r.Context(), record.TransactionID)
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(w).Encode(transaction)
+	}
+
+	w.WriteHeader(record.StatusCode)
+	_, err := w.Write(record.ResponseBody)
+	return err
+}
+
+func (t *TransactionController) UpdateTransaction(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := t.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
+
+	expectedVersion, err := t.validateIfMatch(r)
+	if err != nil {
+		return err
+	}
 
-	t.service.DeleteTransactionByID(transactionID)
+	transactionDTO, err := t.decodeTransactionDTO(r)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := t.service.UpdateTransactionByID(r.Context(), transactionID, transactionDTO.ToTransaction(), expectedVersion, audit(w, r))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(ETagHeader, presentation.ETag(transaction.Version))
+	return json.NewEncoder(w).Encode(transaction)
+}
+
+// PatchTransaction applies a JSON Merge Patch to the transaction, only the fields present are validated and changed
+func (t *TransactionController) PatchTransaction(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := t.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
+
+	expectedVersion, err := t.validateIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != presentation.MergePatchContentType {
+		w.Header().Set(AcceptPatchHeader, presentation.MergePatchContentType)
+		return model.NewUnsupportedMediaError("the patch must be sent as " + presentation.MergePatchContentType)
+	}
+
+	var patchDTO presentation.TransactionPatchDTO
+	if err := json.NewDecoder(r.Body).Decode(&patchDTO); err != nil {
+		return model.NewValidationError("Error decoding request body: " + err.Error())
+	}
+
+	if err := patchDTO.Validate(); err != nil {
+		return err
+	}
+
+	transaction, err := t.service.PatchTransactionByID(r.Context(), transactionID, patchDTO.ToTransactionPatch(), expectedVersion, audit(w, r))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(ETagHeader, presentation.ETag(transaction.Version))
+	return json.NewEncoder(w).Encode(transaction)
+}
+
+func (t *TransactionController) DeleteTransaction(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := t.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
+
+	expectedVersion, err := t.validateIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	if err := t.service.DeleteTransactionByID(r.Context(), transactionID, expectedVersion, audit(w, r)); err != nil {
+		return err
+	}
 
 	w.WriteHeader(http.StatusNoContent)
+	return nil
 }
 
-func (t *TransactionController) validateTransactionID(r *http.Request) int64 {
+// RestoreTransaction undeletes a transaction deleted by mistake
+func (t *TransactionController) RestoreTransaction(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := t.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
+
+	expectedVersion, err := t.validateIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := t.service.RestoreTransactionByID(r.Context(), transactionID, expectedVersion, audit(w, r))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(ETagHeader, presentation.ETag(transaction.Version))
+	return json.NewEncoder(w).Encode(transaction)
+}
+
+func (t *TransactionController) ListTransactions(w http.ResponseWriter, r *http.Request) error {
+	filterDTO := presentation.NewTransactionFilterDTO(r.URL.Query())
+	if err := filterDTO.Validate(); err != nil {
+		return err
+	}
+
+	page, err := t.service.ListTransactions(r.Context(), filterDTO.ToTransactionFilter())
+	if err != nil {
+		return err
+	}
+
+	page.Links = buildPageLinks(r.URL, page.Limit, page.Offset, page.Total)
+	return json.NewEncoder(w).Encode(page)
+}
+
+// GetTransactionHistory returns the changes of a transaction and the transaction as it was at the as_of query
+// parameter, now when it is not informed
+func (t *TransactionController) GetTransactionHistory(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := t.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
+
+	asOf := presentation.AsOf(r.URL.Query().Get("as_of"))
+	if err := asOf.Validate(); err != nil {
+		return err
+	}
+
+	history, err := t.service.GetTransactionHistory(r.Context(), transactionID, asOf.Get())
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(history)
+}
+
+func (t *TransactionController) validateTransactionID(r *http.Request) (int64, error) {
 	params := mux.Vars(r)
 	transactionID := presentation.TransactionID(params["id"])
 
-	transactionID.Validate()
-	return transactionID.Get()
+	if err := transactionID.Validate(); err != nil {
+		return 0, err
+	}
+
+	return transactionID.Get(), nil
+}
+
+// validateIfMatch returns the version required by the If-Match header, 0 when any version is accepted
+func (t *TransactionController) validateIfMatch(r *http.Request) (int64, error) {
+	ifMatch := presentation.IfMatch(r.Header.Get(IfMatchHeader))
+
+	if err := ifMatch.Validate(); err != nil {
+		return 0, err
+	}
+
+	return ifMatch.Get(), nil
 }
 
-func (t *TransactionController) decodeTransactionDTO(r *http.Request) *presentation.TransactionDTO {
+func (t *TransactionController) decodeTransactionDTO(r *http.Request) (*presentation.TransactionDTO, error) {
 	var transactionDTO presentation.TransactionDTO
 
 	if err := json.NewDecoder(r.Body).Decode(&transactionDTO); err != nil {
-		t.errorHandler("Error decoding request body: "+err.Error(), http.StatusBadRequest)
+		return nil, model.NewValidationError("Error decoding request body: " + err.Error())
 	}
 
-	transactionDTO.Validate()
-	return &transactionDTO
+	if err := transactionDTO.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &transactionDTO, nil
 }
 
-func (t *TransactionController) errorHandler(errorMessage string, statusCode int) {
-	panic(presentation.NewApiError(statusCode, errorMessage))
+// buildPageLinks builds the self, next and previous links keeping the filters of the current request
+func buildPageLinks(requestURL *url.URL, limit, offset int, total int64) presentation.PageLinksDTO {
+	pageURL := func(offset int) string {
+		query := requestURL.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+		return requestURL.Path + "?" + query.Encode()
+	}
+
+	links := presentation.PageLinksDTO{
+		Self: pageURL(offset),
+	}
+
+	if int64(offset+limit) < total {
+		links.Next = pageURL(offset + limit)
+	}
+
+	if offset > 0 {
+		links.Prev = pageURL(max(offset-limit, 0))
+	}
+
+	return links
+}
+
+// requestFingerprint hashes the decoded body, so the same request with a different formatting has the same fingerprint
+func requestFingerprint(transactionDTO *presentation.TransactionDTO) (string, error) {
+	body, err := json.Marshal(transactionDTO)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// audit identifies the actor and the request of a change, the service sets when it happened
+func audit(w http.ResponseWriter, r *http.Request) model.Audit {
+	return model.Audit{
+		Actor:     actor(r),
+		RequestID: middleware.RequestID(w, r),
+	}
+}
+
+func actor(r *http.Request) string {
+	if actor := r.Header.Get(ActorHeader); actor != "" {
+		return actor
+	}
+
+	return defaultActor
 }
@@ -2,16 +2,22 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/middleware"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
 	mock_service "github.com/pablorodrigo52/transaction-api/cmd/internal/service/mocks"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,26 +27,27 @@ func Test_ValidateTransactionID(t *testing.T) {
 	mockService := mock_service.NewMockTransactionService(mockController)
 
 	logger := slog.Default()
-	controller := NewTransactionController(logger, mockService)
+	controller := NewTransactionController(logger, mockService, nil)
 
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
-	router.HandleFunc("/transactions/{id}", controller.GetTransactionByID)
+	router.HandleFunc("/transactions/{id}", middleware.HandleErrors(controller.GetTransactionByID))
 
 	t.Run("Validate transaction id with success", func(t *testing.T) {
 		// Given
 		req, err := http.NewRequest("GET", "/transactions/1", nil)
 		assert.NoError(t, err)
 
-		expectedResponse := presentation.TransactionDTO{TransactionID: 1}
+		expectedResponse := presentation.TransactionDTO{TransactionID: 1, Version: 3}
 
-		mockService.EXPECT().GetTransactionByID(int64(1)).Return(&expectedResponse)
+		mockService.EXPECT().GetTransactionByID(gomock.Any(), int64(1)).Return(&expectedResponse, nil)
 
 		// When
 		router.ServeHTTP(rr, req)
 
 		// Then
 		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get(ETagHeader))
 		assert.NotNil(t, rr.Body.String())
 
 		var response presentation.TransactionDTO
@@ -52,30 +59,32 @@ func Test_ValidateTransactionID(t *testing.T) {
 
 	t.Run("Validate transaction id with error invalid parameter non-integer", func(t *testing.T) {
 		// Given
+		rr := httptest.NewRecorder()
 		req, err := http.NewRequest("GET", "/transactions/mock", nil)
 		assert.NoError(t, err)
 
 		expectedError := presentation.NewApiError(http.StatusBadRequest, "transaction ID must be a valid number: strconv.ParseInt: parsing \"mock\": invalid syntax")
 
-		// Then
-		defer assertPanicErrors(t, expectedError)
-
 		// When
 		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
 	})
 
 	t.Run("Validate transaction id with error invalid parameter integer inalid", func(t *testing.T) {
 		// Given
+		rr := httptest.NewRecorder()
 		req, err := http.NewRequest("GET", "/transactions/0", nil)
 		assert.NoError(t, err)
 
 		expectedError := presentation.NewApiError(http.StatusBadRequest, "transaction ID must be a valid number")
 
-		// Then
-		defer assertPanicErrors(t, expectedError)
-
 		// When
 		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
 	})
 }
 
@@ -85,13 +94,14 @@ func Test_DecodeTransactionDTO(t *testing.T) {
 	mockService := mock_service.NewMockTransactionService(mockController)
 
 	logger := slog.Default()
-	controller := NewTransactionController(logger, mockService)
+	controller := NewTransactionController(logger, mockService, nil)
 
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
-	router.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
-		controller.decodeTransactionDTO(r)
-	})
+	router.HandleFunc("/transactions", middleware.HandleErrors(func(w http.ResponseWriter, r *http.Request) error {
+		_, err := controller.decodeTransactionDTO(r)
+		return err
+	}))
 
 	t.Run("Decode transaction DTO with success", func(t *testing.T) {
 		// Given
@@ -99,7 +109,7 @@ func Test_DecodeTransactionDTO(t *testing.T) {
 			TransactionID:   1,
 			Description:     "mock",
 			TransactionDate: "2018-09-26T10:36:40Z",
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 
 		body, err := json.Marshal(transactionDTO)
@@ -117,20 +127,22 @@ func Test_DecodeTransactionDTO(t *testing.T) {
 
 	t.Run("Decode transaction DTO with error invalid JSON", func(t *testing.T) {
 		// Given
+		rr := httptest.NewRecorder()
 		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer([]byte("{invalid json}")))
 		assert.NoError(t, err)
 
 		expectedError := presentation.NewApiError(http.StatusBadRequest, "Error decoding request body: invalid character 'i' looking for beginning of object key string")
 
-		// Then
-		defer assertPanicErrors(t, expectedError)
-
 		// When
 		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
 	})
 
 	t.Run("Decode transaction DTO with error invalid request body", func(t *testing.T) {
 		// Given
+		rr := httptest.NewRecorder()
 		transactionDTO := presentation.TransactionDTO{}
 		body, err := json.Marshal(transactionDTO)
 		assert.NoError(t, err)
@@ -138,13 +150,18 @@ func Test_DecodeTransactionDTO(t *testing.T) {
 		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
 		assert.NoError(t, err)
 
-		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid description, it must be between 1 and 50 characters")
-
-		// Then
-		defer assertPanicErrors(t, expectedError)
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid description, it must be between 1 and 50 characters; transaction date must not be empty; invalid purchase amount, it must be greater than 0")
+		expectedError.InvalidParams = []presentation.InvalidParamDTO{
+			{Name: "description", Reason: "invalid description, it must be between 1 and 50 characters"},
+			{Name: "transaction_date", Reason: "transaction date must not be empty"},
+			{Name: "purchase_amount", Reason: "invalid purchase amount, it must be greater than 0"},
+		}
 
 		// When
 		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
 	})
 }
 
@@ -154,26 +171,27 @@ func Test_GetTransactionByID(t *testing.T) {
 	mockService := mock_service.NewMockTransactionService(mockController)
 
 	logger := slog.Default()
-	controller := NewTransactionController(logger, mockService)
+	controller := NewTransactionController(logger, mockService, nil)
 
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
-	router.HandleFunc("/transactions/{id}", controller.GetTransactionByID)
+	router.HandleFunc("/transactions/{id}", middleware.HandleErrors(controller.GetTransactionByID))
 
 	t.Run("Get transaction by id with success", func(t *testing.T) {
 		// Given
 		req, err := http.NewRequest("GET", "/transactions/1", nil)
 		assert.NoError(t, err)
 
-		expectedResponse := presentation.TransactionDTO{TransactionID: 1}
+		expectedResponse := presentation.TransactionDTO{TransactionID: 1, Version: 3}
 
-		mockService.EXPECT().GetTransactionByID(int64(1)).Return(&expectedResponse)
+		mockService.EXPECT().GetTransactionByID(gomock.Any(), int64(1)).Return(&expectedResponse, nil)
 
 		// When
 		router.ServeHTTP(rr, req)
 
 		// Then
 		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get(ETagHeader))
 		assert.NotNil(t, rr.Body.String())
 
 		var response presentation.TransactionDTO
@@ -182,19 +200,37 @@ func Test_GetTransactionByID(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedResponse, response)
 	})
+
+	t.Run("Get transaction by id with error transaction not found", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/transactions/2", nil)
+		assert.NoError(t, err)
+
+		expectedError := presentation.NewApiError(http.StatusNotFound, "transaction not found")
+
+		mockService.EXPECT().GetTransactionByID(gomock.Any(), int64(2)).Return(nil, model.NewNotFoundError("transaction not found"))
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
 }
 
 func Test_CreateTransaction(t *testing.T) {
 	t.Parallel()
 	mockController := gomock.NewController(t)
 	mockService := mock_service.NewMockTransactionService(mockController)
+	mockIdempotencyService := mock_service.NewMockIdempotencyService(mockController)
 
 	logger := slog.Default()
-	controller := NewTransactionController(logger, mockService)
+	controller := NewTransactionController(logger, mockService, mockIdempotencyService)
 
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
-	router.HandleFunc("/transactions", controller.CreateTransaction).Methods("POST")
+	router.HandleFunc("/transactions", middleware.HandleErrors(controller.CreateTransaction)).Methods("POST")
 
 	t.Run("Create transaction with success", func(t *testing.T) {
 		// Given
@@ -202,7 +238,7 @@ func Test_CreateTransaction(t *testing.T) {
 			TransactionID:   1,
 			Description:     "mock",
 			TransactionDate: "2018-09-26T10:36:40Z",
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 
 		body, err := json.Marshal(expectedResponse)
@@ -211,7 +247,7 @@ func Test_CreateTransaction(t *testing.T) {
 		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
 		assert.NoError(t, err)
 
-		mockService.EXPECT().SaveTransaction(expectedResponse.ToTransaction()).Return(&expectedResponse)
+		mockService.EXPECT().SaveTransaction(gomock.Any(), expectedResponse.ToTransaction(), gomock.Any()).Return(&expectedResponse, nil)
 
 		// When
 		router.ServeHTTP(rr, req)
@@ -226,6 +262,163 @@ func Test_CreateTransaction(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedResponse, response)
 	})
+
+	transactionDTO := presentation.TransactionDTO{
+		Description:     "mock",
+		TransactionDate: "2018-09-26T10:36:40Z",
+		PurchaseAmount:  100,
+	}
+	createdTransaction := transactionDTO
+	createdTransaction.TransactionID = 1
+
+	body, err := json.Marshal(transactionDTO)
+	assert.NoError(t, err)
+
+	fingerprint, err := requestFingerprint(&transactionDTO)
+	assert.NoError(t, err)
+
+	t.Run("Create transaction with a new idempotency key", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+		mockIdempotencyService.EXPECT().Start(gomock.Any(), "key-1", fingerprint).Return(nil, nil)
+		mockService.EXPECT().SaveTransaction(gomock.Any(), transactionDTO.ToTransaction(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*presentation.TransactionDTO, error) {
+				assert.Equal(t, "key-1", audit.IdempotencyKey)
+				return &createdTransaction, nil
+			})
+		mockIdempotencyService.EXPECT().Complete(gomock.Any(), "key-1", http.StatusOK, gomock.Any()).Return(nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get(IdempotentReplayedHeader))
+
+		var response presentation.TransactionDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, createdTransaction, response)
+	})
+
+	t.Run("Create transaction replays the response of the idempotency key", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+		storedResponse := []byte(`{"id":1,"description":"mock"}`)
+		mockIdempotencyService.EXPECT().Start(gomock.Any(), "key-1", fingerprint).Return(&model.IdempotencyRecord{
+			Key:          "key-1",
+			Fingerprint:  fingerprint,
+			Completed:    true,
+			StatusCode:   http.StatusOK,
+			ResponseBody: storedResponse,
+		}, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, storedResponse, rr.Body.Bytes())
+	})
+
+	t.Run("Create transaction replays the transaction of the idempotency key without the stored response", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+		mockIdempotencyService.EXPECT().Start(gomock.Any(), "key-1", fingerprint).Return(&model.IdempotencyRecord{
+			Key:           "key-1",
+			Fingerprint:   fingerprint,
+			Completed:     true,
+			StatusCode:    http.StatusOK,
+			TransactionID: 1,
+		}, nil)
+		mockService.EXPECT().GetTransactionByID(gomock.Any(), int64(1)).Return(&createdTransaction, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader))
+
+		var response presentation.TransactionDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, createdTransaction, response)
+	})
+
+	t.Run("Create transaction with an idempotency key reused with another body", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+		mockIdempotencyService.EXPECT().Start(gomock.Any(), "key-1", fingerprint).
+			Return(nil, model.NewIdempotencyKeyReuseError("idempotency key already used with a different request body"))
+
+		expectedError := &presentation.ApiError{
+			Code:      http.StatusUnprocessableEntity,
+			Message:   "idempotency key already used with a different request body",
+			ErrorCode: presentation.ErrorCodeIdempotencyKeyReused,
+		}
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+
+	t.Run("Create transaction releases the idempotency key when it fails", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+		mockIdempotencyService.EXPECT().Start(gomock.Any(), "key-1", fingerprint).Return(nil, nil)
+		mockService.EXPECT().SaveTransaction(gomock.Any(), transactionDTO.ToTransaction(), gomock.Any()).Return(nil, errors.New("database is locked"))
+		mockIdempotencyService.EXPECT().Release(gomock.Any(), "key-1").Return(nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, presentation.NewApiError(http.StatusInternalServerError, "internal server error"), rr)
+	})
+
+	t.Run("Create transaction logs the idempotency key not released with the logger of the request", func(t *testing.T) {
+		// Given
+		var output bytes.Buffer
+		requestLog := slog.New(slog.NewJSONHandler(&output, nil)).With("request_id", "request-1")
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(util.WithLogger(context.Background(), requestLog), "POST", "/transactions", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+		mockIdempotencyService.EXPECT().Start(gomock.Any(), "key-1", fingerprint).Return(nil, nil)
+		mockService.EXPECT().SaveTransaction(gomock.Any(), transactionDTO.ToTransaction(), gomock.Any()).Return(nil, errors.New("database is locked"))
+		mockIdempotencyService.EXPECT().Release(gomock.Any(), "key-1").Return(errors.New("database is locked"))
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Contains(t, output.String(), `"msg":"Error releasing idempotency key"`)
+		assert.Contains(t, output.String(), `"request_id":"request-1"`)
+	})
 }
 
 func Test_UpdateTransaction(t *testing.T) {
@@ -234,11 +427,11 @@ func Test_UpdateTransaction(t *testing.T) {
 	mockService := mock_service.NewMockTransactionService(mockController)
 
 	logger := slog.Default()
-	controller := NewTransactionController(logger, mockService)
+	controller := NewTransactionController(logger, mockService, nil)
 
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
-	router.HandleFunc("/transactions/{id}", controller.UpdateTransaction).Methods("PUT")
+	router.HandleFunc("/transactions/{id}", middleware.HandleErrors(controller.UpdateTransaction)).Methods("PUT")
 
 	t.Run("Update transaction with success", func(t *testing.T) {
 		// Given
@@ -246,29 +439,147 @@ func Test_UpdateTransaction(t *testing.T) {
 			TransactionID:   1,
 			Description:     "updated description",
 			TransactionDate: "2018-09-26T10:36:40Z",
-			PurchaseAmount:  2.0,
+			PurchaseAmount:  200,
 		}
+		updatedDTO := transactionDTO
+		updatedDTO.Version = 3
 
 		body, err := json.Marshal(transactionDTO)
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(body))
 		assert.NoError(t, err)
+		req.Header.Set(IfMatchHeader, `"2"`)
 
-		mockService.EXPECT().UpdateTransactionByID(int64(1), transactionDTO.ToTransaction()).Return(&transactionDTO)
+		mockService.EXPECT().UpdateTransactionByID(gomock.Any(), int64(1), transactionDTO.ToTransaction(), int64(2), gomock.Any()).Return(&updatedDTO, nil)
 
 		// When
 		router.ServeHTTP(rr, req)
 
 		// Then
 		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get(ETagHeader))
 		assert.NotNil(t, rr.Body.String())
 
 		var response presentation.TransactionDTO
 		err = json.Unmarshal(rr.Body.Bytes(), &response)
 
 		assert.NoError(t, err)
-		assert.Equal(t, transactionDTO, response)
+		assert.Equal(t, updatedDTO, response)
+	})
+
+	t.Run("Update transaction with error version does not match", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		transactionDTO := presentation.TransactionDTO{
+			Description:     "updated description",
+			TransactionDate: "2018-09-26T10:36:40Z",
+			PurchaseAmount:  200,
+		}
+
+		body, err := json.Marshal(transactionDTO)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set(IfMatchHeader, `"2"`)
+
+		mockService.EXPECT().UpdateTransactionByID(gomock.Any(), int64(1), transactionDTO.ToTransaction(), int64(2), gomock.Any()).
+			Return(nil, model.NewPreconditionFailedError("transaction was changed, If-Match does not match its current version"))
+
+		expectedError := &presentation.ApiError{
+			Code:      http.StatusPreconditionFailed,
+			Message:   "transaction was changed, If-Match does not match its current version",
+			ErrorCode: presentation.ErrorCodePreconditionFailed,
+		}
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+}
+
+func Test_PatchTransaction(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockTransactionService(mockController)
+
+	logger := slog.Default()
+	controller := NewTransactionController(logger, mockService, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/transactions/{id}", middleware.HandleErrors(controller.PatchTransaction)).Methods("PATCH")
+
+	t.Run("Patch transaction with success", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("PATCH", "/transactions/1", bytes.NewBufferString(`{"description":"fixed typo"}`))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", presentation.MergePatchContentType)
+		req.Header.Set(IfMatchHeader, `"1"`)
+
+		description := "fixed typo"
+		patchedDTO := presentation.TransactionDTO{
+			TransactionID:   1,
+			Description:     description,
+			TransactionDate: "2018-09-26T10:36:40Z",
+			PurchaseAmount:  200,
+			Version:         2,
+		}
+		mockService.EXPECT().PatchTransactionByID(gomock.Any(), int64(1), &model.TransactionPatch{Description: &description}, int64(1), gomock.Any()).Return(&patchedDTO, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"2"`, rr.Header().Get(ETagHeader))
+
+		var response presentation.TransactionDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, patchedDTO, response)
+	})
+
+	t.Run("Patch transaction with error unsupported content type", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("PATCH", "/transactions/1", bytes.NewBufferString(`{"description":"fixed typo"}`))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		expectedError := &presentation.ApiError{
+			Code:      http.StatusUnsupportedMediaType,
+			Message:   "the patch must be sent as application/merge-patch+json",
+			ErrorCode: presentation.ErrorCodeUnsupportedMedia,
+		}
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+		assert.Equal(t, presentation.MergePatchContentType, rr.Header().Get(AcceptPatchHeader))
+	})
+
+	t.Run("Patch transaction with error invalid field", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("PATCH", "/transactions/1", bytes.NewBufferString(`{"purchase_amount":0}`))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/merge-patch+json; charset=utf-8")
+
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid purchase amount, it must be greater than 0")
+		expectedError.InvalidParams = []presentation.InvalidParamDTO{
+			{Name: "purchase_amount", Reason: "invalid purchase amount, it must be greater than 0"},
+		}
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
 	})
 }
 
@@ -278,18 +589,18 @@ func Test_DeleteTransaction(t *testing.T) {
 	mockService := mock_service.NewMockTransactionService(mockController)
 
 	logger := slog.Default()
-	controller := NewTransactionController(logger, mockService)
+	controller := NewTransactionController(logger, mockService, nil)
 
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
-	router.HandleFunc("/transactions/{id}", controller.DeleteTransaction).Methods("DELETE")
+	router.HandleFunc("/transactions/{id}", middleware.HandleErrors(controller.DeleteTransaction)).Methods("DELETE")
 
 	t.Run("Delete transaction with success", func(t *testing.T) {
 		// Given
 		req, err := http.NewRequest("DELETE", "/transactions/1", nil)
 		assert.NoError(t, err)
 
-		mockService.EXPECT().DeleteTransactionByID(int64(1)).Times(1)
+		mockService.EXPECT().DeleteTransactionByID(gomock.Any(), int64(1), int64(0), gomock.Any()).Return(nil)
 
 		// When
 		router.ServeHTTP(rr, req)
@@ -297,10 +608,262 @@ func Test_DeleteTransaction(t *testing.T) {
 		// Then
 		assert.Equal(t, http.StatusNoContent, rr.Code)
 	})
+
+	t.Run("Delete transaction with If-Match", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("DELETE", "/transactions/1", nil)
+		assert.NoError(t, err)
+		req.Header.Set(IfMatchHeader, `"4"`)
+
+		mockService.EXPECT().DeleteTransactionByID(gomock.Any(), int64(1), int64(4), gomock.Any()).Return(nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("Delete transaction with error invalid If-Match", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("DELETE", "/transactions/1", nil)
+		assert.NoError(t, err)
+		req.Header.Set(IfMatchHeader, `"4", "5"`)
+
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "If-Match must be a single strong ETag, e.g. \"3\", or *")
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+
+	t.Run("Delete transaction with error weak If-Match", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("DELETE", "/transactions/1", nil)
+		assert.NoError(t, err)
+		req.Header.Set(IfMatchHeader, `W/"4"`)
+
+		expectedError := &presentation.ApiError{
+			Code:      http.StatusPreconditionFailed,
+			Message:   "If-Match does not match the current version of the transaction",
+			ErrorCode: presentation.ErrorCodePreconditionFailed,
+		}
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
 }
 
-func assertPanicErrors(t *testing.T, expectedError *presentation.ApiError) {
-	if r := recover(); r != nil {
-		assert.Equal(t, expectedError, r)
-	}
+func Test_RestoreTransaction(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockTransactionService(mockController)
+
+	logger := slog.Default()
+	controller := NewTransactionController(logger, mockService, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/transactions/{id}/restore", middleware.HandleErrors(controller.RestoreTransaction)).Methods("POST")
+
+	t.Run("Restore transaction with success", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/transactions/1/restore", nil)
+		assert.NoError(t, err)
+		req.Header.Set(ActorHeader, "alice")
+		req.Header.Set(middleware.RequestIDHeader, "request-1")
+
+		restoredDTO := presentation.TransactionDTO{TransactionID: 1, Version: 4, RestoredAt: "2025-05-10T12:00:00Z", RestoredBy: "alice"}
+		mockService.EXPECT().RestoreTransactionByID(gomock.Any(), int64(1), int64(0), model.Audit{Actor: "alice", RequestID: "request-1"}).Return(&restoredDTO, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"4"`, rr.Header().Get(ETagHeader))
+
+		var response presentation.TransactionDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, restoredDTO, response)
+	})
+
+	t.Run("Restore transaction without actor", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/transactions/1/restore", nil)
+		assert.NoError(t, err)
+		req.Header.Set(middleware.RequestIDHeader, "request-2")
+
+		mockService.EXPECT().RestoreTransactionByID(gomock.Any(), int64(1), int64(0), model.Audit{Actor: "anonymous", RequestID: "request-2"}).
+			Return(nil, model.NewConflictError("transaction is not deleted"))
+
+		expectedError := &presentation.ApiError{
+			Code:      http.StatusConflict,
+			Message:   "transaction is not deleted",
+			ErrorCode: presentation.ErrorCodeConflict,
+		}
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+}
+
+func Test_GetTransactionHistory(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockTransactionService(mockController)
+
+	logger := slog.Default()
+	controller := NewTransactionController(logger, mockService, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/transactions/{id}/history", middleware.HandleErrors(controller.GetTransactionHistory)).Methods("GET")
+
+	t.Run("Get transaction history as of a point in time", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/transactions/1/history?as_of=2025-05-10T12:00:00Z", nil)
+		assert.NoError(t, err)
+
+		asOf := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+		historyDTO := presentation.TransactionHistoryDTO{
+			TransactionID: 1,
+			Transaction:   &presentation.TransactionDTO{TransactionID: 1, Description: "mock", Version: 1},
+			Events:        []presentation.TransactionEventDTO{{EventID: 1, Operation: "create", Actor: "alice"}},
+		}
+		mockService.EXPECT().GetTransactionHistory(gomock.Any(), int64(1), &asOf).Return(&historyDTO, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response presentation.TransactionHistoryDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, historyDTO, response)
+	})
+
+	t.Run("Get transaction history with invalid as_of", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/transactions/1/history?as_of=yesterday", nil)
+		assert.NoError(t, err)
+
+		expectedError := &presentation.ApiError{
+			Code:      http.StatusBadRequest,
+			Message:   "invalid as_of: invalid date format expected 2006-01-02T15:04:05Z07:00",
+			ErrorCode: presentation.ErrorCodeValidation,
+		}
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+}
+
+func Test_ListTransactions(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockTransactionService(mockController)
+	logger := slog.Default()
+	controller := NewTransactionController(logger, mockService, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/transactions", middleware.HandleErrors(controller.ListTransactions)).Methods("GET")
+
+	t.Run("List transactions with success and pagination links", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/transactions?description=mock&limit=2&offset=2", nil)
+		assert.NoError(t, err)
+
+		expectedFilter := &model.TransactionFilter{Description: "mock", Limit: 2, Offset: 2}
+		mockService.EXPECT().ListTransactions(gomock.Any(), expectedFilter).Return(&presentation.TransactionPageDTO{
+			Data:   []presentation.TransactionDTO{{TransactionID: 3}, {TransactionID: 4}},
+			Total:  5,
+			Limit:  2,
+			Offset: 2,
+		}, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response presentation.TransactionPageDTO
+		err = json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Data, 2)
+		assert.Equal(t, int64(5), response.Total)
+		assert.Equal(t, "/transactions?description=mock&limit=2&offset=2", response.Links.Self)
+		assert.Equal(t, "/transactions?description=mock&limit=2&offset=4", response.Links.Next)
+		assert.Equal(t, "/transactions?description=mock&limit=2&offset=0", response.Links.Prev)
+	})
+
+	t.Run("List transactions last page without next link", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/transactions", nil)
+		assert.NoError(t, err)
+
+		expectedFilter := &model.TransactionFilter{Limit: presentation.DefaultPageLimit}
+		mockService.EXPECT().ListTransactions(gomock.Any(), expectedFilter).Return(&presentation.TransactionPageDTO{
+			Data:  []presentation.TransactionDTO{{TransactionID: 1}},
+			Total: 1,
+			Limit: presentation.DefaultPageLimit,
+		}, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response presentation.TransactionPageDTO
+		err = json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Empty(t, response.Links.Next)
+		assert.Empty(t, response.Links.Prev)
+	})
+
+	t.Run("List transactions with error invalid limit", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/transactions?limit=0", nil)
+		assert.NoError(t, err)
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid limit, it must be between 1 and 100")
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+}
+
+func assertApiError(t *testing.T, expectedError *presentation.ApiError, rr *httptest.ResponseRecorder) {
+	var problem presentation.ProblemDetailsDTO
+	err := json.Unmarshal(rr.Body.Bytes(), &problem)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedError.Code, rr.Code)
+	assert.Equal(t, presentation.ProblemContentType, rr.Header().Get("Content-Type"))
+	assert.NotEmpty(t, problem.RequestID)
+	assert.Equal(t, *presentation.NewProblemDetailsDTO(expectedError, problem.Instance, rr.Header().Get("X-Request-ID")), problem)
 }
@@ -2,50 +2,181 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/service"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
 )
 
 type TransactionCurrencyController struct {
 	service service.TransactionCurrencyService
-	log     *slog.Logger
+	// writeTimeout is renewed for every line of a streamed response, so a long batch is not cut by the deadline
+	// of the whole response while a stalled client still is
+	writeTimeout time.Duration
+	log          *slog.Logger
 }
 
 func NewTransactionCurrencyController(
 	service service.TransactionCurrencyService,
+	writeTimeout time.Duration,
 	log *slog.Logger) *TransactionCurrencyController {
 
 	return &TransactionCurrencyController{
-		service: service,
-		log:     log,
+		service:      service,
+		writeTimeout: writeTimeout,
+		log:          log,
 	}
 }
 
-func (c *TransactionCurrencyController) GetTransactionCurrency(w http.ResponseWriter, r *http.Request) {
-	transactionID := c.validateTransactionID(r)
-	country := c.validateCountryName(r)
+func (c *TransactionCurrencyController) GetTransactionCurrency(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := c.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
+
+	country, err := c.validateCountryName(r)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.service.GetTransactionCurrencyConverted(r.Context(), transactionID, country)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func (c *TransactionCurrencyController) GetTransactionCurrencies(w http.ResponseWriter, r *http.Request) error {
+	transactionID, err := c.validateTransactionID(r)
+	if err != nil {
+		return err
+	}
+
+	countries, err := c.validateCountryNames(r)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.service.GetTransactionCurrenciesConverted(r.Context(), transactionID, countries)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// ConvertTransactionsCurrency streams the conversion of each transaction as a line of JSON (NDJSON). An error
+// after the first line cannot change the response status anymore, so it ends the stream with a line that has only
+// the error, which tells a truncated report from a complete one
+func (c *TransactionCurrencyController) ConvertTransactionsCurrency(w http.ResponseWriter, r *http.Request) error {
+	country, err := c.validateCountryName(r)
+	if err != nil {
+		return err
+	}
+
+	batchDTO, err := c.decodeBatchConversionDTO(r)
+	if err != nil {
+		return err
+	}
+
+	log := util.Logger(r.Context(), c.log)
+	encoder := json.NewEncoder(w)
+	responseController := http.NewResponseController(w)
+	streaming := false
+
+	writeLine := func(result presentation.TransactionCurrencyResultDTO) {
+		c.renewWriteDeadline(r, responseController)
+
+		if err := encoder.Encode(result); err != nil {
+			log.Error("error writing conversion result", "transaction_id", result.TransactionID, "error", err)
+			return
+		}
+
+		if err := responseController.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Error("error flushing conversion result", "transaction_id", result.TransactionID, "error", err)
+		}
+	}
 
-	response := c.service.GetTransactionCurrencyConverted(r.Context(), transactionID, country)
+	err = c.service.ConvertTransactionsCurrency(r.Context(), country, batchDTO.TransactionIDs, batchDTO.ToTransactionFilter(), func(result presentation.TransactionCurrencyResultDTO) {
+		if !streaming {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			streaming = true
+		}
 
-	json.NewEncoder(w).Encode(response)
+		writeLine(result)
+	})
+
+	if err != nil && streaming {
+		log.Error("error converting transactions, ending the stream", "country", country, "error", err)
+		writeLine(presentation.TransactionCurrencyResultDTO{Country: country, Error: presentation.NewApiErrorFromError(err)})
+		return nil
+	}
+
+	if err == nil && !streaming {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+
+	return err
+}
+
+// renewWriteDeadline gives the next line of the stream the write timeout of a whole response
+func (c *TransactionCurrencyController) renewWriteDeadline(r *http.Request, responseController *http.ResponseController) {
+	if c.writeTimeout <= 0 {
+		return
+	}
+
+	if err := responseController.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		util.Logger(r.Context(), c.log).Error("error renewing the write deadline of the stream", "error", err)
+	}
 }
 
-func (t *TransactionCurrencyController) validateTransactionID(r *http.Request) int64 {
+func (t *TransactionCurrencyController) validateTransactionID(r *http.Request) (int64, error) {
 	params := mux.Vars(r)
 	transactionID := presentation.TransactionID(params["id"])
 
-	transactionID.Validate()
-	return transactionID.Get()
+	if err := transactionID.Validate(); err != nil {
+		return 0, err
+	}
+
+	return transactionID.Get(), nil
 }
 
-func (c *TransactionCurrencyController) validateCountryName(r *http.Request) string {
+func (c *TransactionCurrencyController) validateCountryName(r *http.Request) (string, error) {
 	params := mux.Vars(r)
 	country := presentation.Country(params["country"])
 
-	country.Validate()
+	if err := country.Validate(); err != nil {
+		return "", err
+	}
+
 	return country.Normalize()
 }
+
+func (c *TransactionCurrencyController) validateCountryNames(r *http.Request) ([]string, error) {
+	countries := presentation.Countries(r.URL.Query().Get("countries"))
+	if err := countries.Validate(); err != nil {
+		return nil, err
+	}
+
+	return countries.Normalize()
+}
+
+func (c *TransactionCurrencyController) decodeBatchConversionDTO(r *http.Request) (*presentation.BatchConversionDTO, error) {
+	var batchDTO presentation.BatchConversionDTO
+	if err := json.NewDecoder(r.Body).Decode(&batchDTO); err != nil {
+		return nil, model.NewValidationError("Error decoding request body: " + err.Error())
+	}
+
+	if err := batchDTO.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &batchDTO, nil
+}
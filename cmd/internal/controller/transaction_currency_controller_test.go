@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/middleware"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	mock_service "github.com/pablorodrigo52/transaction-api/cmd/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetTransactionCurrency(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockTransactionCurrencyService(mockController)
+
+	controller := NewTransactionCurrencyController(mockService, time.Minute, slog.Default())
+
+	router := mux.NewRouter()
+	router.HandleFunc("/converter/transaction/{id}/currency/{country}", middleware.HandleErrors(controller.GetTransactionCurrency))
+
+	t.Run("Get transaction currency with success", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/converter/transaction/1/currency/brazil", nil)
+		assert.NoError(t, err)
+
+		expectedResponse := presentation.TransactionCurrencyDTO{TransactionID: 1, ExchangeRate: "6.18", ConvertedPurchaseAmount: 618}
+		mockService.EXPECT().GetTransactionCurrencyConverted(gomock.Any(), int64(1), "Brazil").Return(&expectedResponse, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response presentation.TransactionCurrencyDTO
+		err = json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResponse, response)
+	})
+
+	t.Run("Get transaction currency with error rate not found", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/converter/transaction/1/currency/Brazil", nil)
+		assert.NoError(t, err)
+
+		expectedError := &presentation.ApiError{Code: http.StatusBadGateway, Message: "no rate", ErrorCode: presentation.ErrorCodeExchangeRateNotFound}
+		mockService.EXPECT().GetTransactionCurrencyConverted(gomock.Any(), int64(1), "Brazil").Return(nil, model.NewRateNotFoundError("no rate"))
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+}
+
+func Test_ConvertTransactionsCurrency(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockTransactionCurrencyService(mockController)
+
+	controller := NewTransactionCurrencyController(mockService, time.Minute, slog.Default())
+
+	router := mux.NewRouter()
+	router.HandleFunc("/converter/currency/{country}", middleware.HandleErrors(controller.ConvertTransactionsCurrency)).Methods("POST")
+
+	emitResults := func(results ...presentation.TransactionCurrencyResultDTO) func(any, any, any, any, func(presentation.TransactionCurrencyResultDTO)) {
+		return func(_, _, _, _ any, emit func(presentation.TransactionCurrencyResultDTO)) {
+			for _, result := range results {
+				emit(result)
+			}
+		}
+	}
+
+	t.Run("Convert transactions currency streams one line per transaction", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/converter/currency/Brazil", bytes.NewBufferString(`{"transaction_ids":[1,2]}`))
+		assert.NoError(t, err)
+
+		mockService.EXPECT().ConvertTransactionsCurrency(gomock.Any(), "Brazil", []int64{1, 2}, nil, gomock.Any()).
+			Do(emitResults(
+				presentation.TransactionCurrencyResultDTO{TransactionID: 1, Country: "Brazil", Conversion: &presentation.TransactionCurrencyDTO{TransactionID: 1}},
+				presentation.TransactionCurrencyResultDTO{TransactionID: 2, Country: "Brazil", Error: presentation.NewApiError(http.StatusNotFound, "transaction not found")},
+			)).
+			Return(nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+
+		decoder := json.NewDecoder(rr.Body)
+		var first, second presentation.TransactionCurrencyResultDTO
+		assert.NoError(t, decoder.Decode(&first))
+		assert.NoError(t, decoder.Decode(&second))
+		assert.Equal(t, int64(1), first.TransactionID)
+		assert.Equal(t, presentation.NewApiError(http.StatusNotFound, "transaction not found"), second.Error)
+	})
+
+	t.Run("Convert transactions currency with error before streaming", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/converter/currency/Brazil", bytes.NewBufferString(`{"transaction_ids":[1]}`))
+		assert.NoError(t, err)
+
+		expectedError := presentation.NewApiError(http.StatusInternalServerError, "internal server error")
+		mockService.EXPECT().ConvertTransactionsCurrency(gomock.Any(), "Brazil", []int64{1}, nil, gomock.Any()).Return(errors.New("database error"))
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+
+	t.Run("Convert transactions currency with error after streaming keeps the results and ends with the error", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/converter/currency/Brazil", bytes.NewBufferString(`{"transaction_ids":[1]}`))
+		assert.NoError(t, err)
+
+		mockService.EXPECT().ConvertTransactionsCurrency(gomock.Any(), "Brazil", []int64{1}, nil, gomock.Any()).
+			Do(emitResults(presentation.TransactionCurrencyResultDTO{TransactionID: 1, Country: "Brazil"})).
+			Return(errors.New("database error"))
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		decoder := json.NewDecoder(rr.Body)
+		var first, last presentation.TransactionCurrencyResultDTO
+		assert.NoError(t, decoder.Decode(&first))
+		assert.NoError(t, decoder.Decode(&last))
+		assert.False(t, decoder.More())
+		assert.Equal(t, presentation.TransactionCurrencyResultDTO{TransactionID: 1, Country: "Brazil"}, first)
+		assert.Equal(t, presentation.TransactionCurrencyResultDTO{Country: "Brazil", Error: presentation.NewApiError(http.StatusInternalServerError, "internal server error")}, last)
+	})
+
+	t.Run("Convert transactions currency streams for longer than the write timeout of the server", func(t *testing.T) {
+		// Given
+		streamController := NewTransactionCurrencyController(mockService, 200*time.Millisecond, slog.Default())
+		streamRouter := mux.NewRouter()
+		streamRouter.HandleFunc("/converter/currency/{country}", middleware.HandleErrors(streamController.ConvertTransactionsCurrency)).Methods("POST")
+		server := httptest.NewUnstartedServer(streamRouter)
+		server.Config.WriteTimeout = 200 * time.Millisecond
+		server.Start()
+		defer server.Close()
+
+		mockService.EXPECT().ConvertTransactionsCurrency(gomock.Any(), "Brazil", []int64{1, 2, 3, 4, 5}, nil, gomock.Any()).
+			Do(func(_, _, transactionIDs, _ any, emit func(presentation.TransactionCurrencyResultDTO)) {
+				for _, transactionID := range transactionIDs.([]int64) {
+					time.Sleep(100 * time.Millisecond)
+					emit(presentation.TransactionCurrencyResultDTO{TransactionID: transactionID, Country: "Brazil"})
+				}
+			}).
+			Return(nil)
+
+		// When
+		client := &http.Client{Timeout: 5 * time.Second}
+		response, err := client.Post(server.URL+"/converter/currency/Brazil", "application/json", bytes.NewBufferString(`{"transaction_ids":[1,2,3,4,5]}`))
+
+		// Then
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		lines := 0
+		decoder := json.NewDecoder(response.Body)
+		for decoder.More() {
+			var result presentation.TransactionCurrencyResultDTO
+			if !assert.NoError(t, decoder.Decode(&result)) {
+				break
+			}
+			lines++
+		}
+		assert.Equal(t, 5, lines)
+	})
+
+	t.Run("Convert transactions currency with error invalid request body", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/converter/currency/Brazil", bytes.NewBufferString(`{}`))
+		assert.NoError(t, err)
+
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "either transaction_ids or transaction_date_from and transaction_date_to must be informed")
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, expectedError, rr)
+	})
+}
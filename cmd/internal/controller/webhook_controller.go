@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/service"
+)
+
+type WebhookController struct {
+	service service.WebhookService
+	log     *slog.Logger
+}
+
+func NewWebhookController(log *slog.Logger, service service.WebhookService) *WebhookController {
+	return &WebhookController{
+		service: service,
+		log:     log,
+	}
+}
+
+func (c *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) error {
+	var subscriptionDTO presentation.WebhookSubscriptionDTO
+	if err := json.NewDecoder(r.Body).Decode(&subscriptionDTO); err != nil {
+		return model.NewValidationError("Error decoding request body: " + err.Error())
+	}
+
+	if err := subscriptionDTO.Validate(); err != nil {
+		return err
+	}
+
+	subscription, err := c.service.CreateSubscription(subscriptionDTO.ToWebhookSubscription())
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(subscription)
+}
+
+func (c *WebhookController) GetWebhook(w http.ResponseWriter, r *http.Request) error {
+	webhookID, err := c.validateWebhookID(r)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := c.service.GetSubscription(webhookID)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(subscription)
+}
+
+func (c *WebhookController) ListWebhooks(w http.ResponseWriter, r *http.Request) error {
+	subscriptions, err := c.service.ListSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(subscriptions)
+}
+
+func (c *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) error {
+	webhookID, err := c.validateWebhookID(r)
+	if err != nil {
+		return err
+	}
+
+	if err := c.service.DeleteSubscription(webhookID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// EnableWebhook reactivates a webhook disabled after too many failed deliveries
+func (c *WebhookController) EnableWebhook(w http.ResponseWriter, r *http.Request) error {
+	webhookID, err := c.validateWebhookID(r)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := c.service.EnableSubscription(webhookID)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(subscription)
+}
+
+// ListWebhookDeliveries returns the delivery log of a webhook, the newest deliveries first
+func (c *WebhookController) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) error {
+	webhookID, err := c.validateWebhookID(r)
+	if err != nil {
+		return err
+	}
+
+	pageDTO := presentation.NewPageDTO(r.URL.Query())
+	if err := pageDTO.Validate(); err != nil {
+		return err
+	}
+
+	limit, offset := pageDTO.Get()
+	page, err := c.service.ListDeliveries(webhookID, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	page.Links = buildPageLinks(r.URL, page.Limit, page.Offset, page.Total)
+	return json.NewEncoder(w).Encode(page)
+}
+
+func (c *WebhookController) validateWebhookID(r *http.Request) (int64, error) {
+	webhookID := presentation.WebhookID(mux.Vars(r)["id"])
+
+	if err := webhookID.Validate(); err != nil {
+		return 0, err
+	}
+
+	return webhookID.Get(), nil
+}
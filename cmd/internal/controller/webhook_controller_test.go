@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/middleware"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	mock_service "github.com/pablorodrigo52/transaction-api/cmd/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WebhookController(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockService := mock_service.NewMockWebhookService(mockController)
+
+	controller := NewWebhookController(slog.Default(), mockService)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/webhooks", middleware.HandleErrors(controller.CreateWebhook)).Methods("POST")
+	router.HandleFunc("/webhooks", middleware.HandleErrors(controller.ListWebhooks)).Methods("GET")
+	router.HandleFunc("/webhooks/{id}", middleware.HandleErrors(controller.GetWebhook)).Methods("GET")
+	router.HandleFunc("/webhooks/{id}", middleware.HandleErrors(controller.DeleteWebhook)).Methods("DELETE")
+	router.HandleFunc("/webhooks/{id}/enable", middleware.HandleErrors(controller.EnableWebhook)).Methods("POST")
+	router.HandleFunc("/webhooks/{id}/deliveries", middleware.HandleErrors(controller.ListWebhookDeliveries)).Methods("GET")
+
+	webhook := &presentation.WebhookSubscriptionDTO{WebhookID: 3, URL: "https://example.com/hook", Events: []string{"transaction.created"}, Active: true}
+
+	t.Run("CreateWebhook with success", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"url":"https://example.com/hook","events":["transaction.created"],"secret":"0123456789abcdef"}`))
+
+		mockService.EXPECT().CreateSubscription(&model.WebhookSubscription{
+			URL:        "https://example.com/hook",
+			EventTypes: []model.EventType{model.EventTransactionCreated},
+			Secret:     "0123456789abcdef",
+		}).Return(webhook, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.JSONEq(t, `{"webhook_id":3,"url":"https://example.com/hook","events":["transaction.created"],"active":true,"consecutive_failures":0,"created_at":""}`, rr.Body.String())
+	})
+
+	t.Run("CreateWebhook with every invalid field", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"url":"ftp://example.com","events":["transaction.purged"],"secret":"short"}`))
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		var problem presentation.ProblemDetailsDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, []presentation.InvalidParamDTO{
+			{Name: "url", Reason: "invalid url, it must be an absolute http or https URL"},
+			{Name: "events", Reason: `invalid event "transaction.purged"`},
+			{Name: "secret", Reason: "invalid secret, it must be between 16 and 255 characters"},
+		}, problem.InvalidParams)
+	})
+
+	t.Run("GetWebhook not found", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/webhooks/4", nil)
+
+		mockService.EXPECT().GetSubscription(int64(4)).Return(nil, model.NewNotFoundError("webhook not found"))
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, presentation.NewApiError(http.StatusNotFound, "webhook not found"), rr)
+	})
+
+	t.Run("GetWebhook with invalid id", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/webhooks/mock", nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, presentation.NewApiError(http.StatusBadRequest, "webhook ID must be a valid number"), rr)
+	})
+
+	t.Run("ListWebhooks with success", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/webhooks", nil)
+
+		mockService.EXPECT().ListSubscriptions().Return([]presentation.WebhookSubscriptionDTO{*webhook}, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		var response []presentation.WebhookSubscriptionDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []presentation.WebhookSubscriptionDTO{*webhook}, response)
+	})
+
+	t.Run("DeleteWebhook with success", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/webhooks/3", nil)
+
+		mockService.EXPECT().DeleteSubscription(int64(3)).Return(nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("EnableWebhook with success", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/webhooks/3/enable", nil)
+
+		mockService.EXPECT().EnableSubscription(int64(3)).Return(webhook, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("ListWebhookDeliveries with page links", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/webhooks/3/deliveries?limit=1", nil)
+
+		mockService.EXPECT().ListDeliveries(int64(3), 1, 0).Return(&presentation.WebhookDeliveryPageDTO{
+			Data:  []presentation.WebhookDeliveryDTO{{DeliveryID: 9, EventID: 7, EventType: "transaction.created", Status: "delivered", Attempts: 1}},
+			Total: 2,
+			Limit: 1,
+		}, nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		var response presentation.WebhookDeliveryPageDTO
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, presentation.PageLinksDTO{Self: "/webhooks/3/deliveries?limit=1&offset=0", Next: "/webhooks/3/deliveries?limit=1&offset=1"}, response.Links)
+	})
+
+	t.Run("ListWebhookDeliveries with invalid limit", func(t *testing.T) {
+		// Given
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/webhooks/3/deliveries?limit=0", nil)
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		assertApiError(t, presentation.NewApiError(http.StatusBadRequest, "invalid limit, it must be between 1 and 100"), rr)
+	})
+}
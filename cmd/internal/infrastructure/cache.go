@@ -1,26 +1,34 @@
 package infrastructure
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/dgraph-io/ristretto"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
 )
 
 type Cache struct {
-	Cache *ristretto.Cache
+	Cache          *ristretto.Cache
+	transactionTTL time.Duration
 }
 
-func NewCache() *Cache {
+func NewCache(cfg config.Cache) (*Cache, error) {
 
 	cache, err := ristretto.NewCache(&ristretto.Config{
-		NumCounters: 1e7,     // number of keys to track frequency of (10M).
-		MaxCost:     1 << 30, // maximum cost of cache (1GB).
-		BufferItems: 64,      // number of keys per Get buffer.
+		NumCounters: cfg.NumCounters,
+		MaxCost:     cfg.MaxCost,
+		BufferItems: cfg.BufferItems,
+		// exposed in /metrics
+		Metrics: true,
 	})
 
 	if err != nil {
-		panic("Failed to create cache " + err.Error())
+		return nil, fmt.Errorf("failed to create cache: %w", err)
 	}
 
 	return &Cache{
-		Cache: cache,
-	}
+		Cache:          cache,
+		transactionTTL: cfg.TransactionTTL,
+	}, nil
 }
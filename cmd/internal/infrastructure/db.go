@@ -2,33 +2,114 @@ package infrastructure
 
 import (
 	"database/sql"
-	"net/http"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
-	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
 )
 
 type DB struct {
-	Database *sql.DB
+	Database     *sql.DB
+	readTimeout  time.Duration
+	writeTimeout time.Duration
 }
 
-func NewDBClient() *DB {
-	db, err := sql.Open("sqlite3", "../db/transactions.db")
+func NewDBClient(cfg config.Database) (*DB, error) {
+	db, err := sql.Open("sqlite3", dataSourceName(cfg.Path))
 	if err != nil {
-		panic(presentation.NewApiError(http.StatusInternalServerError, "Failed to open database: "+err.Error()))
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	initScript, err := os.ReadFile("../scripts/init.sql")
+	initScript, err := os.ReadFile(cfg.InitScript)
 	if err != nil {
-		panic(presentation.NewApiError(http.StatusInternalServerError, "Failed to read init.sql: "+err.Error()))
+		return nil, fmt.Errorf("failed to read init.sql: %w", err)
 	}
 
 	if _, err := db.Exec(string(initScript)); err != nil {
-		panic(presentation.NewApiError(http.StatusInternalServerError, "Failed to execute init.sql: "+err.Error()))
+		return nil, fmt.Errorf("failed to execute init.sql: %w", err)
+	}
+
+	if err := runMigrations(db, cfg.MigrationsDir); err != nil {
+		return nil, fmt.Errorf("failed to execute migrations: %w", err)
 	}
 
 	return &DB{
-		Database: db,
+		Database:     db,
+		readTimeout:  cfg.ReadTimeout,
+		writeTimeout: cfg.WriteTimeout,
+	}, nil
+}
+
+// dataSourceName opens the transactions with BEGIN IMMEDIATE, so concurrent writers wait for each other up to the
+// busy timeout, a deferred transaction that read first fails right away when it cannot take the write lock
+func dataSourceName(path string) string {
+	return path + "?_txlock=immediate&_busy_timeout=5000"
+}
+
+// runMigrations applies, in order, the scripts named <version>_<description>.sql whose version is greater
+// than the database user_version, each one in its own transaction
+func runMigrations(db *sql.DB, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+
+	migrations := map[int]string{}
+	versions := []int{}
+	for _, file := range files {
+		version, err := strconv.Atoi(strings.SplitN(filepath.Base(file), "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("invalid migration file name %s", filepath.Base(file))
+		}
+
+		migrations[version] = file
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	var currentVersion int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&currentVersion); err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if version <= currentVersion {
+			continue
+		}
+
+		if err := applyMigration(db, version, migrations[version]); err != nil {
+			return fmt.Errorf("migration %s: %w", filepath.Base(migrations[version]), err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(db *sql.DB, version int, file string) error {
+	script, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(string(script)); err != nil {
+		return err
 	}
+
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
@@ -0,0 +1,118 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RunMigrations(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	initScript, err := os.ReadFile("../../../scripts/init.sql")
+	assert.NoError(t, err)
+	_, err = db.Exec(string(initScript))
+	assert.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO transactions (description, transaction_date, purchase_amount) VALUES ('mock', '2024-01-01T00:00:00Z', 123456.78)")
+	assert.NoError(t, err)
+
+	t.Run("Run migrations converting purchase amount to cents", func(t *testing.T) {
+		err := runMigrations(db, "../../../scripts/migrations")
+		assert.NoError(t, err)
+
+		var purchaseAmount int64
+		err = db.QueryRow("SELECT purchase_amount FROM transactions WHERE description = 'mock'").Scan(&purchaseAmount)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12345678), purchaseAmount)
+	})
+
+	t.Run("Run migrations twice does not apply them again", func(t *testing.T) {
+		err := runMigrations(db, "../../../scripts/migrations")
+		assert.NoError(t, err)
+
+		var purchaseAmount int64
+		err = db.QueryRow("SELECT purchase_amount FROM transactions WHERE description = 'mock'").Scan(&purchaseAmount)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12345678), purchaseAmount)
+	})
+
+	t.Run("Run migrations records the baseline of the existing transactions in an append-only history", func(t *testing.T) {
+		var operation, newValue string
+		err := db.QueryRow("SELECT operation, new_value FROM transaction_events WHERE transaction_id = 1").Scan(&operation, &newValue)
+		assert.NoError(t, err)
+		assert.Equal(t, "baseline", operation)
+		assert.JSONEq(t, `{"description":"mock","transaction_date":"2024-01-01T00:00:00Z","purchase_amount":12345678,"deleted":false,"version":1,"deleted_at":null,"restored_at":null,"restored_by":null}`, newValue)
+
+		_, err = db.Exec("DELETE FROM transaction_events")
+		assert.EqualError(t, err, "transaction_events is append-only")
+	})
+
+	t.Run("Run migrations error invalid file name", func(t *testing.T) {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "invalid.sql"), []byte("SELECT 1;"), 0o600)
+		assert.NoError(t, err)
+
+		err = runMigrations(db, dir)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid migration file name invalid.sql", err.Error())
+	})
+}
+
+func Test_NewDBClient_ConcurrentWriters(t *testing.T) {
+	// Given
+	database, err := NewDBClient(config.Database{
+		Path:          filepath.Join(t.TempDir(), "transactions.db"),
+		InitScript:    "../../../scripts/init.sql",
+		MigrationsDir: "../../../scripts/migrations",
+	})
+	assert.NoError(t, err)
+	defer database.Database.Close()
+
+	transactionRepository := repository.NewTransactionRepository(slog.Default(), database.Database)
+	ctx := context.Background()
+	transaction, err := transactionRepository.SaveTransaction(ctx, &model.Transaction{
+		Description:     "mock",
+		TransactionDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PurchaseAmount:  100,
+	}, model.Audit{})
+	assert.NoError(t, err)
+
+	// When
+	const writers = 60
+	errs := make(chan error, writers)
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			amount := int64(i)
+			_, err := transactionRepository.PatchTransaction(ctx, transaction.ID, &model.TransactionPatch{PurchaseAmount: &amount}, 0, model.Audit{})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Then
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	var version int64
+	assert.NoError(t, database.Database.QueryRow("SELECT version FROM transactions WHERE id = ?", transaction.ID).Scan(&version))
+	assert.Equal(t, int64(writers+1), version)
+}
@@ -1,40 +1,112 @@
 package infrastructure
 
 import (
+	"net/http"
+
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/controller"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/metrics"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/service"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Dependencies struct {
 	PingController                controller.PingController
+	HealthController              *controller.HealthController
 	TransactionController         controller.TransactionController
 	TransactionCurrencyController controller.TransactionCurrencyController
+	WebhookController             controller.WebhookController
+	LogLevelController            *controller.LogLevelController
+	TreasurySyncService           *service.TreasurySyncServiceImpl
+	TransactionPurgeService       *service.TransactionPurgeServiceImpl
+	OutboxDispatcher              *service.OutboxDispatcherImpl
+	WebhookDeliveryService        *service.WebhookDeliveryServiceImpl
 }
 
 func InitDependencies(infrastructure *Infrastructure) *Dependencies {
 
 	// repositories
-	transactionRepository := repository.NewTransactionRepository(infrastructure.Log, infrastructure.Database.Database)
-	transactionCache := repository.NewTransactionCache(infrastructure.Cache.Cache)
-	treasuryRepository := repository.NewTreasuryRepository(
+	transactionRepository := repository.NewTransactionRepositoryMetrics(
+		repository.NewTransactionRepositoryTracing(repository.NewTransactionRepositoryTimeout(
+			repository.NewTransactionRepository(infrastructure.Log, infrastructure.Database.Database),
+			infrastructure.Database.readTimeout,
+			infrastructure.Database.writeTimeout)),
+		infrastructure.Metrics.RepositoryDuration)
+	transactionCache := repository.NewTransactionCache(infrastructure.Cache.Cache, infrastructure.Cache.transactionTTL)
+	treasuryClientRepository := repository.NewTreasuryRepository(
 		infrastructure.TreasuryClient.domain,
 		infrastructure.TreasuryClient.path,
 		infrastructure.TreasuryClient.timeout,
+		// one span per call, with every attempt observed by the metrics
+		otelhttp.NewTransport(
+			repository.NewResilientTransport(
+				metrics.NewTreasuryTransport(nil, infrastructure.Metrics),
+				infrastructure.TreasuryClient.breaker,
+				infrastructure.TreasuryClient.limiter,
+				infrastructure.TreasuryClient.maxRetries,
+				infrastructure.TreasuryClient.attemptTimeout,
+				infrastructure.TreasuryClient.retryBaseDelay,
+				infrastructure.TreasuryClient.retryMaxDelay),
+			otelhttp.WithSpanNameFormatter(treasurySpanName)),
+		infrastructure.Log)
+	exchangeRateRepository := repository.NewExchangeRateRepository(infrastructure.Log, infrastructure.Database.Database)
+	exchangeRateProviders := map[string]repository.ExchangeRateProvider{
+		model.ExchangeRateSourceDatabase: exchangeRateRepository,
+		model.ExchangeRateSourceTreasury: repository.NewTreasuryExchangeRateProvider(treasuryClientRepository),
+		model.ExchangeRateSourceFile:     infrastructure.ExchangeRates.file,
+	}
+	// the providers are asked in the configured order, the rate they supply is cached whatever its source
+	exchangeRateChain := []repository.ExchangeRateProvider{}
+	for _, name := range infrastructure.ExchangeRates.providers {
+		exchangeRateChain = append(exchangeRateChain, exchangeRateProviders[name])
+	}
+	exchangeRateProvider := repository.NewExchangeRateCache(infrastructure.Cache.Cache,
+		repository.NewExchangeRateChain(infrastructure.Log, exchangeRateChain...),
+		infrastructure.Metrics.ExchangeRateCacheLookups,
 		infrastructure.Log)
+	idempotencyRepository := repository.NewIdempotencyRepository(infrastructure.Log, infrastructure.Database.Database)
+	outboxRepository := repository.NewOutboxRepository(infrastructure.Log, infrastructure.Database.Database)
+	webhookRepository := repository.NewWebhookRepository(infrastructure.Log, infrastructure.Database.Database)
+	webhookClient := repository.NewWebhookClient(infrastructure.Webhooks.timeout)
 
 	// services
 	transactionService := service.NewTransactionService(infrastructure.Log, transactionRepository, transactionCache)
-	transactionCurrencyService := service.NewTransactionCurrencyService(treasuryRepository, transactionRepository, infrastructure.Log)
+	transactionCurrencyService := service.NewTransactionCurrencyService(exchangeRateProvider, transactionRepository, infrastructure.Metrics.ConversionsRejected, infrastructure.Log)
+	idempotencyService := service.NewIdempotencyService(infrastructure.Log, idempotencyRepository, infrastructure.Database.writeTimeout)
+	treasurySyncService := service.NewTreasurySyncService(treasuryClientRepository, exchangeRateRepository, infrastructure.TreasuryClient.syncInterval, infrastructure.Log)
+	transactionPurgeService := service.NewTransactionPurgeService(transactionRepository, transactionCache, infrastructure.Purge.retention, infrastructure.Purge.interval, infrastructure.Log)
+	webhookService := service.NewWebhookService(infrastructure.Log, webhookRepository)
+	webhookDeliveryService := service.NewWebhookDeliveryService(webhookRepository, webhookClient, infrastructure.Webhooks.interval, infrastructure.Webhooks.maxAttempts, infrastructure.Webhooks.disableAfter, infrastructure.Log)
+	// the events go to the configured publisher and to the webhooks subscribed to them
+	eventPublisher := repository.NewMultiEventPublisher(infrastructure.Outbox.publisher, webhookService)
+	outboxDispatcher := service.NewOutboxDispatcher(outboxRepository, eventPublisher, infrastructure.Outbox.interval, infrastructure.Outbox.maxAttempts, infrastructure.Log)
+	// the server works without the cache and with the other exchange rate providers when the treasury api is down
+	healthService := service.NewHealthService(infrastructure.Health.timeout, infrastructure.Log)
+	healthService.Register("database", repository.NewDatabaseHealthChecker(infrastructure.Database.Database), true)
+	healthService.Register("cache", repository.NewCacheHealthChecker(infrastructure.Cache.Cache), false)
+	healthService.Register("treasury", repository.NewCircuitBreakerHealthChecker(infrastructure.TreasuryClient.breaker), false)
 
 	// controllers
 	pingController := controller.NewPingController()
-	transactionController := controller.NewTransactionController(infrastructure.Log, transactionService)
-	transactionCurrencyController := controller.NewTransactionCurrencyController(transactionCurrencyService, infrastructure.Log)
+	transactionController := controller.NewTransactionController(infrastructure.Log, transactionService, idempotencyService)
+	transactionCurrencyController := controller.NewTransactionCurrencyController(transactionCurrencyService, infrastructure.Router.WriteTimeout, infrastructure.Log)
+	webhookController := controller.NewWebhookController(infrastructure.Log, webhookService)
 
 	return &Dependencies{
 		PingController:                *pingController,
+		HealthController:              controller.NewHealthController(healthService),
 		TransactionController:         *transactionController,
 		TransactionCurrencyController: *transactionCurrencyController,
+		WebhookController:             *webhookController,
+		LogLevelController:            controller.NewLogLevelController(infrastructure.Log, infrastructure.LogLevel),
+		TreasurySyncService:           treasurySyncService,
+		TransactionPurgeService:       transactionPurgeService,
+		OutboxDispatcher:              outboxDispatcher,
+		WebhookDeliveryService:        webhookDeliveryService,
 	}
 }
+
+func treasurySpanName(_ string, request *http.Request) string {
+	return "Treasury " + request.Method
+}
@@ -0,0 +1,36 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+type ExchangeRates struct {
+	// names of the providers in the order they are asked for a rate
+	providers []string
+	// file is loaded once at startup, nil when the file provider is not used
+	file *repository.FileExchangeRateProvider
+}
+
+func NewExchangeRates(cfg config.ExchangeRates) (*ExchangeRates, error) {
+	exchangeRates := &ExchangeRates{
+		providers: cfg.ProviderNames(),
+	}
+
+	for _, provider := range exchangeRates.providers {
+		if provider != "file" {
+			continue
+		}
+
+		file, err := repository.NewFileExchangeRateProvider(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load exchange rate file: %w", err)
+		}
+
+		exchangeRates.file = file
+	}
+
+	return exchangeRates, nil
+}
@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewExchangeRates(t *testing.T) {
+	t.Run("Providers in the configured order without the file", func(t *testing.T) {
+		// When
+		exchangeRates, err := NewExchangeRates(config.ExchangeRates{Providers: "treasury, database"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"treasury", "database"}, exchangeRates.providers)
+		assert.Nil(t, exchangeRates.file)
+	})
+
+	t.Run("File provider loaded on startup", func(t *testing.T) {
+		// Given
+		file := filepath.Join(t.TempDir(), "rates.csv")
+		assert.NoError(t, os.WriteFile(file, []byte("country,currency,exchange_rate,effective_date\nBrazil,Real,5.434,2024-09-30\n"), 0o600))
+
+		// When
+		exchangeRates, err := NewExchangeRates(config.ExchangeRates{Providers: "database,file", File: file})
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotNil(t, exchangeRates.file)
+	})
+
+	t.Run("Error loading the file", func(t *testing.T) {
+		// When
+		exchangeRates, err := NewExchangeRates(config.ExchangeRates{Providers: "file", File: filepath.Join(t.TempDir(), "rates.csv")})
+
+		// Then
+		assert.ErrorContains(t, err, "failed to load exchange rate file")
+		assert.Nil(t, exchangeRates)
+	})
+}
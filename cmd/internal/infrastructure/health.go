@@ -0,0 +1,18 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+)
+
+type Health struct {
+	// every dependency check of the readiness probe gets its own timeout
+	timeout time.Duration
+}
+
+func NewHealth(cfg config.Health) *Health {
+	return &Health{
+		timeout: cfg.Timeout,
+	}
+}
@@ -1,39 +1,108 @@
 package infrastructure
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/metrics"
 )
 
 type Infrastructure struct {
 	Log            *slog.Logger
+	LogLevel       *slog.LevelVar
 	Router         *Routes
 	Database       *DB
 	Cache          *Cache
 	TreasuryClient *TreasuryClient
+	ExchangeRates  *ExchangeRates
+	Purge          *TransactionPurge
+	Outbox         *Outbox
+	Webhooks       *Webhooks
+	Health         *Health
+	Metrics        *metrics.Metrics
+	Tracing        *Tracing
 }
 
-func InitInfrastructure() *Infrastructure {
-	log := slog.Default()
+func InitInfrastructure(cfg *config.Config) (*Infrastructure, error) {
+	log, logLevel := NewLog(cfg.Log)
+
+	log.Info("Initializing tracing..")
+	tracing, err := NewTracing(cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
 
 	log.Info("Initializing mux router..")
-	router := NewRouter(8080, mux.NewRouter())
+	router := NewRouter(cfg.Server, mux.NewRouter())
 
 	log.Info("Initializing database client..")
-	database := NewDBClient()
+	database, err := NewDBClient(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
 
 	log.Info("Initializing cache client..")
-	cache := NewCache()
+	cache, err := NewCache(cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Initializing metrics..")
+	appMetrics := metrics.New()
+	appMetrics.Registry.MustRegister(metrics.NewCacheCollector(cache.Cache))
 
 	log.Info("Initializing treasury client..")
-	treasuryClient := NewTreasuryClient()
+	treasuryClient := NewTreasuryClient(cfg.Treasury)
+
+	log.Info("Initializing exchange rate providers..", "providers", cfg.ExchangeRates.ProviderNames())
+	exchangeRates, err := NewExchangeRates(cfg.ExchangeRates)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox, err := NewOutbox(cfg.Outbox, log)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Infrastructure{
-		Log:            slog.Default(),
+		Log:            log,
+		LogLevel:       logLevel,
 		Router:         router,
 		Database:       database,
 		Cache:          cache,
 		TreasuryClient: treasuryClient,
+		ExchangeRates:  exchangeRates,
+		Purge:          NewTransactionPurge(cfg.Purge),
+		Outbox:         outbox,
+		Webhooks:       NewWebhooks(cfg.Webhooks),
+		Health:         NewHealth(cfg.Health),
+		Metrics:        appMetrics,
+		Tracing:        tracing,
+	}, nil
+}
+
+// Close releases the database, the cache and the event publisher file and exports the spans still buffered, it
+// runs after the server and the workers stopped using them
+func (i *Infrastructure) Close() error {
+	errs := []error{}
+
+	if err := i.Outbox.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close event publisher: %w", err))
+	}
+
+	if err := i.Database.Database.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close database: %w", err))
 	}
+
+	i.Cache.Cache.Close()
+
+	if err := i.Tracing.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to export spans: %w", err))
+	}
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,21 @@
+package infrastructure
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+)
+
+// NewLog writes JSON lines to stdout at a level that can be changed while the server runs. It becomes the default
+// logger, so the packages logging with slog.Default() write the same lines
+func NewLog(cfg config.Log) (*slog.Logger, *slog.LevelVar) {
+	level := new(slog.LevelVar)
+	// validated with the config
+	_ = level.UnmarshalText([]byte(cfg.Level))
+
+	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(log)
+
+	return log, level
+}
@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+const webhookPublisherTimeout = 10 * time.Second
+
+type Outbox struct {
+	publisher   repository.EventPublisher
+	interval    time.Duration
+	maxAttempts int
+	// file of the file event publisher, closed on shutdown
+	file *os.File
+}
+
+// NewOutbox publishes the transaction events to stdout, to a file or to a webhook, as chosen by the config
+func NewOutbox(cfg config.Outbox, log *slog.Logger) (*Outbox, error) {
+	outbox := &Outbox{
+		interval:    cfg.Interval,
+		maxAttempts: cfg.MaxAttempts,
+	}
+
+	switch cfg.Publisher {
+	case "stdout":
+		outbox.publisher = repository.NewWriterEventPublisher(os.Stdout)
+	case "file":
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event publisher file: %w", err)
+		}
+
+		outbox.file = file
+		outbox.publisher = repository.NewWriterEventPublisher(file)
+	case "webhook":
+		outbox.publisher = repository.NewWebhookEventPublisher(cfg.WebhookURL, webhookPublisherTimeout, log)
+	default:
+		return nil, fmt.Errorf("invalid event publisher %q, it must be stdout, file or webhook", cfg.Publisher)
+	}
+
+	return outbox, nil
+}
+
+func (o *Outbox) Close() error {
+	if o.file == nil {
+		return nil
+	}
+
+	return o.file.Close()
+}
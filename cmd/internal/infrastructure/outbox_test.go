@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewOutbox(t *testing.T) {
+	t.Run("Stdout publisher with interval and max attempts", func(t *testing.T) {
+		// When
+		outbox, err := NewOutbox(config.Outbox{Publisher: "stdout", Interval: 2 * time.Second, MaxAttempts: 10}, slog.Default())
+
+		// Then
+		assert.NoError(t, err)
+		assert.IsType(t, &repository.WriterEventPublisher{}, outbox.publisher)
+		assert.Equal(t, 2*time.Second, outbox.interval)
+		assert.Equal(t, 10, outbox.maxAttempts)
+	})
+
+	t.Run("File publisher", func(t *testing.T) {
+		// When
+		outbox, err := NewOutbox(config.Outbox{Publisher: "file", File: filepath.Join(t.TempDir(), "events.jsonl")}, slog.Default())
+
+		// Then
+		assert.NoError(t, err)
+		assert.IsType(t, &repository.WriterEventPublisher{}, outbox.publisher)
+	})
+
+	t.Run("File publisher closes its file", func(t *testing.T) {
+		// Given
+		outbox, err := NewOutbox(config.Outbox{Publisher: "file", File: filepath.Join(t.TempDir(), "events.jsonl")}, slog.Default())
+		assert.NoError(t, err)
+
+		// When
+		err = outbox.Close()
+
+		// Then
+		assert.NoError(t, err)
+		assert.ErrorIs(t, outbox.file.Close(), os.ErrClosed)
+	})
+
+	t.Run("Stdout publisher is not closed", func(t *testing.T) {
+		// Given
+		outbox, err := NewOutbox(config.Outbox{Publisher: "stdout"}, slog.Default())
+		assert.NoError(t, err)
+
+		// When
+		err = outbox.Close()
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, outbox.file)
+	})
+
+	t.Run("File publisher error opening the file", func(t *testing.T) {
+		// When
+		outbox, err := NewOutbox(config.Outbox{Publisher: "file", File: filepath.Join(t.TempDir(), "missing", "events.jsonl")}, slog.Default())
+
+		// Then
+		assert.Nil(t, outbox)
+		assert.ErrorContains(t, err, "failed to open event publisher file")
+	})
+
+	t.Run("Webhook publisher", func(t *testing.T) {
+		// When
+		outbox, err := NewOutbox(config.Outbox{Publisher: "webhook", WebhookURL: "http://localhost:9090/events"}, slog.Default())
+
+		// Then
+		assert.NoError(t, err)
+		assert.IsType(t, &repository.WebhookEventPublisher{}, outbox.publisher)
+	})
+
+	t.Run("Invalid publisher", func(t *testing.T) {
+		// When
+		outbox, err := NewOutbox(config.Outbox{Publisher: "kafka"}, slog.Default())
+
+		// Then
+		assert.Nil(t, outbox)
+		assert.EqualError(t, err, `invalid event publisher "kafka", it must be stdout, file or webhook`)
+	})
+}
@@ -1,15 +1,37 @@
 package infrastructure
 
-import "github.com/gorilla/mux"
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+)
 
 type Routes struct {
-	Port   int
-	MuxRouter *mux.Router
+	Port int
+	// WriteTimeout of the responses, a streamed response renews it on every line it writes
+	WriteTimeout time.Duration
+	MuxRouter    *mux.Router
 }
 
-func NewRouter(port int, router *mux.Router) *Routes {
+func NewRouter(cfg config.Server, router *mux.Router) *Routes {
 	return &Routes{
-		Port:   port,
-		MuxRouter: router,
+		Port:         cfg.Port,
+		WriteTimeout: cfg.WriteTimeout,
+		MuxRouter:    router,
+	}
+}
+
+// NewHTTPServer serves the handler with the timeouts of the config, so a slow client cannot hold a connection forever
+func NewHTTPServer(cfg config.Server, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
 	}
 }
@@ -0,0 +1,77 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// tracingFlushTimeout bounds the export of the spans still buffered on shutdown
+const tracingFlushTimeout = 5 * time.Second
+
+type Tracing struct {
+	serviceName string
+	provider    *sdktrace.TracerProvider
+}
+
+// NewTracing sets the global tracer provider and the W3C trace context propagator, the spans are not recorded with
+// the none exporter but the traceparent of the requests is still propagated
+func NewTracing(cfg config.Tracing) (*Tracing, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	tracing := &Tracing{
+		serviceName: cfg.ServiceName,
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "none":
+		return tracing, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		exporter, err = otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	default:
+		err = fmt.Errorf("invalid tracing exporter %q, it must be none, stdout or otlp", cfg.Exporter)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create span exporter: %w", err)
+	}
+
+	tracing.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(tracing.provider)
+
+	return tracing, nil
+}
+
+func (t *Tracing) ServiceName() string {
+	return t.serviceName
+}
+
+// Close exports the spans still buffered
+func (t *Tracing) Close() error {
+	if t.provider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+
+	return t.provider.Shutdown(ctx)
+}
@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func Test_NewTracing(t *testing.T) {
+	t.Run("None exporter only propagates the trace context", func(t *testing.T) {
+		// When
+		tracing, err := NewTracing(config.Tracing{Exporter: "none", ServiceName: "transaction-api", SampleRatio: 1})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, tracing.provider)
+		assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+		assert.NoError(t, tracing.Close())
+	})
+
+	t.Run("Stdout exporter records the spans", func(t *testing.T) {
+		// When
+		tracing, err := NewTracing(config.Tracing{Exporter: "stdout", ServiceName: "transaction-api", SampleRatio: 1})
+
+		// Then
+		assert.NoError(t, err)
+		_, span := otel.Tracer("test").Start(context.Background(), "test")
+		assert.True(t, span.IsRecording())
+		span.End()
+		assert.NoError(t, tracing.Close())
+	})
+
+	t.Run("OTLP exporter", func(t *testing.T) {
+		// When
+		tracing, err := NewTracing(config.Tracing{Exporter: "otlp", OTLPEndpoint: "http://127.0.0.1:4318", ServiceName: "transaction-api", SampleRatio: 0.5})
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotNil(t, tracing.provider)
+		assert.NoError(t, tracing.provider.Shutdown(context.Background()))
+	})
+
+	t.Run("Error unknown exporter", func(t *testing.T) {
+		// When
+		tracing, err := NewTracing(config.Tracing{Exporter: "jaeger"})
+
+		// Then
+		assert.Nil(t, tracing)
+		assert.EqualError(t, err, `failed to create span exporter: invalid tracing exporter "jaeger", it must be none, stdout or otlp`)
+	})
+}
@@ -0,0 +1,20 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+)
+
+type TransactionPurge struct {
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewTransactionPurge keeps the deleted transactions for the configured retention, a retention of 0 disables the purge
+func NewTransactionPurge(cfg config.Purge) *TransactionPurge {
+	return &TransactionPurge{
+		retention: cfg.Retention,
+		interval:  cfg.Interval,
+	}
+}
@@ -1,17 +1,37 @@
 package infrastructure
 
-import "time"
+import (
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
 
 type TreasuryClient struct {
-	domain  string
-	path    string
-	timeout time.Duration
+	domain         string
+	path           string
+	timeout        time.Duration
+	syncInterval   time.Duration
+	attemptTimeout time.Duration
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	// shared by every call to the treasury api, the health check included
+	breaker *repository.CircuitBreaker
+	limiter *repository.RateLimiter
 }
 
-func NewTreasuryClient() *TreasuryClient {
+func NewTreasuryClient(cfg config.Treasury) *TreasuryClient {
 	return &TreasuryClient{
-		domain:  "https://api.fiscaldata.treasury.gov",
-		path:    "/services/api/fiscal_service/v1/accounting/od/rates_of_exchange",
-		timeout: 30 * time.Second,
+		domain:         cfg.Domain,
+		path:           cfg.Path,
+		timeout:        cfg.Timeout,
+		syncInterval:   cfg.SyncInterval,
+		attemptTimeout: cfg.AttemptTimeout,
+		maxRetries:     cfg.MaxRetries,
+		retryBaseDelay: cfg.RetryBaseDelay,
+		retryMaxDelay:  cfg.RetryMaxDelay,
+		breaker:        repository.NewCircuitBreaker(cfg.BreakerFailures, cfg.BreakerOpenTimeout),
+		limiter:        repository.NewRateLimiter(cfg.RateLimit, cfg.RateBurst),
 	}
 }
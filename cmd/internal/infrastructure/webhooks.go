@@ -0,0 +1,24 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
+)
+
+type Webhooks struct {
+	timeout     time.Duration
+	interval    time.Duration
+	maxAttempts int
+	// a webhook is disabled after this many deliveries failed in a row, it is enabled again by its enable endpoint
+	disableAfter int
+}
+
+func NewWebhooks(cfg config.Webhooks) *Webhooks {
+	return &Webhooks{
+		timeout:      cfg.Timeout,
+		interval:     cfg.Interval,
+		maxAttempts:  cfg.MaxAttempts,
+		disableAfter: cfg.DisableAfter,
+	}
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"github.com/dgraph-io/ristretto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CacheCollector exposes the ristretto metrics of a cache, which must be created with Metrics enabled
+type CacheCollector struct {
+	cache *ristretto.Cache
+
+	hits    *prometheus.Desc
+	misses  *prometheus.Desc
+	added   *prometheus.Desc
+	evicted *prometheus.Desc
+	dropped *prometheus.Desc
+	cost    *prometheus.Desc
+}
+
+func NewCacheCollector(cache *ristretto.Cache) *CacheCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", name), help, nil, nil)
+	}
+
+	return &CacheCollector{
+		cache:   cache,
+		hits:    desc("hits_total", "Number of cache gets that found the key."),
+		misses:  desc("misses_total", "Number of cache gets that did not find the key."),
+		added:   desc("keys_added_total", "Number of keys added to the cache."),
+		evicted: desc("keys_evicted_total", "Number of keys evicted from the cache."),
+		dropped: desc("sets_dropped_total", "Number of cache sets dropped or rejected by the admission policy."),
+		cost:    desc("cost", "Cost of the keys in the cache, added minus evicted."),
+	}
+}
+
+func (c *CacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.added
+	ch <- c.evicted
+	ch <- c.dropped
+	ch <- c.cost
+}
+
+func (c *CacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.cache.Metrics
+	if stats == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits()))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses()))
+	ch <- prometheus.MustNewConstMetric(c.added, prometheus.CounterValue, float64(stats.KeysAdded()))
+	ch <- prometheus.MustNewConstMetric(c.evicted, prometheus.CounterValue, float64(stats.KeysEvicted()))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(stats.SetsDropped()+stats.SetsRejected()))
+	ch <- prometheus.MustNewConstMetric(c.cost, prometheus.GaugeValue, float64(stats.CostAdded()-stats.CostEvicted()))
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "transaction_api"
+
+// Metrics holds the prometheus collectors of the application, registered in a registry of its own so the tests
+// create as many as they need
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	// HTTPRequests and HTTPDuration are labeled by method, route template and status code
+	HTTPRequests *prometheus.CounterVec
+	HTTPDuration *prometheus.HistogramVec
+
+	// RepositoryDuration is labeled by repository and method
+	RepositoryDuration *prometheus.HistogramVec
+
+	// TreasuryRequests is labeled by status code, the calls without response count only in TreasuryErrors
+	TreasuryRequests *prometheus.CounterVec
+	TreasuryDuration prometheus.Histogram
+	TreasuryErrors   prometheus.Counter
+
+	// ExchangeRateCacheLookups is labeled by result: hit, negative_hit (a cached lookup without rate) or miss
+	ExchangeRateCacheLookups *prometheus.CounterVec
+
+	// ConversionsRejected counts the conversions without an exchange rate effective in the six months before the
+	// purchase
+	ConversionsRejected prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		RepositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Duration of the database queries by repository and method.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"repository", "method"}),
+		TreasuryRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "treasury_requests_total",
+			Help:      "Number of Treasury API calls answered, by status code.",
+		}, []string{"status"}),
+		TreasuryDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "treasury_request_duration_seconds",
+			Help:      "Duration of the Treasury API calls, including the failed ones.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}),
+		TreasuryErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "treasury_request_errors_total",
+			Help:      "Number of Treasury API calls failed without a response, such as timeouts and refused connections.",
+		}),
+		ExchangeRateCacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "exchange_rate_cache_lookups_total",
+			Help:      "Number of exchange rate lookups in the cache by result.",
+		}, []string{"result"}),
+		ConversionsRejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "conversions_rejected_total",
+			Help:      "Number of conversions rejected for lack of an exchange rate effective in the six months before the purchase.",
+		}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.RepositoryDuration,
+		m.TreasuryRequests,
+		m.TreasuryDuration,
+		m.TreasuryErrors,
+		m.ExchangeRateCacheLookups,
+		m.ConversionsRejected,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
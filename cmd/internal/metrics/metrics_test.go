@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func Test_Handler(t *testing.T) {
+	// Given
+	m := New()
+	m.ConversionsRejected.Inc()
+	m.ExchangeRateCacheLookups.WithLabelValues("hit").Inc()
+	rr := httptest.NewRecorder()
+
+	// When
+	m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	// Then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "transaction_api_conversions_rejected_total 1\n")
+	assert.Contains(t, rr.Body.String(), "transaction_api_exchange_rate_cache_lookups_total{result=\"hit\"} 1\n")
+	assert.Contains(t, rr.Body.String(), "go_goroutines")
+}
+
+func Test_TreasuryTransport(t *testing.T) {
+	t.Run("Transport observes the status code and the duration", func(t *testing.T) {
+		// Given
+		m := New()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewTreasuryTransport(nil, m)}
+
+		// When
+		response, err := client.Get(server.URL)
+
+		// Then
+		assert.NoError(t, err)
+		response.Body.Close()
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.TreasuryRequests.WithLabelValues("429")))
+		assert.Equal(t, float64(0), testutil.ToFloat64(m.TreasuryErrors))
+		assert.Equal(t, 1, testutil.CollectAndCount(m.TreasuryDuration))
+	})
+
+	t.Run("Transport counts the calls without response as errors", func(t *testing.T) {
+		// Given
+		m := New()
+		client := &http.Client{Transport: NewTreasuryTransport(failingTransport{}, m)}
+
+		// When
+		_, err := client.Get("http://treasury.local")
+
+		// Then
+		assert.ErrorContains(t, err, "connection refused")
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.TreasuryErrors))
+		assert.Equal(t, 0, testutil.CollectAndCount(m.TreasuryRequests))
+	})
+}
+
+func Test_CacheCollector(t *testing.T) {
+	t.Run("Collect the ristretto metrics", func(t *testing.T) {
+		// Given
+		cache, err := ristretto.NewCache(&ristretto.Config{NumCounters: 100, MaxCost: 1 << 20, BufferItems: 64, Metrics: true})
+		assert.NoError(t, err)
+		defer cache.Close()
+		cache.SetWithTTL("transaction:1", "mock", 1, time.Minute)
+		cache.Wait()
+		cache.Get("transaction:1")
+		cache.Get("transaction:2")
+
+		// When
+		err = testutil.CollectAndCompare(NewCacheCollector(cache), strings.NewReader(`
+# HELP transaction_api_cache_hits_total Number of cache gets that found the key.
+# TYPE transaction_api_cache_hits_total counter
+transaction_api_cache_hits_total 1
+# HELP transaction_api_cache_misses_total Number of cache gets that did not find the key.
+# TYPE transaction_api_cache_misses_total counter
+transaction_api_cache_misses_total 1
+# HELP transaction_api_cache_keys_added_total Number of keys added to the cache.
+# TYPE transaction_api_cache_keys_added_total counter
+transaction_api_cache_keys_added_total 1
+`), "transaction_api_cache_hits_total", "transaction_api_cache_misses_total", "transaction_api_cache_keys_added_total")
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("Collect nothing without the ristretto metrics", func(t *testing.T) {
+		// Given
+		cache, err := ristretto.NewCache(&ristretto.Config{NumCounters: 100, MaxCost: 1 << 20, BufferItems: 64})
+		assert.NoError(t, err)
+		defer cache.Close()
+
+		// When
+		count := testutil.CollectAndCount(NewCacheCollector(cache))
+
+		// Then
+		assert.Equal(t, 0, count)
+	})
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Transport is an http.RoundTripper that observes the Treasury API calls
+type Transport struct {
+	next     http.RoundTripper
+	requests *prometheus.CounterVec
+	duration prometheus.Histogram
+	errors   prometheus.Counter
+}
+
+func NewTreasuryTransport(next http.RoundTripper, metrics *Metrics) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Transport{
+		next:     next,
+		requests: metrics.TreasuryRequests,
+		duration: metrics.TreasuryDuration,
+		errors:   metrics.TreasuryErrors,
+	}
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	t.duration.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		t.errors.Inc()
+		return nil, err
+	}
+
+	t.requests.WithLabelValues(strconv.Itoa(response.StatusCode)).Inc()
+	return response, nil
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// RequireToken serves the handler only to the requests with the token in the Authorization header as a bearer token
+func RequireToken(token string, handler HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			return model.NewUnauthorizedError("missing or invalid admin token")
+		}
+
+		return handler(w, r)
+	}
+}
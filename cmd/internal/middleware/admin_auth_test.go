@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireToken(t *testing.T) {
+	handler := HandleErrors(RequireToken("0123456789abcdef", func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}))
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "Serve the request with the token", authorization: "Bearer 0123456789abcdef", expectedStatus: http.StatusNoContent},
+		{name: "Reject the request without the token", authorization: "", expectedStatus: http.StatusUnauthorized},
+		{name: "Reject the request with another token", authorization: "Bearer 0123456789abcdeX", expectedStatus: http.StatusUnauthorized},
+		{name: "Reject the request with the token not as a bearer", authorization: "0123456789abcdef", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			req := httptest.NewRequest("PUT", "/admin/log-level", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			rr := httptest.NewRecorder()
+
+			// When
+			handler.ServeHTTP(rr, req)
+
+			// Then
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+				assert.Contains(t, rr.Body.String(), `"error_code":"unauthorized"`)
+			}
+		})
+	}
+}
@@ -2,12 +2,31 @@ package middleware
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
 )
 
+// HandlerFunc is a handler that returns its error instead of writing it
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// HandleErrors adapts the handler writing its error, mapped to the HTTP status of its kind, as the response
+func HandleErrors(handler HandlerFunc) http.HandlerFunc {
+	log := slog.Default()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := handler(w, r); err != nil {
+			apiErr := presentation.NewApiErrorFromError(err)
+			util.Logger(r.Context(), log).Error("Request failed", "message", err.Error(), "status", apiErr.Code)
+			writeProblem(w, r, apiErr)
+		}
+	}
+}
+
+// ErrorHandler recovers from panics, which are programmer errors as every expected failure is a returned error
 func ErrorHandler(next http.Handler) http.Handler {
 	log := slog.Default()
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -15,18 +34,24 @@ func ErrorHandler(next http.Handler) http.Handler {
 			if err := recover(); err != nil {
 				var apiErr *presentation.ApiError
 				switch e := err.(type) {
-				case *presentation.ApiError:
-					apiErr = e
 				case error:
-					apiErr = presentation.NewApiError(http.StatusInternalServerError, e.Error())
+					apiErr = presentation.NewApiErrorFromError(e)
 				default:
 					apiErr = presentation.NewApiError(http.StatusInternalServerError, "Unknown error")
 				}
-				log.Error("Exception caught", "message", apiErr.Message, "status", apiErr.Code)
-				w.WriteHeader(apiErr.Code)
-				json.NewEncoder(w).Encode(apiErr)
+				util.Logger(r.Context(), log).Error("Exception caught", "message", fmt.Sprint(err), "status", apiErr.Code, "stack", string(debug.Stack()))
+				writeProblem(w, r, apiErr)
 			}
 		}()
 		next.ServeHTTP(w, r)
 	})
 }
+
+// writeProblem writes the error as RFC 7807 problem details
+func writeProblem(w http.ResponseWriter, r *http.Request, apiErr *presentation.ApiError) {
+	problem := presentation.NewProblemDetailsDTO(apiErr, r.URL.Path, RequestID(w, r))
+
+	w.Header().Set("Content-Type", presentation.ProblemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
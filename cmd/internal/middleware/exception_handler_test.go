@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,7 +41,7 @@ func TestErrorHandler(t *testing.T) {
 				panic(errors.New("something wrong"))
 			}),
 			expectedStatus: http.StatusInternalServerError,
-			expectedError:  presentation.NewApiError(http.StatusInternalServerError, "something wrong"),
+			expectedError:  presentation.NewApiError(http.StatusInternalServerError, "internal server error"),
 		},
 		{
 			name: "Error handler with unknown error",
@@ -65,11 +66,75 @@ func TestErrorHandler(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 
 			if tt.expectedError != nil {
-				var apiErr presentation.ApiError
-				err := json.NewDecoder(rr.Body).Decode(&apiErr)
+				var problem presentation.ProblemDetailsDTO
+				err := json.NewDecoder(rr.Body).Decode(&problem)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedError.Code, apiErr.Code)
-				assert.Equal(t, tt.expectedError.Message, apiErr.Message)
+				assert.Equal(t, presentation.ProblemContentType, rr.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedError.Code, problem.Status)
+				assert.Equal(t, tt.expectedError.Message, problem.Detail)
+				assert.Equal(t, tt.expectedError.ErrorCode, problem.ErrorCode)
+			}
+		})
+	}
+}
+
+func TestHandleErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        HandlerFunc
+		expectedStatus int
+		expectedError  *presentation.ApiError
+	}{
+		{
+			name: "Handle errors without error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusNoContent)
+				return nil
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedError:  nil,
+		},
+		{
+			name: "Handle errors with validation error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return model.NewValidationError("bad request")
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  presentation.NewApiError(http.StatusBadRequest, "bad request"),
+		},
+		{
+			name: "Handle errors with not found error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return model.NewNotFoundError("transaction not found")
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  presentation.NewApiError(http.StatusNotFound, "transaction not found"),
+		},
+		{
+			name: "Handle errors with generic error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return errors.New("something wrong")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  presentation.NewApiError(http.StatusInternalServerError, "internal server error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			HandleErrors(tt.handler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+
+			if tt.expectedError != nil {
+				var problem presentation.ProblemDetailsDTO
+				err := json.NewDecoder(rr.Body).Decode(&problem)
+				assert.NoError(t, err)
+				assert.Equal(t, *presentation.NewProblemDetailsDTO(tt.expectedError, "/", rr.Header().Get(RequestIDHeader)), problem)
 			}
 		})
 	}
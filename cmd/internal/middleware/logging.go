@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Logging gives each request its ID and a logger with it, and its trace ID when it is traced, and logs the request
+// once it is answered. The route template, and not the path, groups the requests of the same handler
+func Logging(log *slog.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := resolveRequestID(w, r)
+			requestLog := log.With("request_id", requestID)
+			if span := trace.SpanContextFromContext(r.Context()); span.IsValid() {
+				requestLog = requestLog.With("trace_id", span.TraceID().String())
+			}
+
+			recorder := newResponseRecorder(w)
+			ctx := util.WithRequestID(util.WithLogger(r.Context(), requestLog), requestID)
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			level := slog.LevelInfo
+			if recorder.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			requestLog.LogAttrs(r.Context(), level, "Request",
+				slog.String("method", r.Method),
+				slog.String("route", routeTemplate(r)),
+				slog.Int("status", recorder.status),
+				slog.Int("bytes", recorder.bytes),
+				slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogging(t *testing.T) {
+	t.Run("Logging by route template with the request ID informed", func(t *testing.T) {
+		// Given
+		var output bytes.Buffer
+		router := mux.NewRouter()
+		router.Use(Logging(slog.New(slog.NewJSONHandler(&output, nil))))
+		router.HandleFunc("/v1/transaction/{id}", func(w http.ResponseWriter, r *http.Request) {
+			util.Logger(r.Context(), nil).Info("Transaction found")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":404}`))
+		}).Methods("GET")
+		req := httptest.NewRequest("GET", "/v1/transaction/1", nil)
+		req.Header.Set(RequestIDHeader, "abc")
+		rr := httptest.NewRecorder()
+
+		// When
+		router.ServeHTTP(rr, req)
+
+		// Then
+		lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+		assert.Len(t, lines, 2)
+		assert.Equal(t, "abc", rr.Header().Get(RequestIDHeader))
+
+		var handlerLog, requestLog map[string]any
+		assert.NoError(t, json.Unmarshal(lines[0], &handlerLog))
+		assert.NoError(t, json.Unmarshal(lines[1], &requestLog))
+		assert.Equal(t, "abc", handlerLog["request_id"])
+		assert.Equal(t, "abc", requestLog["request_id"])
+		assert.Equal(t, "INFO", requestLog["level"])
+		assert.Equal(t, "GET", requestLog["method"])
+		assert.Equal(t, "/v1/transaction/{id}", requestLog["route"])
+		assert.Equal(t, float64(404), requestLog["status"])
+		assert.Equal(t, float64(14), requestLog["bytes"])
+		assert.Contains(t, requestLog, "duration_ms")
+	})
+
+	t.Run("Logging server errors with a generated request ID", func(t *testing.T) {
+		// Given
+		var output bytes.Buffer
+		handler := Logging(slog.New(slog.NewJSONHandler(&output, nil)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		rr := httptest.NewRecorder()
+
+		// When
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/v1/transaction", nil))
+
+		// Then
+		var requestLog map[string]any
+		assert.NoError(t, json.Unmarshal(output.Bytes(), &requestLog))
+		assert.Len(t, rr.Header().Get(RequestIDHeader), 32)
+		assert.Equal(t, rr.Header().Get(RequestIDHeader), requestLog["request_id"])
+		assert.Equal(t, "ERROR", requestLog["level"])
+		assert.Equal(t, "unknown", requestLog["route"])
+	})
+
+	t.Run("Logging keeps the request ID in the context of the request", func(t *testing.T) {
+		// Given
+		var requestID string
+		handler := Logging(slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// a handler overwriting the response header does not change the ID of the request
+			w.Header().Set(RequestIDHeader, "overwritten")
+			requestID = RequestID(w, r)
+		}))
+		req := httptest.NewRequest("GET", "/v1/transaction/1", nil)
+		req.Header.Set(RequestIDHeader, "abc")
+
+		// When
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		// Then
+		assert.Equal(t, "abc", requestID)
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("Metrics by route template and status", func(t *testing.T) {
+		// Given
+		m := metrics.New()
+		router := mux.NewRouter()
+		router.Use(Metrics(m.HTTPRequests, m.HTTPDuration))
+		router.HandleFunc("/v1/transaction/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}).Methods("GET")
+
+		// When
+		for _, id := range []string{"1", "2"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/transaction/"+id, nil))
+		}
+
+		// Then
+		assert.Equal(t, float64(2), testutil.ToFloat64(m.HTTPRequests.WithLabelValues("GET", "/v1/transaction/{id}", "404")))
+		assert.Equal(t, 1, testutil.CollectAndCount(m.HTTPDuration))
+	})
+
+	t.Run("Metrics with the default status keeps flushing the response", func(t *testing.T) {
+		// Given
+		m := metrics.New()
+		handler := Metrics(m.HTTPRequests, m.HTTPDuration)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("{}\n"))
+			w.(http.Flusher).Flush()
+		}))
+		rr := httptest.NewRecorder()
+
+		// When
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/v1/currency/convert", nil))
+
+		// Then
+		assert.True(t, rr.Flushed)
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.HTTPRequests.WithLabelValues("POST", "unknown", "200")))
+	})
+}
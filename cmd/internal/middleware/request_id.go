@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern keeps the ID informed by the client short and safe to log and echo back in the response
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID returns the ID of the request kept in its context by Logging, outside of it, e.g. a handler served on
+// its own, the ID is resolved the same way
+func RequestID(w http.ResponseWriter, r *http.Request) string {
+	if id := util.RequestID(r.Context()); id != "" {
+		return id
+	}
+
+	return resolveRequestID(w, r)
+}
+
+// resolveRequestID returns the ID informed by the client, or generates one when it is missing or invalid, and sends
+// it back in the response
+func resolveRequestID(w http.ResponseWriter, r *http.Request) string {
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		return id
+	}
+
+	id := r.Header.Get(RequestIDHeader)
+	if !requestIDPattern.MatchString(id) {
+		id = newRequestID()
+	}
+
+	w.Header().Set(RequestIDHeader, id)
+	return id
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "Keep the ID informed by the client", header: "abc-123_DEF.4", expected: "abc-123_DEF.4"},
+		{name: "Keep the ID with 64 characters", header: strings.Repeat("a", 64), expected: strings.Repeat("a", 64)},
+		{name: "Generate an ID when it is missing", header: ""},
+		{name: "Generate an ID longer than 64 characters", header: strings.Repeat("a", 65)},
+		{name: "Generate an ID with spaces", header: "abc 123"},
+		{name: "Generate an ID with line breaks", header: "abc\r\nSet-Cookie: x=1"},
+		{name: "Generate an ID with quotes", header: `abc"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			req := httptest.NewRequest("GET", "/v1/transaction/1", nil)
+			req.Header.Set(RequestIDHeader, tt.header)
+			rr := httptest.NewRecorder()
+
+			// When
+			id := RequestID(rr, req)
+
+			// Then
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, id)
+			} else {
+				assert.Regexp(t, "^[0-9a-f]{32}$", id)
+			}
+			assert.Equal(t, id, rr.Header().Get(RequestIDHeader))
+		})
+	}
+
+	t.Run("Reuse the ID already sent in the response", func(t *testing.T) {
+		// Given
+		req := httptest.NewRequest("GET", "/v1/transaction/1", nil)
+		req.Header.Set(RequestIDHeader, "from-client")
+		rr := httptest.NewRecorder()
+		rr.Header().Set(RequestIDHeader, "already-sent")
+
+		// When
+		id := RequestID(rr, req)
+
+		// Then
+		assert.Equal(t, "already-sent", id)
+	})
+}
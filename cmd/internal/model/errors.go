@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// Kinds of domain errors, check them with errors.Is
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrValidation          = errors.New("validation failed")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrRateNotFound        = errors.New("exchange rate not found")
+	ErrConflict            = errors.New("conflict")
+	ErrIdempotencyKeyReuse = errors.New("idempotency key reused")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrUnsupportedMedia    = errors.New("unsupported media type")
+	ErrUnauthorized        = errors.New("unauthorized")
+)
+
+// InvalidParam is a field of a request that failed validation and why
+type InvalidParam struct {
+	Name   string
+	Reason string
+}
+
+// DomainError is a failure of one of the domain kinds with the message reported to the caller
+type DomainError struct {
+	Kind          error
+	Message       string
+	Err           error
+	InvalidParams []InvalidParam
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func (e *DomainError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+func NewNotFoundError(message string) error {
+	return &DomainError{Kind: ErrNotFound, Message: message}
+}
+
+func NewValidationError(message string) error {
+	return &DomainError{Kind: ErrValidation, Message: message}
+}
+
+// NewInvalidParamsError returns a validation error with every invalid field, its message joins their reasons
+func NewInvalidParamsError(invalidParams []InvalidParam) error {
+	reasons := make([]string, 0, len(invalidParams))
+	for _, param := range invalidParams {
+		reasons = append(reasons, param.Reason)
+	}
+
+	return &DomainError{Kind: ErrValidation, Message: strings.Join(reasons, "; "), InvalidParams: invalidParams}
+}
+
+func NewUpstreamUnavailableError(message string, err error) error {
+	return &DomainError{Kind: ErrUpstreamUnavailable, Message: message, Err: err}
+}
+
+func NewRateNotFoundError(message string) error {
+	return &DomainError{Kind: ErrRateNotFound, Message: message}
+}
+
+func NewConflictError(message string) error {
+	return &DomainError{Kind: ErrConflict, Message: message}
+}
+
+func NewIdempotencyKeyReuseError(message string) error {
+	return &DomainError{Kind: ErrIdempotencyKeyReuse, Message: message}
+}
+
+func NewPreconditionFailedError(message string) error {
+	return &DomainError{Kind: ErrPreconditionFailed, Message: message}
+}
+
+func NewUnsupportedMediaError(message string) error {
+	return &DomainError{Kind: ErrUnsupportedMedia, Message: message}
+}
+
+func NewUnauthorizedError(message string) error {
+	return &DomainError{Kind: ErrUnauthorized, Message: message}
+}
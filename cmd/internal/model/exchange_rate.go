@@ -0,0 +1,24 @@
+package model
+
+import (
+	"math/big"
+	"time"
+)
+
+// the sources of the exchange rates, the names used in the order of the providers
+const (
+	ExchangeRateSourceDatabase = "database"
+	ExchangeRateSourceTreasury = "treasury"
+	ExchangeRateSourceFile     = "file"
+)
+
+// ExchangeRate is the rate of the currency of a country, in units of the currency per dollar, whatever the source
+// that supplied it
+type ExchangeRate struct {
+	Country string
+	// Currency is the code of the currency in the source, the Treasury names it after the country (e.g. Real)
+	Currency      string
+	Rate          *big.Rat
+	EffectiveDate time.Time
+	Source        string
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// IdempotencyKeyTTL is how long a completed response is replayed
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyRecord is the request sent with an idempotency key and, once completed, its response. The response
+// body is stored after the transaction is created, TransactionID is completed along with it and replays the
+// transaction when the body was not stored
+type IdempotencyRecord struct {
+	Key           string
+	Fingerprint   string
+	Completed     bool
+	StatusCode    int
+	ResponseBody  []byte
+	TransactionID int64
+	ExpiresAt     time.Time
+}
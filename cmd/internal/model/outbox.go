@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+type EventType string
+
+const (
+	EventTransactionCreated EventType = "transaction.created"
+	EventTransactionUpdated EventType = "transaction.updated"
+	EventTransactionDeleted EventType = "transaction.deleted"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxDelivered OutboxStatus = "delivered"
+	// OutboxDead is an event that failed too many times, it is kept for inspection and is not retried
+	OutboxDead OutboxStatus = "dead"
+)
+
+// operationEventTypes are the operations published to the downstream consumers, a purge only removes a
+// transaction already deleted
+var operationEventTypes = map[TransactionOperation]EventType{
+	OperationCreate:  EventTransactionCreated,
+	OperationUpdate:  EventTransactionUpdated,
+	OperationPatch:   EventTransactionUpdated,
+	OperationRestore: EventTransactionUpdated,
+	OperationDelete:  EventTransactionDeleted,
+}
+
+// EventType returns the type of the event published for the operation, false when it is not published
+func (o TransactionOperation) EventType() (EventType, bool) {
+	eventType, published := operationEventTypes[o]
+	return eventType, published
+}
+
+// OutboxEvent is a change of a transaction waiting to be published, Transaction is the state after the change
+type OutboxEvent struct {
+	ID            int64
+	Type          EventType
+	TransactionID int64
+	Transaction   *Transaction
+	OccurredAt    time.Time
+	Attempts      int
+}
+
+// EventMessage is an event serialized to be delivered
+type EventMessage struct {
+	ID   int64
+	Type EventType
+	Body []byte
+}
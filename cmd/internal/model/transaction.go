@@ -8,6 +8,10 @@ type Transaction struct {
 	ID              int64
 	Description     string
 	TransactionDate time.Time
-	PurchaseAmount  float32
+	PurchaseAmount  int64 // in cents
 	Deleted         bool
+	Version         int64 // incremented on every change, used for optimistic concurrency
+	DeletedAt       *time.Time
+	RestoredAt      *time.Time
+	RestoredBy      string
 }
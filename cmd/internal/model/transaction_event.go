@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type TransactionOperation string
+
+const (
+	// OperationBaseline is the state of a transaction created before the audit trail existed
+	OperationBaseline TransactionOperation = "baseline"
+	OperationCreate   TransactionOperation = "create"
+	OperationUpdate   TransactionOperation = "update"
+	OperationPatch    TransactionOperation = "patch"
+	OperationDelete   TransactionOperation = "delete"
+	OperationRestore  TransactionOperation = "restore"
+	OperationPurge    TransactionOperation = "purge"
+)
+
+// Audit identifies who changed a transaction, in which request and when
+type Audit struct {
+	Actor     string
+	RequestID string
+	At        time.Time
+	// IdempotencyKey reserved by the request, completed in the same database transaction that creates the transaction
+	IdempotencyKey string
+}
+
+// TransactionEvent is one change of a transaction, OldValue is nil when it was created and NewValue is nil when
+// it was purged
+type TransactionEvent struct {
+	ID            int64
+	TransactionID int64
+	Operation     TransactionOperation
+	Actor         string
+	RequestID     string
+	OccurredAt    time.Time
+	OldValue      *Transaction
+	NewValue      *Transaction
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type TransactionFilter struct {
+	TransactionDateFrom *time.Time
+	TransactionDateTo   *time.Time
+	PurchaseAmountMin   *int64 // in cents
+	PurchaseAmountMax   *int64 // in cents
+	Description         string
+	IncludeDeleted      bool
+	Limit               int
+	Offset              int
+	AfterID             int64 // only the transactions with a greater id, pages by id when the rows may change
+}
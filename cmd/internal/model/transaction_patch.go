@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// TransactionPatch has the fields of a transaction to change, nil fields are kept
+type TransactionPatch struct {
+	Description     *string
+	TransactionDate *time.Time
+	PurchaseAmount  *int64 // in cents
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// AllEvents subscribes a webhook to every event type
+const AllEvents EventType = "*"
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryFailed is a delivery that failed too many times, it is not retried anymore
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// WebhookSubscription receives the events of the types in EventTypes signed with its secret
+type WebhookSubscription struct {
+	ID                  int64
+	URL                 string
+	EventTypes          []EventType
+	Secret              string
+	Active              bool
+	ConsecutiveFailures int
+	DisabledAt          *time.Time
+	CreatedAt           time.Time
+}
+
+// WebhookDelivery is one event sent to one subscription and the result of its last attempt
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        int64
+	EventType      EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// PendingWebhookDelivery is a delivery due with where to send it
+type PendingWebhookDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WebhookAttempt is the result of sending a delivery, NextAttemptAt is nil when it is not retried anymore
+type WebhookAttempt struct {
+	DeliveryID     int64
+	SubscriptionID int64
+	Attempts       int
+	StatusCode     int
+	Error          string
+	At             time.Time
+	NextAttemptAt  *time.Time
+}
+
+// Subscribable reports whether a webhook can subscribe to the event type
+func (e EventType) Subscribable() bool {
+	switch e {
+	case AllEvents, EventTransactionCreated, EventTransactionUpdated, EventTransactionDeleted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,41 @@
+package presentation
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+// Amount is a monetary value in cents, serialized as an exact decimal JSON number (e.g. 123.45)
+type Amount int64
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(util.FormatAmount(int64(a))), nil
+}
+
+// UnmarshalJSON accepts the amount as a JSON number or string and parses it without float rounding
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	value := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	cents, err := util.ParseAmount(value)
+	if err != nil {
+		return err
+	}
+
+	*a = Amount(cents)
+	return nil
+}
+
+func (a Amount) String() string {
+	return util.FormatAmount(int64(a))
+}
@@ -0,0 +1,58 @@
+package presentation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Amount_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    Amount
+		expected string
+	}{
+		{input: 0, expected: "0.00"},
+		{input: 174, expected: "1.74"},
+		{input: 12345678999, expected: "123456789.99"},
+	}
+
+	for _, tt := range tests {
+		response, err := json.Marshal(tt.input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, string(response))
+	}
+}
+
+func Test_Amount_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      Amount
+		expectedError string
+	}{
+		{name: "Unmarshal number", input: "123456789.99", expected: 12345678999},
+		{name: "Unmarshal string", input: `"1.74"`, expected: 174},
+		{name: "Unmarshal number with more than two decimal places", input: "1.235", expectedError: "invalid amount, it must have at most 2 decimal places"},
+		{name: "Unmarshal hex string", input: `"0x10"`, expectedError: "invalid amount, it must be a decimal number"},
+		{name: "Unmarshal number with exponent", input: "1e2", expectedError: "invalid amount, it must be a decimal number"},
+		{name: "Unmarshal null", input: "null", expected: 0},
+		{name: "Unmarshal invalid string", input: `"mock"`, expectedError: "invalid amount, it must be a decimal number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var amount Amount
+			err := json.Unmarshal([]byte(tt.input), &amount)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, amount)
+			}
+		})
+	}
+}
@@ -1,17 +1,114 @@
 package presentation
 
+import (
+	"errors"
+	"net/http"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// Stable error codes, clients must rely on them instead of the messages
+const (
+	ErrorCodeValidation           = "validation_error"
+	ErrorCodeNotFound             = "not_found"
+	ErrorCodeExchangeRateNotFound = "exchange_rate_not_found"
+	ErrorCodeUpstreamUnavailable  = "upstream_unavailable"
+	ErrorCodeConflict             = "conflict"
+	ErrorCodeIdempotencyKeyReused = "idempotency_key_reused"
+	ErrorCodePreconditionFailed   = "precondition_failed"
+	ErrorCodeUnsupportedMedia     = "unsupported_media_type"
+	ErrorCodeUnauthorized         = "unauthorized"
+	ErrorCodeInternal             = "internal_error"
+)
+
+// internalErrorMessage is the message of every internal error, its cause is only logged as it may expose the
+// database, driver or upstream details
+const internalErrorMessage = "internal server error"
+
+// errorKinds maps each kind of domain error to its HTTP status and error code
+var errorKinds = []struct {
+	kind      error
+	status    int
+	errorCode string
+}{
+	{kind: model.ErrValidation, status: http.StatusBadRequest, errorCode: ErrorCodeValidation},
+	{kind: model.ErrNotFound, status: http.StatusNotFound, errorCode: ErrorCodeNotFound},
+	{kind: model.ErrRateNotFound, status: http.StatusBadGateway, errorCode: ErrorCodeExchangeRateNotFound},
+	{kind: model.ErrUpstreamUnavailable, status: http.StatusBadGateway, errorCode: ErrorCodeUpstreamUnavailable},
+	{kind: model.ErrConflict, status: http.StatusConflict, errorCode: ErrorCodeConflict},
+	{kind: model.ErrIdempotencyKeyReuse, status: http.StatusUnprocessableEntity, errorCode: ErrorCodeIdempotencyKeyReused},
+	{kind: model.ErrPreconditionFailed, status: http.StatusPreconditionFailed, errorCode: ErrorCodePreconditionFailed},
+	{kind: model.ErrUnsupportedMedia, status: http.StatusUnsupportedMediaType, errorCode: ErrorCodeUnsupportedMedia},
+	{kind: model.ErrUnauthorized, status: http.StatusUnauthorized, errorCode: ErrorCodeUnauthorized},
+}
+
 type ApiError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code          int               `json:"code"`
+	Message       string            `json:"message"`
+	ErrorCode     string            `json:"error_code,omitempty"`
+	InvalidParams []InvalidParamDTO `json:"invalid_params,omitempty"`
+}
+
+type InvalidParamDTO struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
 }
 
 func (e *ApiError) Error() string {
 	return e.Message
 }
 
+// NewApiError returns an error with the default error code of the HTTP status
 func NewApiError(code int, message string) *ApiError {
 	return &ApiError{
-		Code:    code,
-		Message: message,
+		Code:      code,
+		Message:   message,
+		ErrorCode: defaultErrorCode(code),
+	}
+}
+
+// NewApiErrorFromError maps the kind of a domain error to its HTTP status, any other error is an internal error
+func NewApiErrorFromError(err error) *ApiError {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	for _, errorKind := range errorKinds {
+		if !errors.Is(err, errorKind.kind) {
+			continue
+		}
+
+		apiErr = &ApiError{
+			Code:      errorKind.status,
+			Message:   err.Error(),
+			ErrorCode: errorKind.errorCode,
+		}
+
+		var domainErr *model.DomainError
+		if errors.As(err, &domainErr) {
+			for _, param := range domainErr.InvalidParams {
+				apiErr.InvalidParams = append(apiErr.InvalidParams, InvalidParamDTO{Name: param.Name, Reason: param.Reason})
+			}
+		}
+
+		return apiErr
+	}
+
+	return NewApiError(http.StatusInternalServerError, internalErrorMessage)
+}
+
+func defaultErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeValidation
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusBadGateway:
+		return ErrorCodeUpstreamUnavailable
+	case http.StatusInternalServerError:
+		return ErrorCodeInternal
+	default:
+		return ""
 	}
 }
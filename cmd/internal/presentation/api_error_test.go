@@ -1,8 +1,13 @@
 package presentation
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,9 +17,10 @@ func TestNewApiError(t *testing.T) {
 		message  string
 		expected *ApiError
 	}{
-		{400, "Bad Request", &ApiError{400, "Bad Request"}},
-		{404, "Not Found", &ApiError{404, "Not Found"}},
-		{500, "Internal Server Error", &ApiError{500, "Internal Server Error"}},
+		{400, "Bad Request", &ApiError{Code: 400, Message: "Bad Request", ErrorCode: ErrorCodeValidation}},
+		{404, "Not Found", &ApiError{Code: 404, Message: "Not Found", ErrorCode: ErrorCodeNotFound}},
+		{409, "Conflict", &ApiError{Code: 409, Message: "Conflict"}},
+		{500, "Internal Server Error", &ApiError{Code: 500, Message: "Internal Server Error", ErrorCode: ErrorCodeInternal}},
 	}
 
 	for _, tt := range tests {
@@ -26,3 +32,47 @@ func TestNewApiError(t *testing.T) {
 		})
 	}
 }
+
+func TestNewApiErrorFromError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected *ApiError
+	}{
+		{name: "Validation error", err: model.NewValidationError("invalid"), expected: NewApiError(http.StatusBadRequest, "invalid")},
+		{name: "Not found error", err: model.NewNotFoundError("not found"), expected: NewApiError(http.StatusNotFound, "not found")},
+		{name: "Upstream unavailable error", err: model.NewUpstreamUnavailableError("timeout", errors.New("timeout")), expected: NewApiError(http.StatusBadGateway, "timeout")},
+		{name: "Rate not found error", err: model.NewRateNotFoundError("no rate"), expected: &ApiError{Code: http.StatusBadGateway, Message: "no rate", ErrorCode: ErrorCodeExchangeRateNotFound}},
+		{name: "Conflict error", err: model.NewConflictError("in progress"), expected: &ApiError{Code: http.StatusConflict, Message: "in progress", ErrorCode: ErrorCodeConflict}},
+		{name: "Precondition failed error", err: model.NewPreconditionFailedError("changed"), expected: &ApiError{Code: http.StatusPreconditionFailed, Message: "changed", ErrorCode: ErrorCodePreconditionFailed}},
+		{name: "Unsupported media error", err: model.NewUnsupportedMediaError("json"), expected: &ApiError{Code: http.StatusUnsupportedMediaType, Message: "json", ErrorCode: ErrorCodeUnsupportedMedia}},
+		{name: "Idempotency key reuse error", err: model.NewIdempotencyKeyReuseError("reused"), expected: &ApiError{Code: http.StatusUnprocessableEntity, Message: "reused", ErrorCode: ErrorCodeIdempotencyKeyReused}},
+		{
+			name: "Invalid params error",
+			err: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "description", Reason: "invalid description"},
+				{Name: "purchase_amount", Reason: "invalid purchase amount"},
+			}),
+			expected: &ApiError{
+				Code:      http.StatusBadRequest,
+				Message:   "invalid description; invalid purchase amount",
+				ErrorCode: ErrorCodeValidation,
+				InvalidParams: []InvalidParamDTO{
+					{Name: "description", Reason: "invalid description"},
+					{Name: "purchase_amount", Reason: "invalid purchase amount"},
+				},
+			},
+		},
+		{name: "Wrapped domain error", err: fmt.Errorf("wrapped: %w", model.NewNotFoundError("not found")), expected: NewApiError(http.StatusNotFound, "wrapped: not found")},
+		{name: "Api error", err: NewApiError(http.StatusConflict, "conflict"), expected: NewApiError(http.StatusConflict, "conflict")},
+		{name: "Unknown error", err: errors.New("database error"), expected: NewApiError(http.StatusInternalServerError, "internal server error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewApiErrorFromError(tt.err)
+
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
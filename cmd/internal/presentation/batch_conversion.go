@@ -0,0 +1,72 @@
+package presentation
+
+import (
+	"strconv"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+const MaxBatchTransactionIDs = 500
+
+// BatchConversionDTO selects the transactions of a batch conversion by their IDs or by a transaction date range
+type BatchConversionDTO struct {
+	TransactionIDs      []int64 `json:"transaction_ids"`
+	TransactionDateFrom string  `json:"transaction_date_from"`
+	TransactionDateTo   string  `json:"transaction_date_to"`
+}
+
+func (b *BatchConversionDTO) Validate() error {
+	hasIDs := len(b.TransactionIDs) > 0
+	hasDateRange := b.TransactionDateFrom != "" || b.TransactionDateTo != ""
+
+	if hasIDs == hasDateRange {
+		return model.NewValidationError("either transaction_ids or transaction_date_from and transaction_date_to must be informed")
+	}
+
+	if hasIDs {
+		if len(b.TransactionIDs) > MaxBatchTransactionIDs {
+			return model.NewValidationError("invalid transaction_ids, it must have at most " + strconv.Itoa(MaxBatchTransactionIDs) + " transactions")
+		}
+
+		for _, transactionID := range b.TransactionIDs {
+			if transactionID <= 0 {
+				return model.NewValidationError("invalid transaction_ids, transaction ID must be a valid number")
+			}
+		}
+
+		return nil
+	}
+
+	dateFrom, err := util.ParseDate(b.TransactionDateFrom)
+	if err != nil {
+		return model.NewValidationError("invalid transaction_date_from: " + err.Error())
+	}
+
+	dateTo, err := util.ParseDate(b.TransactionDateTo)
+	if err != nil {
+		return model.NewValidationError("invalid transaction_date_to: " + err.Error())
+	}
+
+	if dateFrom.After(dateTo) {
+		return model.NewValidationError("invalid date range, transaction_date_from must be before transaction_date_to")
+	}
+
+	return nil
+}
+
+// ToTransactionFilter returns the filter of the date range or nil when the transactions are selected by ID
+func (b *BatchConversionDTO) ToTransactionFilter() *model.TransactionFilter {
+	if len(b.TransactionIDs) > 0 {
+		return nil
+	}
+
+	dateFrom, _ := util.ParseDate(b.TransactionDateFrom)
+	dateTo, _ := util.ParseDate(b.TransactionDateTo)
+
+	return &model.TransactionFilter{
+		TransactionDateFrom: &dateFrom,
+		TransactionDateTo:   &dateTo,
+		Limit:               MaxPageLimit,
+	}
+}
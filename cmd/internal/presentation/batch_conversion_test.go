@@ -0,0 +1,54 @@
+package presentation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateBatchConversionDTO(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         BatchConversionDTO
+		expectedError error
+	}{
+		{name: "Validate BatchConversionDTO with ids", input: BatchConversionDTO{TransactionIDs: []int64{1, 2}}, expectedError: nil},
+		{name: "Validate BatchConversionDTO with date range", input: BatchConversionDTO{TransactionDateFrom: "2025-01-01T00:00:00Z", TransactionDateTo: "2025-03-31T00:00:00Z"}, expectedError: nil},
+		{name: "Validate BatchConversionDTO without selection", input: BatchConversionDTO{}, expectedError: model.NewValidationError("either transaction_ids or transaction_date_from and transaction_date_to must be informed")},
+		{name: "Validate BatchConversionDTO with ids and date range", input: BatchConversionDTO{TransactionIDs: []int64{1}, TransactionDateFrom: "2025-01-01T00:00:00Z"}, expectedError: model.NewValidationError("either transaction_ids or transaction_date_from and transaction_date_to must be informed")},
+		{name: "Validate BatchConversionDTO with too many ids", input: BatchConversionDTO{TransactionIDs: make([]int64, MaxBatchTransactionIDs+1)}, expectedError: model.NewValidationError("invalid transaction_ids, it must have at most 500 transactions")},
+		{name: "Validate BatchConversionDTO with invalid id", input: BatchConversionDTO{TransactionIDs: []int64{1, 0}}, expectedError: model.NewValidationError("invalid transaction_ids, transaction ID must be a valid number")},
+		{name: "Validate BatchConversionDTO without date to", input: BatchConversionDTO{TransactionDateFrom: "2025-01-01T00:00:00Z"}, expectedError: model.NewValidationError("invalid transaction_date_to: invalid date format expected 2006-01-02T15:04:05Z07:00")},
+		{name: "Validate BatchConversionDTO with inverted date range", input: BatchConversionDTO{TransactionDateFrom: "2025-03-31T00:00:00Z", TransactionDateTo: "2025-01-01T00:00:00Z"}, expectedError: model.NewValidationError("invalid date range, transaction_date_from must be before transaction_date_to")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func Test_BatchConversionDTOToTransactionFilter(t *testing.T) {
+	t.Run("ToTransactionFilter nil with ids", func(t *testing.T) {
+		input := BatchConversionDTO{TransactionIDs: []int64{1}}
+
+		assert.Nil(t, input.ToTransactionFilter())
+	})
+
+	t.Run("ToTransactionFilter with date range", func(t *testing.T) {
+		input := BatchConversionDTO{TransactionDateFrom: "2025-01-01T00:00:00Z", TransactionDateTo: "2025-03-31T00:00:00Z"}
+		dateFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		dateTo := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+		assert.Equal(t, &model.TransactionFilter{
+			TransactionDateFrom: &dateFrom,
+			TransactionDateTo:   &dateTo,
+			Limit:               MaxPageLimit,
+		}, input.ToTransactionFilter())
+	})
+}
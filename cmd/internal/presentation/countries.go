@@ -0,0 +1,52 @@
+package presentation
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+const MaxCountries = 20
+
+// Countries is a comma separated list of country names
+type Countries string
+
+func (c *Countries) Validate() error {
+	if c == nil || strings.TrimSpace(string(*c)) == "" {
+		return model.NewValidationError("countries is required")
+	}
+
+	names := strings.Split(string(*c), ",")
+	if len(names) > MaxCountries {
+		return model.NewValidationError("invalid countries, it must have at most " + strconv.Itoa(MaxCountries) + " countries")
+	}
+
+	for _, name := range names {
+		country := Country(strings.TrimSpace(name))
+		if err := country.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Normalize returns each country name normalized, without duplicates and in the informed order
+func (c *Countries) Normalize() ([]string, error) {
+	countries := []string{}
+	for _, name := range strings.Split(string(*c), ",") {
+		country := Country(strings.TrimSpace(name))
+		normalized, err := country.Normalize()
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(countries, normalized) {
+			countries = append(countries, normalized)
+		}
+	}
+
+	return countries, nil
+}
@@ -0,0 +1,39 @@
+package presentation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateCountries(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         Countries
+		expectedError error
+	}{
+		{name: "Validate Countries with success", input: "Brazil,Canada, Mexico", expectedError: nil},
+		{name: "Validate Countries empty", input: " ", expectedError: model.NewValidationError("countries is required")},
+		{name: "Validate Countries with empty country", input: "Brazil,,Mexico", expectedError: model.NewValidationError("country name is required")},
+		{name: "Validate Countries too many", input: Countries(strings.Repeat("Brazil,", MaxCountries) + "Brazil"), expectedError: model.NewValidationError("invalid countries, it must have at most 20 countries")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func Test_NormalizeCountries(t *testing.T) {
+	input := Countries("brazil, Canada,México,Brazil")
+
+	response, err := input.Normalize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Brazil", "Canada", "Mexico"}, response)
+}
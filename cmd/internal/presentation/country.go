@@ -1,33 +1,36 @@
 package presentation
 
 import (
-	"net/http"
 	"strings"
 	"unicode"
 
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 )
 
 type Country string
 
-func (c *Country) Validate() {
+func (c *Country) Validate() error {
 	if c == nil || *c == "" {
-		panic(NewApiError(http.StatusBadRequest, "country name is required"))
+		return model.NewValidationError("country name is required")
 	}
+
+	return nil
 }
 
-func (c *Country) Normalize() string {
+func (c *Country) Normalize() (string, error) {
 
 	// Remove accents [ñ -> n], [ç -> c], [á -> a]
 	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
 	normalizedString, _, err := transform.String(t, string(*c))
 	if err != nil {
-		panic(NewApiError(http.StatusBadRequest, "country name not in pattern"))
+		return "", model.NewValidationError("country name not in pattern")
 	}
-	
-	return c.toTitleCase(normalizedString)
+
+	return c.toTitleCase(normalizedString), nil
 }
 
 func (c *Country) toTitleCase(s string) string {
@@ -1,9 +1,9 @@
 package presentation
 
 import (
-	"net/http"
 	"testing"
 
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,25 +11,17 @@ func Test_ValidateCountry(t *testing.T) {
 	tests := []struct {
 		name          string
 		input         Country
-		expectedError *ApiError
+		expectedError error
 	}{
 		{name: "Validate Country with success", input: "Brazil", expectedError: nil},
-		{name: "Validate Country throws exception", input: "", expectedError: NewApiError(http.StatusBadRequest, "country name is required")},
+		{name: "Validate Country throws exception", input: "", expectedError: model.NewValidationError("country name is required")},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			defer assertPanicErrors(t, tt.expectedError)
-
-			if tt.expectedError == nil {
-				assert.NotPanics(t, func() {
-					tt.input.Validate()
-				})
-			} else {
-				assert.Panics(t, func() {
-					tt.input.Validate()
-				})
-			}
+			err := tt.input.Validate()
+
+			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
@@ -51,8 +43,9 @@ func Test_NormalizeCountry(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		response := test.input.Normalize()
+		response, err := test.input.Normalize()
 
+		assert.NoError(t, err)
 		assert.Equal(t, test.expected, response)
 	}
 }
@@ -0,0 +1,46 @@
+package presentation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// ETag returns the strong entity tag of a transaction version
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// IfMatch is the If-Match header of a request, empty or "*" accepts any version
+type IfMatch string
+
+func (i *IfMatch) Validate() error {
+	if i == nil || *i == "" || *i == "*" {
+		return nil
+	}
+
+	tag := strings.TrimSpace(string(*i))
+	weak := strings.HasPrefix(tag, "W/")
+	if !isQuotedTag(strings.TrimPrefix(tag, "W/")) {
+		return model.NewValidationError("If-Match must be a single strong ETag, e.g. \"3\", or *")
+	}
+
+	// weak tags never match, If-Match uses the strong comparison
+	version, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+	if weak || err != nil || version <= 0 {
+		return model.NewPreconditionFailedError("If-Match does not match the current version of the transaction")
+	}
+
+	return nil
+}
+
+func isQuotedTag(tag string) bool {
+	return len(tag) >= 2 && strings.HasPrefix(tag, `"`) && strings.HasSuffix(tag, `"`) && !strings.Contains(tag, ",")
+}
+
+// Get returns the version required by the header, 0 when any version is accepted
+func (i *IfMatch) Get() int64 {
+	version, _ := strconv.ParseInt(strings.Trim(strings.TrimSpace(string(*i)), `"`), 10, 64)
+	return version
+}
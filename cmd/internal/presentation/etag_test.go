@@ -0,0 +1,64 @@
+package presentation
+
+import (
+	"testing"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ETag(t *testing.T) {
+	assert.Equal(t, `"3"`, ETag(3))
+}
+
+func Test_IfMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		ifMatch         IfMatch
+		expectedVersion int64
+		expectedError   error
+	}{
+		{name: "Empty accepts any version", ifMatch: "", expectedVersion: 0},
+		{name: "Wildcard accepts any version", ifMatch: "*", expectedVersion: 0},
+		{name: "Strong ETag", ifMatch: `"3"`, expectedVersion: 3},
+		{name: "Strong ETag with spaces", ifMatch: ` "3" `, expectedVersion: 3},
+		{
+			name:          "Weak ETag",
+			ifMatch:       `W/"3"`,
+			expectedError: model.NewPreconditionFailedError("If-Match does not match the current version of the transaction"),
+		},
+		{
+			name:          "Unquoted ETag",
+			ifMatch:       `3`,
+			expectedError: model.NewValidationError("If-Match must be a single strong ETag, e.g. \"3\", or *"),
+		},
+		{
+			name:          "Weak unquoted ETag",
+			ifMatch:       `W/3`,
+			expectedError: model.NewValidationError("If-Match must be a single strong ETag, e.g. \"3\", or *"),
+		},
+		{
+			name:          "List of ETags",
+			ifMatch:       `"3", "4"`,
+			expectedError: model.NewValidationError("If-Match must be a single strong ETag, e.g. \"3\", or *"),
+		},
+		{
+			name:          "ETag of another resource",
+			ifMatch:       `"abc"`,
+			expectedError: model.NewPreconditionFailedError("If-Match does not match the current version of the transaction"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := tt.ifMatch.Validate()
+
+			// Then
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedVersion, tt.ifMatch.Get())
+			}
+		})
+	}
+}
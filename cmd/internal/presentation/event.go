@@ -0,0 +1,23 @@
+package presentation
+
+import (
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// EventDTO is the body of the events published to the downstream consumers, every delivery of an event has the
+// same event_id so the consumers can discard the duplicates
+type EventDTO struct {
+	EventID    int64           `json:"event_id"`
+	Type       string          `json:"type"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       *TransactionDTO `json:"data"`
+}
+
+func NewEventDTO(event *model.OutboxEvent) *EventDTO {
+	return &EventDTO{
+		EventID:    event.ID,
+		Type:       string(event.Type),
+		OccurredAt: formatEventTime(event.OccurredAt),
+		Data:       NewTransactionDTO(event.Transaction),
+	}
+}
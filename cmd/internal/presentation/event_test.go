@@ -0,0 +1,26 @@
+package presentation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewEventDTO(t *testing.T) {
+	// Given
+	occurredAt := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	transaction := &model.Transaction{ID: 1, Description: "mock", TransactionDate: occurredAt, PurchaseAmount: 100, Deleted: true, Version: 2, DeletedAt: &occurredAt}
+
+	// When
+	event := NewEventDTO(&model.OutboxEvent{ID: 3, Type: model.EventTransactionDeleted, TransactionID: 1, Transaction: transaction, OccurredAt: occurredAt})
+
+	// Then
+	assert.Equal(t, &EventDTO{
+		EventID:    3,
+		Type:       "transaction.deleted",
+		OccurredAt: occurredAt.UTC().Format(time.RFC3339Nano),
+		Data:       NewTransactionDTO(transaction),
+	}, event)
+}
@@ -0,0 +1,63 @@
+package presentation
+
+import (
+	"time"
+)
+
+const (
+	HealthUp = "up"
+	// HealthDegraded means a dependency the server works without is down
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+// HealthComponentDTO is the result of the health check of one dependency
+type HealthComponentDTO struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	// CircuitBreaker is the state of the circuit breaker in front of the dependency, when there is one
+	CircuitBreaker string `json:"circuit_breaker,omitempty"`
+}
+
+func NewHealthComponentDTO(critical bool, latency time.Duration, err error) HealthComponentDTO {
+	component := HealthComponentDTO{
+		Status:    HealthUp,
+		Critical:  critical,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		component.Status = HealthDown
+		component.Error = err.Error()
+	}
+
+	return component
+}
+
+// HealthDTO is down when a critical component is down and degraded when any other one is
+type HealthDTO struct {
+	Status     string                        `json:"status"`
+	Components map[string]HealthComponentDTO `json:"components,omitempty"`
+}
+
+func NewHealthDTO(components map[string]HealthComponentDTO) *HealthDTO {
+	status := HealthUp
+	for _, component := range components {
+		if component.Status == HealthUp {
+			continue
+		}
+
+		if component.Critical {
+			status = HealthDown
+			break
+		}
+		status = HealthDegraded
+	}
+
+	return &HealthDTO{
+		Status:     status,
+		Components: components,
+	}
+}
@@ -0,0 +1,55 @@
+package presentation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewHealthComponentDTO(t *testing.T) {
+	t.Run("Component up with the latency in milliseconds", func(t *testing.T) {
+		// When
+		component := NewHealthComponentDTO(true, 1500*time.Microsecond, nil)
+
+		// Then
+		assert.Equal(t, HealthComponentDTO{Status: "up", Critical: true, LatencyMs: 1.5}, component)
+	})
+
+	t.Run("Component down with the error", func(t *testing.T) {
+		// When
+		component := NewHealthComponentDTO(false, 2*time.Second, errors.New("context deadline exceeded"))
+
+		// Then
+		assert.Equal(t, HealthComponentDTO{Status: "down", LatencyMs: 2000, Error: "context deadline exceeded"}, component)
+	})
+}
+
+func Test_NewHealthDTO(t *testing.T) {
+	up := HealthComponentDTO{Status: HealthUp, Critical: true}
+	optionalDown := HealthComponentDTO{Status: HealthDown}
+	criticalDown := HealthComponentDTO{Status: HealthDown, Critical: true}
+
+	tests := []struct {
+		name           string
+		components     map[string]HealthComponentDTO
+		expectedStatus string
+	}{
+		{name: "Up without components", components: nil, expectedStatus: "up"},
+		{name: "Up with every component up", components: map[string]HealthComponentDTO{"database": up, "cache": up}, expectedStatus: "up"},
+		{name: "Degraded with a non critical component down", components: map[string]HealthComponentDTO{"database": up, "treasury": optionalDown}, expectedStatus: "degraded"},
+		{name: "Down with a critical component down", components: map[string]HealthComponentDTO{"database": criticalDown, "treasury": optionalDown}, expectedStatus: "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			health := NewHealthDTO(tt.components)
+
+			// Then
+			assert.Equal(t, tt.expectedStatus, health.Status)
+			assert.Equal(t, tt.components, health.Components)
+		})
+	}
+}
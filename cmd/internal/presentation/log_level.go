@@ -0,0 +1,33 @@
+package presentation
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+type LogLevelDTO struct {
+	Level string `json:"level"`
+}
+
+func NewLogLevelDTO(level slog.Level) *LogLevelDTO {
+	return &LogLevelDTO{
+		Level: strings.ToLower(level.String()),
+	}
+}
+
+func (l *LogLevelDTO) Validate() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return model.NewValidationError("level must be debug, info, warn or error")
+	}
+
+	return nil
+}
+
+func (l *LogLevelDTO) ToLevel() slog.Level {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(l.Level))
+	return level
+}
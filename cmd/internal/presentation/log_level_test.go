@@ -0,0 +1,45 @@
+package presentation
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevelDTO(t *testing.T) {
+	tests := []struct {
+		name          string
+		level         string
+		expectedLevel slog.Level
+		expectedError string
+	}{
+		{name: "Debug level", level: "debug", expectedLevel: slog.LevelDebug},
+		{name: "Uppercase level", level: "WARN", expectedLevel: slog.LevelWarn},
+		{name: "Unknown level", level: "verbose", expectedError: "level must be debug, info, warn or error"},
+		{name: "Empty level", level: "", expectedError: "level must be debug, info, warn or error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			dto := LogLevelDTO{Level: tt.level}
+
+			// When
+			err := dto.Validate()
+
+			// Then
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedLevel, dto.ToLevel())
+		})
+	}
+
+	t.Run("New from level", func(t *testing.T) {
+		assert.Equal(t, &LogLevelDTO{Level: "error"}, NewLogLevelDTO(slog.LevelError))
+	})
+}
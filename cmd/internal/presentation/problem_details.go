@@ -0,0 +1,55 @@
+package presentation
+
+import "net/http"
+
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix identifies the problem types, the error code completes the URI
+const problemTypePrefix = "urn:transaction-api:problem:"
+
+var problemTitles = map[string]string{
+	ErrorCodeValidation:           "Request validation failed",
+	ErrorCodeNotFound:             "Resource not found",
+	ErrorCodeExchangeRateNotFound: "Exchange rate not found",
+	ErrorCodeUpstreamUnavailable:  "Upstream service unavailable",
+	ErrorCodeConflict:             "Request conflicts with the current state",
+	ErrorCodeIdempotencyKeyReused: "Idempotency key reused with a different request",
+	ErrorCodePreconditionFailed:   "Precondition failed",
+	ErrorCodeUnsupportedMedia:     "Unsupported media type",
+	ErrorCodeInternal:             "Internal server error",
+}
+
+// ProblemDetailsDTO is the RFC 7807 body of every error response
+type ProblemDetailsDTO struct {
+	Type          string            `json:"type"`
+	Title         string            `json:"title"`
+	Status        int               `json:"status"`
+	Detail        string            `json:"detail"`
+	Instance      string            `json:"instance,omitempty"`
+	ErrorCode     string            `json:"error_code"`
+	InvalidParams []InvalidParamDTO `json:"invalid_params,omitempty"`
+	RequestID     string            `json:"request_id,omitempty"`
+}
+
+func NewProblemDetailsDTO(apiErr *ApiError, instance string, requestID string) *ProblemDetailsDTO {
+	errorCode := apiErr.ErrorCode
+	if errorCode == "" {
+		errorCode = ErrorCodeInternal
+	}
+
+	title, found := problemTitles[errorCode]
+	if !found {
+		title = http.StatusText(apiErr.Code)
+	}
+
+	return &ProblemDetailsDTO{
+		Type:          problemTypePrefix + errorCode,
+		Title:         title,
+		Status:        apiErr.Code,
+		Detail:        apiErr.Message,
+		Instance:      instance,
+		ErrorCode:     errorCode,
+		InvalidParams: apiErr.InvalidParams,
+		RequestID:     requestID,
+	}
+}
@@ -0,0 +1,57 @@
+package presentation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProblemDetailsDTO(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiErr   *ApiError
+		expected *ProblemDetailsDTO
+	}{
+		{
+			name: "Problem details of a validation error",
+			apiErr: &ApiError{
+				Code:          http.StatusBadRequest,
+				Message:       "invalid description",
+				ErrorCode:     ErrorCodeValidation,
+				InvalidParams: []InvalidParamDTO{{Name: "description", Reason: "invalid description"}},
+			},
+			expected: &ProblemDetailsDTO{
+				Type:          "urn:transaction-api:problem:validation_error",
+				Title:         "Request validation failed",
+				Status:        http.StatusBadRequest,
+				Detail:        "invalid description",
+				Instance:      "/v1/transaction",
+				ErrorCode:     ErrorCodeValidation,
+				InvalidParams: []InvalidParamDTO{{Name: "description", Reason: "invalid description"}},
+				RequestID:     "request-id",
+			},
+		},
+		{
+			name:   "Problem details of an error without error code",
+			apiErr: &ApiError{Code: http.StatusTeapot, Message: "teapot"},
+			expected: &ProblemDetailsDTO{
+				Type:      "urn:transaction-api:problem:internal_error",
+				Title:     "Internal server error",
+				Status:    http.StatusTeapot,
+				Detail:    "teapot",
+				Instance:  "/v1/transaction",
+				ErrorCode: ErrorCodeInternal,
+				RequestID: "request-id",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewProblemDetailsDTO(tt.apiErr, "/v1/transaction", "request-id")
+
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -1,37 +1,84 @@
 package presentation
 
 import (
-	"net/http"
-
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
 )
 
 type TransactionDTO struct {
-	TransactionID   int64   `json:"transaction_id"`
-	Description     string  `json:"description"`
-	TransactionDate string  `json:"transaction_date"`
-	PurchaseAmount  float32 `json:"purchase_amount"`
-	Deleted         bool    `json:"deleted,omitempty"`
+	TransactionID   int64  `json:"transaction_id"`
+	Description     string `json:"description"`
+	TransactionDate string `json:"transaction_date"`
+	PurchaseAmount  Amount `json:"purchase_amount"`
+	Deleted         bool   `json:"deleted,omitempty"`
+	Version         int64  `json:"version,omitempty"`
+	DeletedAt       string `json:"deleted_at,omitempty"`
+	RestoredAt      string `json:"restored_at,omitempty"`
+	RestoredBy      string `json:"restored_by,omitempty"`
 }
 
-func (t *TransactionDTO) Validate() {
-	if t.Description == "" || len(t.Description) > 50 {
-		panic(NewApiError(http.StatusBadRequest, "invalid description, it must be between 1 and 50 characters"))
+func NewTransactionDTO(transaction *model.Transaction) *TransactionDTO {
+	transactionDTO := &TransactionDTO{
+		TransactionID:   transaction.ID,
+		Description:     transaction.Description,
+		TransactionDate: util.FormatDate(transaction.TransactionDate),
+		PurchaseAmount:  Amount(transaction.PurchaseAmount),
+		Deleted:         transaction.Deleted,
+		Version:         transaction.Version,
+		RestoredBy:      transaction.RestoredBy,
+	}
+
+	if transaction.DeletedAt != nil {
+		transactionDTO.DeletedAt = util.FormatDate(*transaction.DeletedAt)
 	}
 
-	if t.TransactionDate == "" {
-		panic(NewApiError(http.StatusBadRequest, "transaction date must not be empty"))
+	if transaction.RestoredAt != nil {
+		transactionDTO.RestoredAt = util.FormatDate(*transaction.RestoredAt)
 	}
 
-	_, err := util.ParseDate(t.TransactionDate)
-	if err != nil {
-		panic(NewApiError(http.StatusBadRequest, err.Error()))
+	return transactionDTO
+}
+
+// Validate checks every field and reports all the invalid ones in the same error
+func (t *TransactionDTO) Validate() error {
+	invalidParams := []model.InvalidParam{}
+	invalidParams = append(invalidParams, validateDescription(t.Description)...)
+	invalidParams = append(invalidParams, validateTransactionDate(t.TransactionDate)...)
+	invalidParams = append(invalidParams, validatePurchaseAmount(t.PurchaseAmount)...)
+
+	if len(invalidParams) > 0 {
+		return model.NewInvalidParamsError(invalidParams)
 	}
 
-	if t.PurchaseAmount <= 0 {
-		panic(NewApiError(http.StatusBadRequest, "invalid purchase amount, it must be greater than 0"))
+	return nil
+}
+
+func validateDescription(description string) []model.InvalidParam {
+	if description == "" || len(description) > 50 {
+		return []model.InvalidParam{{Name: "description", Reason: "invalid description, it must be between 1 and 50 characters"}}
 	}
+
+	return nil
+}
+
+func validateTransactionDate(transactionDate string) []model.InvalidParam {
+	if transactionDate == "" {
+		return []model.InvalidParam{{Name: "transaction_date", Reason: "transaction date must not be empty"}}
+	}
+
+	if _, err := util.ParseDate(transactionDate); err != nil {
+		return []model.InvalidParam{{Name: "transaction_date", Reason: err.Error()}}
+	}
+
+	return nil
+}
+
+func validatePurchaseAmount(purchaseAmount Amount) []model.InvalidParam {
+	if purchaseAmount <= 0 {
+		return []model.InvalidParam{{Name: "purchase_amount", Reason: "invalid purchase amount, it must be greater than 0"}}
+	}
+
+	return nil
 }
 
 func (t *TransactionDTO) ToTransaction() *model.Transaction {
@@ -41,6 +88,6 @@ func (t *TransactionDTO) ToTransaction() *model.Transaction {
 		ID:              t.TransactionID,
 		Description:     t.Description,
 		TransactionDate: date,
-		PurchaseAmount:  util.RoundPurchaseAmount(t.PurchaseAmount),
+		PurchaseAmount:  int64(t.PurchaseAmount),
 	}
 }
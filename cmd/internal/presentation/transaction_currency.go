@@ -1,10 +1,23 @@
 package presentation
 
+import "encoding/json"
+
 type TransactionCurrencyDTO struct {
-	TransactionID           int64   `json:"transaction_id"`
-	Description             string  `json:"description"`
-	TransactionDate         string  `json:"transaction_date"`
-	PurchaseAmount          float32 `json:"purchase_amount"`
-	ExchangeRate            float32 `json:"exchange_rate"`
-	ConvertedPurchaseAmount float32 `json:"converted_purchase_amount"`
+	TransactionID           int64       `json:"transaction_id"`
+	Description             string      `json:"description"`
+	TransactionDate         string      `json:"transaction_date"`
+	PurchaseAmount          Amount      `json:"purchase_amount"`
+	ExchangeRate            json.Number `json:"exchange_rate"`
+	ConvertedPurchaseAmount Amount      `json:"converted_purchase_amount"`
+	EffectiveDate           string      `json:"effective_date"`
+	Source                  string      `json:"source"`
+}
+
+// TransactionCurrencyResultDTO is the conversion of one transaction to the currency of one country, when
+// the conversion fails the error is filled instead
+type TransactionCurrencyResultDTO struct {
+	TransactionID int64                   `json:"transaction_id,omitempty"`
+	Country       string                  `json:"country"`
+	Conversion    *TransactionCurrencyDTO `json:"conversion,omitempty"`
+	Error         *ApiError               `json:"error,omitempty"`
 }
@@ -0,0 +1,135 @@
+package presentation
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// TransactionFilterDTO holds the raw query parameters of the transaction list endpoint
+type TransactionFilterDTO struct {
+	TransactionDateFrom string
+	TransactionDateTo   string
+	PurchaseAmountMin   string
+	PurchaseAmountMax   string
+	Description         string
+	IncludeDeleted      string
+	Limit               string
+	Offset              string
+}
+
+func NewTransactionFilterDTO(query url.Values) *TransactionFilterDTO {
+	return &TransactionFilterDTO{
+		TransactionDateFrom: query.Get("transaction_date_from"),
+		TransactionDateTo:   query.Get("transaction_date_to"),
+		PurchaseAmountMin:   query.Get("purchase_amount_min"),
+		PurchaseAmountMax:   query.Get("purchase_amount_max"),
+		Description:         query.Get("description"),
+		IncludeDeleted:      query.Get("include_deleted"),
+		Limit:               query.Get("limit"),
+		Offset:              query.Get("offset"),
+	}
+}
+
+func (f *TransactionFilterDTO) Validate() error {
+	if f.TransactionDateFrom != "" {
+		if _, err := util.ParseDate(f.TransactionDateFrom); err != nil {
+			return model.NewValidationError("invalid transaction_date_from: " + err.Error())
+		}
+	}
+
+	if f.TransactionDateTo != "" {
+		if _, err := util.ParseDate(f.TransactionDateTo); err != nil {
+			return model.NewValidationError("invalid transaction_date_to: " + err.Error())
+		}
+	}
+
+	if f.PurchaseAmountMin != "" {
+		if _, err := util.ParseAmount(f.PurchaseAmountMin); err != nil {
+			return model.NewValidationError("invalid purchase_amount_min, it must be a number")
+		}
+	}
+
+	if f.PurchaseAmountMax != "" {
+		if _, err := util.ParseAmount(f.PurchaseAmountMax); err != nil {
+			return model.NewValidationError("invalid purchase_amount_max, it must be a number")
+		}
+	}
+
+	if len(f.Description) > 50 {
+		return model.NewValidationError("invalid description, it must have at most 50 characters")
+	}
+
+	if f.IncludeDeleted != "" {
+		if _, err := strconv.ParseBool(f.IncludeDeleted); err != nil {
+			return model.NewValidationError("invalid include_deleted, it must be true or false")
+		}
+	}
+
+	return validatePage(f.Limit, f.Offset)
+}
+
+func (f *TransactionFilterDTO) ToTransactionFilter() *model.TransactionFilter {
+	filter := &model.TransactionFilter{
+		Description: f.Description,
+	}
+
+	if date, err := util.ParseDate(f.TransactionDateFrom); err == nil {
+		filter.TransactionDateFrom = &date
+	}
+
+	if date, err := util.ParseDate(f.TransactionDateTo); err == nil {
+		filter.TransactionDateTo = &date
+	}
+
+	if amountMin, err := util.ParseAmount(f.PurchaseAmountMin); err == nil {
+		filter.PurchaseAmountMin = &amountMin
+	}
+
+	if amountMax, err := util.ParseAmount(f.PurchaseAmountMax); err == nil {
+		filter.PurchaseAmountMax = &amountMax
+	}
+
+	filter.IncludeDeleted, _ = strconv.ParseBool(f.IncludeDeleted)
+
+	filter.Limit, filter.Offset = parsePage(f.Limit, f.Offset)
+
+	return filter
+}
+
+// validatePage checks the limit and offset query parameters of a paginated list
+func validatePage(limit, offset string) error {
+	if limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 || value > MaxPageLimit {
+			return model.NewValidationError("invalid limit, it must be between 1 and " + strconv.Itoa(MaxPageLimit))
+		}
+	}
+
+	if offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			return model.NewValidationError("invalid offset, it must be greater than or equal to 0")
+		}
+	}
+
+	return nil
+}
+
+// parsePage returns the limit and offset of a validated page, DefaultPageLimit when the limit is not informed
+func parsePage(limit, offset string) (int, int) {
+	pageLimit, err := strconv.Atoi(limit)
+	if err != nil {
+		pageLimit = DefaultPageLimit
+	}
+
+	pageOffset, _ := strconv.Atoi(offset)
+	return pageLimit, pageOffset
+}
@@ -0,0 +1,84 @@
+package presentation
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionFilterDTO_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedError error
+	}{
+		{name: "Validate filter with success without parameters", query: "", expectedError: nil},
+		{name: "Validate filter with success with all parameters", query: "transaction_date_from=2023-01-01T00:00:00Z&transaction_date_to=2023-12-31T00:00:00Z&purchase_amount_min=1&purchase_amount_max=10.5&description=mock&include_deleted=true&limit=10&offset=20", expectedError: nil},
+		{name: "Validate filter invalid transaction_date_from", query: "transaction_date_from=2023-01-01", expectedError: model.NewValidationError("invalid transaction_date_from: invalid date format expected 2006-01-02T15:04:05Z07:00")},
+		{name: "Validate filter invalid transaction_date_to", query: "transaction_date_to=mock", expectedError: model.NewValidationError("invalid transaction_date_to: invalid date format expected 2006-01-02T15:04:05Z07:00")},
+		{name: "Validate filter invalid purchase_amount_min", query: "purchase_amount_min=mock", expectedError: model.NewValidationError("invalid purchase_amount_min, it must be a number")},
+		{name: "Validate filter invalid purchase_amount_max", query: "purchase_amount_max=mock", expectedError: model.NewValidationError("invalid purchase_amount_max, it must be a number")},
+		{name: "Validate filter invalid include_deleted", query: "include_deleted=mock", expectedError: model.NewValidationError("invalid include_deleted, it must be true or false")},
+		{name: "Validate filter invalid limit", query: "limit=101", expectedError: model.NewValidationError("invalid limit, it must be between 1 and 100")},
+		{name: "Validate filter invalid offset", query: "offset=-1", expectedError: model.NewValidationError("invalid offset, it must be greater than or equal to 0")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			filter := NewTransactionFilterDTO(query)
+
+			err = filter.Validate()
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func Test_TransactionFilterDTO_ToTransactionFilter(t *testing.T) {
+	dateFrom := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	amountMin := int64(100)
+	amountMax := int64(1050)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected *model.TransactionFilter
+	}{
+		{
+			name:     "Convert filter with default values",
+			query:    "",
+			expected: &model.TransactionFilter{Limit: DefaultPageLimit},
+		},
+		{
+			name:  "Convert filter with all parameters",
+			query: "transaction_date_from=2023-01-01T00:00:00Z&transaction_date_to=2023-12-31T00:00:00Z&purchase_amount_min=1&purchase_amount_max=10.5&description=mock&include_deleted=true&limit=10&offset=20",
+			expected: &model.TransactionFilter{
+				TransactionDateFrom: &dateFrom,
+				TransactionDateTo:   &dateTo,
+				PurchaseAmountMin:   &amountMin,
+				PurchaseAmountMax:   &amountMax,
+				Description:         "mock",
+				IncludeDeleted:      true,
+				Limit:               10,
+				Offset:              20,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+
+			filter := NewTransactionFilterDTO(query).ToTransactionFilter()
+
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
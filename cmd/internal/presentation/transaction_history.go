@@ -0,0 +1,99 @@
+package presentation
+
+import (
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+// TransactionHistoryDTO is the transaction as it was at a point in time and the events that led to it, the
+// transaction is null when it was purged
+type TransactionHistoryDTO struct {
+	TransactionID int64                 `json:"transaction_id"`
+	AsOf          string                `json:"as_of,omitempty"`
+	Transaction   *TransactionDTO       `json:"transaction"`
+	Events        []TransactionEventDTO `json:"events"`
+}
+
+// NewTransactionHistoryDTO reconstructs the transaction from the new value of the last event, the events must be
+// the ones until asOf in the order they happened
+func NewTransactionHistoryDTO(transactionID int64, asOf *time.Time, events []model.TransactionEvent) *TransactionHistoryDTO {
+	historyDTO := &TransactionHistoryDTO{
+		TransactionID: transactionID,
+		Events:        make([]TransactionEventDTO, 0, len(events)),
+	}
+
+	if asOf != nil {
+		historyDTO.AsOf = formatEventTime(*asOf)
+	}
+
+	for _, event := range events {
+		historyDTO.Events = append(historyDTO.Events, NewTransactionEventDTO(&event))
+	}
+
+	if len(historyDTO.Events) > 0 {
+		historyDTO.Transaction = historyDTO.Events[len(historyDTO.Events)-1].NewValue
+	}
+
+	return historyDTO
+}
+
+type TransactionEventDTO struct {
+	EventID    int64           `json:"event_id"`
+	Operation  string          `json:"operation"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id,omitempty"`
+	OccurredAt string          `json:"occurred_at"`
+	OldValue   *TransactionDTO `json:"old_value"`
+	NewValue   *TransactionDTO `json:"new_value"`
+}
+
+func NewTransactionEventDTO(event *model.TransactionEvent) TransactionEventDTO {
+	eventDTO := TransactionEventDTO{
+		EventID:    event.ID,
+		Operation:  string(event.Operation),
+		Actor:      event.Actor,
+		RequestID:  event.RequestID,
+		OccurredAt: formatEventTime(event.OccurredAt),
+	}
+
+	if event.OldValue != nil {
+		eventDTO.OldValue = NewTransactionDTO(event.OldValue)
+	}
+
+	if event.NewValue != nil {
+		eventDTO.NewValue = NewTransactionDTO(event.NewValue)
+	}
+
+	return eventDTO
+}
+
+// AsOf is the point in time to reconstruct a transaction, empty means now
+type AsOf string
+
+func (a *AsOf) Validate() error {
+	if a == nil || *a == "" {
+		return nil
+	}
+
+	if _, err := util.ParseDate(string(*a)); err != nil {
+		return model.NewValidationError("invalid as_of: " + err.Error())
+	}
+
+	return nil
+}
+
+func (a *AsOf) Get() *time.Time {
+	if a == nil || *a == "" {
+		return nil
+	}
+
+	asOf, _ := util.ParseDate(string(*a))
+	return &asOf
+}
+
+// formatEventTime keeps the fraction of the seconds in UTC, so an event time can be used as as_of
+func formatEventTime(occurredAt time.Time) string {
+	return occurredAt.UTC().Format(time.RFC3339Nano)
+}
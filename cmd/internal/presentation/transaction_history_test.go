@@ -0,0 +1,86 @@
+package presentation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AsOf_Validate(t *testing.T) {
+	asOf := time.Date(2025, 5, 10, 12, 0, 0, 500000000, time.UTC)
+
+	tests := []struct {
+		name          string
+		input         AsOf
+		expected      *time.Time
+		expectedError error
+	}{
+		{name: "Validate AsOf empty means now", input: "", expected: nil},
+		{name: "Validate AsOf with success", input: "2025-05-10T12:00:00.5Z", expected: &asOf},
+		{name: "Validate AsOf not a date", input: "yesterday", expectedError: model.NewValidationError("invalid as_of: invalid date format expected 2006-01-02T15:04:05Z07:00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+
+			assert.Equal(t, tt.expectedError, err)
+			if err == nil {
+				assert.Equal(t, tt.expected, tt.input.Get())
+			}
+		})
+	}
+}
+
+func Test_NewTransactionHistoryDTO(t *testing.T) {
+	occurredAt := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	created := &model.Transaction{ID: 1, Description: "created", TransactionDate: occurredAt, PurchaseAmount: 100, Version: 1}
+	deleted := &model.Transaction{ID: 1, Description: "created", TransactionDate: occurredAt, PurchaseAmount: 100, Deleted: true, Version: 2, DeletedAt: &occurredAt}
+
+	t.Run("The transaction is the new value of the last event", func(t *testing.T) {
+		// When
+		history := NewTransactionHistoryDTO(1, &occurredAt, []model.TransactionEvent{
+			{ID: 1, TransactionID: 1, Operation: model.OperationCreate, Actor: "alice", OccurredAt: occurredAt, NewValue: created},
+			{ID: 2, TransactionID: 1, Operation: model.OperationDelete, Actor: "bob", RequestID: "request-2", OccurredAt: occurredAt, OldValue: created, NewValue: deleted},
+		})
+
+		// Then
+		assert.Equal(t, int64(1), history.TransactionID)
+		assert.Equal(t, occurredAt.UTC().Format(time.RFC3339Nano), history.AsOf)
+		assert.Equal(t, NewTransactionDTO(deleted), history.Transaction)
+		assert.Equal(t, TransactionEventDTO{
+			EventID:    2,
+			Operation:  "delete",
+			Actor:      "bob",
+			RequestID:  "request-2",
+			OccurredAt: occurredAt.UTC().Format(time.RFC3339Nano),
+			OldValue:   NewTransactionDTO(created),
+			NewValue:   NewTransactionDTO(deleted),
+		}, history.Events[1])
+	})
+
+	t.Run("The transaction is null after it was purged", func(t *testing.T) {
+		// When
+		history := NewTransactionHistoryDTO(1, nil, []model.TransactionEvent{
+			{ID: 1, TransactionID: 1, Operation: model.OperationPurge, Actor: "purge-job", OccurredAt: occurredAt, OldValue: deleted},
+		})
+
+		// Then
+		assert.Empty(t, history.AsOf)
+		assert.Nil(t, history.Transaction)
+		assert.Len(t, history.Events, 1)
+	})
+}
+
+func Test_formatEventTime(t *testing.T) {
+	// Given
+	occurredAt := time.Date(2025, 5, 10, 9, 0, 0, 123456000, time.FixedZone("UTC-3", -3*60*60))
+
+	// When
+	formatted := formatEventTime(occurredAt)
+
+	// Then
+	assert.Equal(t, "2025-05-10T12:00:00.123456Z", formatted)
+}
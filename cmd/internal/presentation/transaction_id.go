@@ -1,25 +1,28 @@
 package presentation
 
 import (
-	"net/http"
 	"strconv"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 )
 
 type TransactionID string
 
-func (t *TransactionID) Validate() {
+func (t *TransactionID) Validate() error {
 	if t == nil || *t == "" {
-		panic(NewApiError(http.StatusBadRequest, "transaction ID is required"))
+		return model.NewValidationError("transaction ID is required")
 	}
 
 	id, err := strconv.ParseInt(string(*t), 10, 64)
 	if err != nil {
-		panic(NewApiError(http.StatusBadRequest, "transaction ID must be a valid number: "+err.Error()))
+		return model.NewValidationError("transaction ID must be a valid number: " + err.Error())
 	}
 
 	if id <= 0 {
-		panic(NewApiError(http.StatusBadRequest, "transaction ID must be a valid number"))
+		return model.NewValidationError("transaction ID must be a valid number")
 	}
+
+	return nil
 }
 
 func (t *TransactionID) Get() int64 {
@@ -1,9 +1,9 @@
 package presentation
 
 import (
-	"net/http"
 	"testing"
 
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,27 +11,19 @@ func Test_TransactionID_Validate(t *testing.T) {
 	tests := []struct {
 		name          string
 		input         TransactionID
-		expectedError *ApiError
+		expectedError error
 	}{
 		{name: "Validate TransactionID with success", input: "1", expectedError: nil},
-		{name: "Validate TransactionID empty", input: "", expectedError: NewApiError(http.StatusBadRequest, "transaction ID is required")},
-		{name: "Validate TransactionID not a number", input: "NaN", expectedError: NewApiError(http.StatusBadRequest, "transaction ID must be a valid number: mock error")},
-		{name: "Validate TransactionID negative", input: "-1", expectedError: NewApiError(http.StatusBadRequest, "transaction ID must be a valid number")},
+		{name: "Validate TransactionID empty", input: "", expectedError: model.NewValidationError("transaction ID is required")},
+		{name: "Validate TransactionID not a number", input: "NaN", expectedError: model.NewValidationError("transaction ID must be a valid number: strconv.ParseInt: parsing \"NaN\": invalid syntax")},
+		{name: "Validate TransactionID negative", input: "-1", expectedError: model.NewValidationError("transaction ID must be a valid number")},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			defer assertPanicErrors(t, tt.expectedError)
-
-			if tt.expectedError == nil {
-				assert.NotPanics(t, func() {
-					tt.input.Validate()
-				})
-			} else {
-				assert.Panics(t, func() {
-					tt.input.Validate()
-				})
-			}
+			err := tt.input.Validate()
+
+			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
@@ -0,0 +1,15 @@
+package presentation
+
+type TransactionPageDTO struct {
+	Data   []TransactionDTO `json:"data"`
+	Total  int64            `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+	Links  PageLinksDTO     `json:"links"`
+}
+
+type PageLinksDTO struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
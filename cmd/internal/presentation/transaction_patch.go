@@ -0,0 +1,109 @@
+package presentation
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+// TransactionPatchDTO is a JSON Merge Patch (RFC 7396) of a transaction, only the fields present are changed
+type TransactionPatchDTO struct {
+	Description     *string
+	TransactionDate *string
+	PurchaseAmount  *Amount
+
+	// every field of a transaction is required, so removing one with null is invalid
+	nullFields    []string
+	unknownFields []string
+}
+
+func (t *TransactionPatchDTO) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for name, value := range fields {
+		if string(value) == "null" {
+			t.nullFields = append(t.nullFields, name)
+			continue
+		}
+
+		var err error
+		switch name {
+		case "description":
+			err = json.Unmarshal(value, &t.Description)
+		case "transaction_date":
+			err = json.Unmarshal(value, &t.TransactionDate)
+		case "purchase_amount":
+			err = json.Unmarshal(value, &t.PurchaseAmount)
+		default:
+			t.unknownFields = append(t.unknownFields, name)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks only the fields present and reports all the invalid ones in the same error
+func (t *TransactionPatchDTO) Validate() error {
+	invalidParams := []model.InvalidParam{}
+
+	slices.Sort(t.unknownFields)
+	for _, name := range t.unknownFields {
+		invalidParams = append(invalidParams, model.InvalidParam{Name: name, Reason: name + " cannot be changed"})
+	}
+
+	slices.Sort(t.nullFields)
+	for _, name := range t.nullFields {
+		invalidParams = append(invalidParams, model.InvalidParam{Name: name, Reason: name + " cannot be removed"})
+	}
+
+	if t.Description != nil {
+		invalidParams = append(invalidParams, validateDescription(*t.Description)...)
+	}
+
+	if t.TransactionDate != nil {
+		invalidParams = append(invalidParams, validateTransactionDate(*t.TransactionDate)...)
+	}
+
+	if t.PurchaseAmount != nil {
+		invalidParams = append(invalidParams, validatePurchaseAmount(*t.PurchaseAmount)...)
+	}
+
+	if len(invalidParams) > 0 {
+		return model.NewInvalidParamsError(invalidParams)
+	}
+
+	if t.Description == nil && t.TransactionDate == nil && t.PurchaseAmount == nil {
+		return model.NewValidationError("the patch must have at least one field to change")
+	}
+
+	return nil
+}
+
+func (t *TransactionPatchDTO) ToTransactionPatch() *model.TransactionPatch {
+	patch := &model.TransactionPatch{
+		Description: t.Description,
+	}
+
+	if t.TransactionDate != nil {
+		date, _ := util.ParseDate(*t.TransactionDate)
+		patch.TransactionDate = &date
+	}
+
+	if t.PurchaseAmount != nil {
+		purchaseAmount := int64(*t.PurchaseAmount)
+		patch.PurchaseAmount = &purchaseAmount
+	}
+
+	return patch
+}
@@ -0,0 +1,76 @@
+package presentation
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionPatchDTO_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectedError error
+	}{
+		{name: "Only the description", body: `{"description":"fixed typo"}`},
+		{name: "Every field", body: `{"description":"fixed typo","transaction_date":"2018-09-26T10:36:40Z","purchase_amount":"10.50"}`},
+		{
+			name:          "Invalid description",
+			body:          `{"description":""}`,
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{{Name: "description", Reason: "invalid description, it must be between 1 and 50 characters"}}),
+		},
+		{
+			name: "Invalid date and amount",
+			body: `{"transaction_date":"invalid-date","purchase_amount":0}`,
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "transaction_date", Reason: "invalid date format expected 2006-01-02T15:04:05Z07:00"},
+				{Name: "purchase_amount", Reason: "invalid purchase amount, it must be greater than 0"},
+			}),
+		},
+		{
+			name: "Remove and change read only fields",
+			body: `{"purchase_amount":null,"transaction_id":2,"deleted":false}`,
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "deleted", Reason: "deleted cannot be changed"},
+				{Name: "transaction_id", Reason: "transaction_id cannot be changed"},
+				{Name: "purchase_amount", Reason: "purchase_amount cannot be removed"},
+			}),
+		},
+		{
+			name:          "Empty patch",
+			body:          `{}`,
+			expectedError: model.NewValidationError("the patch must have at least one field to change"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			var patchDTO TransactionPatchDTO
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &patchDTO))
+
+			// When
+			err := patchDTO.Validate()
+
+			// Then
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func Test_TransactionPatchDTO_ToTransactionPatch(t *testing.T) {
+	// Given
+	var patchDTO TransactionPatchDTO
+	assert.NoError(t, json.Unmarshal([]byte(`{"transaction_date":"2018-09-26T10:36:40Z","purchase_amount":10.5}`), &patchDTO))
+
+	// When
+	patch := patchDTO.ToTransactionPatch()
+
+	// Then
+	transactionDate := time.Date(2018, 9, 26, 10, 36, 40, 0, time.UTC)
+	purchaseAmount := int64(1050)
+	assert.Equal(t, &model.TransactionPatch{TransactionDate: &transactionDate, PurchaseAmount: &purchaseAmount}, patch)
+}
@@ -1,7 +1,6 @@
 package presentation
 
 import (
-	"net/http"
 	"testing"
 	"time"
 
@@ -13,14 +12,14 @@ func TestValidateRequest(t *testing.T) {
 	tests := []struct {
 		name          string
 		dto           TransactionDTO
-		expectedError *ApiError
+		expectedError error
 	}{
 		{
 			name: "Validate Request with success",
 			dto: TransactionDTO{
 				Description:     "Valid Description",
 				TransactionDate: "2018-09-26T10:36:40Z",
-				PurchaseAmount:  100.0,
+				PurchaseAmount:  10000,
 			},
 			expectedError: nil,
 		},
@@ -29,61 +28,66 @@ func TestValidateRequest(t *testing.T) {
 			dto: TransactionDTO{
 				Description:     "",
 				TransactionDate: "2018-09-26T10:36:40Z",
-				PurchaseAmount:  100.0,
+				PurchaseAmount:  10000,
 			},
-			expectedError: NewApiError(http.StatusBadRequest, "invalid description, it must be between 1 and 50 characters"),
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{{Name: "description", Reason: "invalid description, it must be between 1 and 50 characters"}}),
 		},
 		{
 			name: "Validate Request error, description too long",
 			dto: TransactionDTO{
 				Description:     "This description is way too long and exceeds the fifty character limit",
 				TransactionDate: "2018-09-26T10:36:40Z",
-				PurchaseAmount:  100.0,
+				PurchaseAmount:  10000,
 			},
-			expectedError: NewApiError(http.StatusBadRequest, "invalid description, it must be between 1 and 50 characters"),
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{{Name: "description", Reason: "invalid description, it must be between 1 and 50 characters"}}),
 		},
 		{
 			name: "Validate Request error, empty transaction date",
 			dto: TransactionDTO{
 				Description:     "Valid Description",
 				TransactionDate: "",
-				PurchaseAmount:  100.0,
+				PurchaseAmount:  10000,
 			},
-			expectedError: NewApiError(http.StatusBadRequest, "transaction date must not be empty"),
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{{Name: "transaction_date", Reason: "transaction date must not be empty"}}),
 		},
 		{
 			name: "Validate Request error, invalid transaction date",
 			dto: TransactionDTO{
 				Description:     "Valid Description",
 				TransactionDate: "invalid-date",
-				PurchaseAmount:  100.0,
+				PurchaseAmount:  10000,
 			},
-			expectedError: NewApiError(http.StatusBadRequest, "invalid date format expected 2006-01-02T15:04:05Z07:00"),
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{{Name: "transaction_date", Reason: "invalid date format expected 2006-01-02T15:04:05Z07:00"}}),
 		},
 		{
 			name: "Validate Request error, invalid purchase amount",
 			dto: TransactionDTO{
 				Description:     "Valid Description",
 				TransactionDate: "2018-09-26T10:36:40Z",
-				PurchaseAmount:  -10.0,
+				PurchaseAmount:  -1000,
 			},
-			expectedError: NewApiError(http.StatusBadRequest, "invalid purchase amount, it must be greater than 0"),
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{{Name: "purchase_amount", Reason: "invalid purchase amount, it must be greater than 0"}}),
+		},
+		{
+			name: "Validate Request error, every field invalid",
+			dto: TransactionDTO{
+				Description:     "",
+				TransactionDate: "invalid-date",
+				PurchaseAmount:  0,
+			},
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "description", Reason: "invalid description, it must be between 1 and 50 characters"},
+				{Name: "transaction_date", Reason: "invalid date format expected 2006-01-02T15:04:05Z07:00"},
+				{Name: "purchase_amount", Reason: "invalid purchase amount, it must be greater than 0"},
+			}),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			defer assertPanicErrors(t, tt.expectedError)
+			err := tt.dto.Validate()
 
-			if tt.expectedError == nil {
-				assert.NotPanics(t, func() {
-					tt.dto.Validate()
-				})
-			} else {
-				assert.Panics(t, func() {
-					tt.dto.Validate()
-				})
-			}
+			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
@@ -99,13 +103,13 @@ func TestToTransaction(t *testing.T) {
 				TransactionID:   1,
 				Description:     "Valid Description",
 				TransactionDate: "2018-09-26T10:36:40Z",
-				PurchaseAmount:  100.0,
+				PurchaseAmount:  10000,
 			},
 			expected: model.Transaction{
 				ID:              1,
 				Description:     "Valid Description",
 				TransactionDate: time.Date(2018, 9, 26, 10, 36, 40, 0, time.UTC),
-				PurchaseAmount:  100.0,
+				PurchaseAmount:  10000,
 			},
 		},
 	}
@@ -121,9 +125,3 @@ func TestToTransaction(t *testing.T) {
 		})
 	}
 }
-
-func assertPanicErrors(t *testing.T, expectedError *ApiError) {
-	if r := recover(); r != nil {
-		assert.Equal(t, expectedError, r)
-	}
-}
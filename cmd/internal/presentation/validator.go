@@ -1,5 +1,5 @@
 package presentation
 
 type Validator interface {
-	Validate()
+	Validate() error
 }
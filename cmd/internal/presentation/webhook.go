@@ -0,0 +1,194 @@
+package presentation
+
+import (
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+const (
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 255
+)
+
+// WebhookSubscriptionDTO registers a webhook, the secret signs the deliveries and it is never returned
+type WebhookSubscriptionDTO struct {
+	WebhookID           int64    `json:"webhook_id"`
+	URL                 string   `json:"url"`
+	Events              []string `json:"events"`
+	Secret              string   `json:"secret,omitempty"`
+	Active              bool     `json:"active"`
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	DisabledAt          string   `json:"disabled_at,omitempty"`
+	CreatedAt           string   `json:"created_at"`
+}
+
+func NewWebhookSubscriptionDTO(subscription *model.WebhookSubscription) *WebhookSubscriptionDTO {
+	events := make([]string, 0, len(subscription.EventTypes))
+	for _, eventType := range subscription.EventTypes {
+		events = append(events, string(eventType))
+	}
+
+	subscriptionDTO := &WebhookSubscriptionDTO{
+		WebhookID:           subscription.ID,
+		URL:                 subscription.URL,
+		Events:              events,
+		Active:              subscription.Active,
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		CreatedAt:           formatEventTime(subscription.CreatedAt),
+	}
+
+	if subscription.DisabledAt != nil {
+		subscriptionDTO.DisabledAt = formatEventTime(*subscription.DisabledAt)
+	}
+
+	return subscriptionDTO
+}
+
+// Validate checks every field and reports all the invalid ones in the same error
+func (w *WebhookSubscriptionDTO) Validate() error {
+	invalidParams := []model.InvalidParam{}
+
+	if webhookURL, err := url.Parse(w.URL); err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		invalidParams = append(invalidParams, model.InvalidParam{Name: "url", Reason: "invalid url, it must be an absolute http or https URL"})
+	} else if !isPublicHost(webhookURL.Hostname()) {
+		invalidParams = append(invalidParams, model.InvalidParam{Name: "url", Reason: "invalid url, it must not point to a loopback, private or link-local address"})
+	}
+
+	if len(w.Events) == 0 {
+		invalidParams = append(invalidParams, model.InvalidParam{Name: "events", Reason: "events must not be empty, use \"*\" to receive every event"})
+	}
+
+	for _, event := range w.Events {
+		if !model.EventType(event).Subscribable() {
+			invalidParams = append(invalidParams, model.InvalidParam{Name: "events", Reason: "invalid event " + strconv.Quote(event)})
+		}
+	}
+
+	if len(w.Secret) < minWebhookSecretLength || len(w.Secret) > maxWebhookSecretLength {
+		invalidParams = append(invalidParams, model.InvalidParam{
+			Name:   "secret",
+			Reason: "invalid secret, it must be between " + strconv.Itoa(minWebhookSecretLength) + " and " + strconv.Itoa(maxWebhookSecretLength) + " characters",
+		})
+	}
+
+	if len(invalidParams) > 0 {
+		return model.NewInvalidParamsError(invalidParams)
+	}
+
+	return nil
+}
+
+// isPublicHost rejects the hosts that are internal addresses by themselves, the names are checked again on each
+// delivery after they are resolved
+func isPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return util.IsPublicAddress(addr)
+	}
+
+	return true
+}
+
+func (w *WebhookSubscriptionDTO) ToWebhookSubscription() *model.WebhookSubscription {
+	eventTypes := make([]model.EventType, 0, len(w.Events))
+	for _, event := range w.Events {
+		eventTypes = append(eventTypes, model.EventType(event))
+	}
+
+	return &model.WebhookSubscription{
+		URL:        w.URL,
+		EventTypes: eventTypes,
+		Secret:     w.Secret,
+	}
+}
+
+// WebhookDeliveryDTO is one entry of the delivery log of a webhook
+type WebhookDeliveryDTO struct {
+	DeliveryID     int64  `json:"delivery_id"`
+	EventID        int64  `json:"event_id"`
+	EventType      string `json:"event_type"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  string `json:"next_attempt_at,omitempty"`
+	LastStatusCode int    `json:"last_status_code,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	DeliveredAt    string `json:"delivered_at,omitempty"`
+}
+
+func NewWebhookDeliveryDTO(delivery *model.WebhookDelivery) *WebhookDeliveryDTO {
+	deliveryDTO := &WebhookDeliveryDTO{
+		DeliveryID:     delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      formatEventTime(delivery.CreatedAt),
+	}
+
+	if delivery.Status == model.DeliveryPending {
+		deliveryDTO.NextAttemptAt = formatEventTime(delivery.NextAttemptAt)
+	}
+
+	if delivery.DeliveredAt != nil {
+		deliveryDTO.DeliveredAt = formatEventTime(*delivery.DeliveredAt)
+	}
+
+	return deliveryDTO
+}
+
+type WebhookDeliveryPageDTO struct {
+	Data   []WebhookDeliveryDTO `json:"data"`
+	Total  int64                `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+	Links  PageLinksDTO         `json:"links"`
+}
+
+// PageDTO holds the raw limit and offset query parameters of a paginated list
+type PageDTO struct {
+	Limit  string
+	Offset string
+}
+
+func NewPageDTO(query url.Values) *PageDTO {
+	return &PageDTO{
+		Limit:  query.Get("limit"),
+		Offset: query.Get("offset"),
+	}
+}
+
+func (p *PageDTO) Validate() error {
+	return validatePage(p.Limit, p.Offset)
+}
+
+func (p *PageDTO) Get() (int, int) {
+	return parsePage(p.Limit, p.Offset)
+}
+
+type WebhookID string
+
+func (w *WebhookID) Validate() error {
+	id, err := strconv.ParseInt(string(*w), 10, 64)
+	if err != nil || id <= 0 {
+		return model.NewValidationError("webhook ID must be a valid number")
+	}
+
+	return nil
+}
+
+func (w *WebhookID) Get() int64 {
+	id, _ := strconv.ParseInt(string(*w), 10, 64)
+	return id
+}
@@ -0,0 +1,122 @@
+package presentation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WebhookSubscriptionDTO_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         WebhookSubscriptionDTO
+		expectedError error
+	}{
+		{
+			name:  "Validate webhook with success",
+			input: WebhookSubscriptionDTO{URL: "https://example.com/hook", Events: []string{"transaction.created", "transaction.deleted"}, Secret: "0123456789abcdef"},
+		},
+		{
+			name:  "Validate webhook of every event",
+			input: WebhookSubscriptionDTO{URL: "http://hooks.example.com:9000", Events: []string{"*"}, Secret: "0123456789abcdef"},
+		},
+		{
+			name:  "Validate webhook with relative url and no events",
+			input: WebhookSubscriptionDTO{URL: "/hook", Secret: "0123456789abcdef"},
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "url", Reason: "invalid url, it must be an absolute http or https URL"},
+				{Name: "events", Reason: `events must not be empty, use "*" to receive every event`},
+			}),
+		},
+		{
+			name:  "Validate webhook with loopback url",
+			input: WebhookSubscriptionDTO{URL: "http://localhost:9000/hook", Events: []string{"*"}, Secret: "0123456789abcdef"},
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "url", Reason: "invalid url, it must not point to a loopback, private or link-local address"},
+			}),
+		},
+		{
+			name:  "Validate webhook with cloud metadata url",
+			input: WebhookSubscriptionDTO{URL: "http://169.254.169.254/latest/meta-data", Events: []string{"*"}, Secret: "0123456789abcdef"},
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "url", Reason: "invalid url, it must not point to a loopback, private or link-local address"},
+			}),
+		},
+		{
+			name:  "Validate webhook with private ipv6 url",
+			input: WebhookSubscriptionDTO{URL: "https://[fd00::1]:8443/hook", Events: []string{"*"}, Secret: "0123456789abcdef"},
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "url", Reason: "invalid url, it must not point to a loopback, private or link-local address"},
+			}),
+		},
+		{
+			name:  "Validate webhook with unknown event and short secret",
+			input: WebhookSubscriptionDTO{URL: "https://example.com/hook", Events: []string{"transaction.purged"}, Secret: "secret"},
+			expectedError: model.NewInvalidParamsError([]model.InvalidParam{
+				{Name: "events", Reason: `invalid event "transaction.purged"`},
+				{Name: "secret", Reason: "invalid secret, it must be between 16 and 255 characters"},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedError, tt.input.Validate())
+		})
+	}
+}
+
+func Test_NewWebhookSubscriptionDTO(t *testing.T) {
+	createdAt := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	disabledAt := createdAt.Add(time.Hour)
+
+	// When
+	subscriptionDTO := NewWebhookSubscriptionDTO(&model.WebhookSubscription{
+		ID:                  3,
+		URL:                 "https://example.com/hook",
+		EventTypes:          []model.EventType{model.AllEvents},
+		Secret:              "0123456789abcdef",
+		ConsecutiveFailures: 20,
+		DisabledAt:          &disabledAt,
+		CreatedAt:           createdAt,
+	})
+
+	// Then
+	assert.Equal(t, &WebhookSubscriptionDTO{
+		WebhookID:           3,
+		URL:                 "https://example.com/hook",
+		Events:              []string{"*"},
+		ConsecutiveFailures: 20,
+		DisabledAt:          disabledAt.UTC().Format(time.RFC3339Nano),
+		CreatedAt:           createdAt.UTC().Format(time.RFC3339Nano),
+	}, subscriptionDTO)
+}
+
+func Test_NewWebhookDeliveryDTO(t *testing.T) {
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		delivery model.WebhookDelivery
+		expected *WebhookDeliveryDTO
+	}{
+		{
+			name:     "Pending delivery has the next attempt",
+			delivery: model.WebhookDelivery{ID: 9, EventID: 7, EventType: model.EventTransactionCreated, Status: model.DeliveryPending, Attempts: 1, NextAttemptAt: now, LastStatusCode: 503, LastError: "webhook responded with status 503", CreatedAt: now},
+			expected: &WebhookDeliveryDTO{DeliveryID: 9, EventID: 7, EventType: "transaction.created", Status: "pending", Attempts: 1, NextAttemptAt: now.UTC().Format(time.RFC3339Nano), LastStatusCode: 503, LastError: "webhook responded with status 503", CreatedAt: now.UTC().Format(time.RFC3339Nano)},
+		},
+		{
+			name:     "Delivered delivery has when it was delivered",
+			delivery: model.WebhookDelivery{ID: 9, EventID: 7, EventType: model.EventTransactionCreated, Status: model.DeliveryDelivered, Attempts: 2, NextAttemptAt: now, LastStatusCode: 200, CreatedAt: now, DeliveredAt: &now},
+			expected: &WebhookDeliveryDTO{DeliveryID: 9, EventID: 7, EventType: "transaction.created", Status: "delivered", Attempts: 2, LastStatusCode: 200, CreatedAt: now.UTC().Format(time.RFC3339Nano), DeliveredAt: now.UTC().Format(time.RFC3339Nano)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewWebhookDeliveryDTO(&tt.delivery))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	CircuitClosed = "closed"
+	// CircuitOpen fails the calls right away, without calling the dependency
+	CircuitOpen = "open"
+	// CircuitHalfOpen lets a single trial call through, its result closes or opens the circuit again
+	CircuitHalfOpen = "half_open"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker opens after failureThreshold failed calls in a row and stays open for openTimeout, then it lets a
+// trial call through
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+	state            string
+	failures         int
+	openedAt         time.Time
+	trialInFlight    bool
+	now              func() time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            CircuitClosed,
+		now:              time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen when the call must not be made, otherwise the caller reports its result with
+// Success, Failure or Release
+func (c *CircuitBreaker) Allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == CircuitOpen && c.now().Sub(c.openedAt) >= c.openTimeout {
+		c.state = CircuitHalfOpen
+	}
+
+	switch c.state {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if c.trialInFlight {
+			return ErrCircuitOpen
+		}
+		c.trialInFlight = true
+	}
+
+	return nil
+}
+
+func (c *CircuitBreaker) Success() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.state = CircuitClosed
+	c.failures = 0
+	c.trialInFlight = false
+}
+
+func (c *CircuitBreaker) Failure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	c.trialInFlight = false
+	if c.state == CircuitHalfOpen || c.failures >= c.failureThreshold {
+		c.state = CircuitOpen
+		c.openedAt = c.now()
+	}
+}
+
+// Release gives back the trial call of the half-open circuit when the caller gave up on it, so it tells nothing about
+// the dependency
+func (c *CircuitBreaker) Release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.trialInFlight = false
+}
+
+func (c *CircuitBreaker) State() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == CircuitOpen && c.now().Sub(c.openedAt) >= c.openTimeout {
+		return CircuitHalfOpen
+	}
+
+	return c.state
+}
+
+// CircuitBreakerHealthChecker is the health check of a dependency called through a circuit breaker. It reports the
+// dependency down while the circuit is open without calling it, so the checks neither wait for the rate limiter nor
+// count as calls of the circuit
+type CircuitBreakerHealthChecker struct {
+	breaker *CircuitBreaker
+}
+
+func NewCircuitBreakerHealthChecker(breaker *CircuitBreaker) *CircuitBreakerHealthChecker {
+	return &CircuitBreakerHealthChecker{
+		breaker: breaker,
+	}
+}
+
+func (c *CircuitBreakerHealthChecker) Check(_ context.Context) error {
+	if c.breaker.State() == CircuitOpen {
+		return ErrCircuitOpen
+	}
+
+	return nil
+}
+
+func (c *CircuitBreakerHealthChecker) CircuitBreakerState() string {
+	return c.breaker.State()
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+	newCircuitBreaker := func() (*CircuitBreaker, *time.Time) {
+		now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+		breaker := NewCircuitBreaker(2, time.Minute)
+		breaker.now = func() time.Time { return now }
+		return breaker, &now
+	}
+
+	t.Run("Open after the failures in a row", func(t *testing.T) {
+		// Given
+		breaker, _ := newCircuitBreaker()
+
+		// When
+		breaker.Failure()
+		breaker.Success()
+		breaker.Failure()
+		assert.Equal(t, CircuitClosed, breaker.State())
+		breaker.Failure()
+
+		// Then
+		assert.Equal(t, CircuitOpen, breaker.State())
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+	})
+
+	t.Run("Half open lets a single trial through after the open timeout", func(t *testing.T) {
+		// Given
+		breaker, now := newCircuitBreaker()
+		breaker.Failure()
+		breaker.Failure()
+		*now = now.Add(time.Minute)
+
+		// When
+		trial := breaker.Allow()
+		concurrent := breaker.Allow()
+
+		// Then
+		assert.NoError(t, trial)
+		assert.ErrorIs(t, concurrent, ErrCircuitOpen)
+		assert.Equal(t, CircuitHalfOpen, breaker.State())
+	})
+
+	t.Run("Half open closes on the trial success", func(t *testing.T) {
+		// Given
+		breaker, now := newCircuitBreaker()
+		breaker.Failure()
+		breaker.Failure()
+		*now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+
+		// When
+		breaker.Success()
+
+		// Then
+		assert.Equal(t, CircuitClosed, breaker.State())
+		assert.NoError(t, breaker.Allow())
+	})
+
+	t.Run("Half open opens again on the trial failure", func(t *testing.T) {
+		// Given
+		breaker, now := newCircuitBreaker()
+		breaker.Failure()
+		breaker.Failure()
+		*now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+
+		// When
+		breaker.Failure()
+
+		// Then
+		assert.Equal(t, CircuitOpen, breaker.State())
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+	})
+
+	t.Run("Half open lets another trial through after a release", func(t *testing.T) {
+		// Given
+		breaker, now := newCircuitBreaker()
+		breaker.Failure()
+		breaker.Failure()
+		*now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+
+		// When
+		breaker.Release()
+
+		// Then
+		assert.NoError(t, breaker.Allow())
+	})
+}
+
+func Test_CircuitBreakerHealthChecker(t *testing.T) {
+	tests := []struct {
+		name          string
+		change        func(breaker *CircuitBreaker, now *time.Time)
+		expectedState string
+		expectedError error
+	}{
+		{
+			name:          "Check closed circuit",
+			change:        func(breaker *CircuitBreaker, now *time.Time) { breaker.Failure() },
+			expectedState: CircuitClosed,
+		},
+		{
+			name: "Check open circuit",
+			change: func(breaker *CircuitBreaker, now *time.Time) {
+				breaker.Failure()
+				breaker.Failure()
+			},
+			expectedState: CircuitOpen,
+			expectedError: ErrCircuitOpen,
+		},
+		{
+			name: "Check half open circuit",
+			change: func(breaker *CircuitBreaker, now *time.Time) {
+				breaker.Failure()
+				breaker.Failure()
+				*now = now.Add(time.Minute)
+			},
+			expectedState: CircuitHalfOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+			breaker := NewCircuitBreaker(2, time.Minute)
+			breaker.now = func() time.Time { return now }
+			tt.change(breaker, &now)
+
+			// When
+			var reporter CircuitBreakerReporter = NewCircuitBreakerHealthChecker(breaker)
+			err := reporter.Check(context.TODO())
+
+			// Then
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedState, reporter.CircuitBreakerState())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// EventPublisher delivers the events to the downstream consumers, an error means the event must be retried
+type EventPublisher interface {
+	Publish(ctx context.Context, message *model.EventMessage) error
+}
+
+//go:generate mockgen -source=./event_publisher.go -destination=./mocks/event_publisher_mock.go
+
+// WriterEventPublisher writes one event per line, used with stdout or a local file for tests and development
+type WriterEventPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterEventPublisher(writer io.Writer) *WriterEventPublisher {
+	return &WriterEventPublisher{
+		writer: writer,
+	}
+}
+
+func (w *WriterEventPublisher) Publish(_ context.Context, message *model.EventMessage) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.writer.Write(append(message.Body, '\n'))
+	return err
+}
+
+// MultiEventPublisher publishes each event to all the publishers, the event is retried when any of them fails
+type MultiEventPublisher struct {
+	publishers []EventPublisher
+}
+
+func NewMultiEventPublisher(publishers ...EventPublisher) *MultiEventPublisher {
+	return &MultiEventPublisher{
+		publishers: publishers,
+	}
+}
+
+func (m *MultiEventPublisher) Publish(ctx context.Context, message *model.EventMessage) error {
+	errs := make([]error, 0, len(m.publishers))
+	for _, publisher := range m.publishers {
+		errs = append(errs, publisher.Publish(ctx, message))
+	}
+
+	return errors.Join(errs...)
+}
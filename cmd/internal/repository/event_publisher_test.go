@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriterEventPublisher_Publish(t *testing.T) {
+	// Given
+	var output bytes.Buffer
+	publisher := NewWriterEventPublisher(&output)
+
+	// When
+	err1 := publisher.Publish(context.Background(), &model.EventMessage{ID: 1, Type: model.EventTransactionCreated, Body: []byte(`{"event_id":1}`)})
+	err2 := publisher.Publish(context.Background(), &model.EventMessage{ID: 2, Type: model.EventTransactionDeleted, Body: []byte(`{"event_id":2}`)})
+
+	// Then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, "{\"event_id\":1}\n{\"event_id\":2}\n", output.String())
+}
+
+func Test_MultiEventPublisher_Publish(t *testing.T) {
+	message := &model.EventMessage{ID: 1, Type: model.EventTransactionCreated, Body: []byte(`{"event_id":1}`)}
+
+	t.Run("Publish to every publisher", func(t *testing.T) {
+		// Given
+		var first, second bytes.Buffer
+		publisher := NewMultiEventPublisher(NewWriterEventPublisher(&first), NewWriterEventPublisher(&second))
+
+		// When
+		err := publisher.Publish(context.Background(), message)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"event_id\":1}\n", first.String())
+		assert.Equal(t, "{\"event_id\":1}\n", second.String())
+	})
+
+	t.Run("Publish error on one publisher still publishes to the others", func(t *testing.T) {
+		// Given
+		var output bytes.Buffer
+		publisher := NewMultiEventPublisher(NewWriterEventPublisher(failingWriter{}), NewWriterEventPublisher(&output))
+
+		// When
+		err := publisher.Publish(context.Background(), message)
+
+		// Then
+		assert.EqualError(t, err, "disk full")
+		assert.Equal(t, "{\"event_id\":1}\n", output.String())
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
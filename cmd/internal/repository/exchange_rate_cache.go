@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// treasuryPublicationDelay is how long after the end of a quarter the Treasury takes to publish its rates
+	treasuryPublicationDelay   = 15 * 24 * time.Hour
+	exchangeRateCacheKeyPrefix = "exchange_rate"
+)
+
+// cachedExchangeRate is the cache entry of a lookup, the rate is nil when the lookup found none. The entry of a
+// period holds the rate of every date from its effective date through the latest date whose lookup returned it
+type cachedExchangeRate struct {
+	exchangeRate *model.ExchangeRate
+	through      time.Time
+}
+
+// covers reports whether the date is in the range of dates known to have the rate of the entry
+func (c cachedExchangeRate) covers(date time.Time) bool {
+	return c.exchangeRate != nil && !date.Before(c.exchangeRate.EffectiveDate) && !date.After(c.through)
+}
+
+// ExchangeRateCacheImpl is an ExchangeRateProvider that caches the exchange rates of the wrapped provider by country
+// and quarter, and the lookups without data by country and date. A rate published in the middle of the quarter
+// changes the rate of the dates after it, so the rate of a quarter is only reused for the dates between its
+// effective date and the latest date whose lookup returned it
+type ExchangeRateCacheImpl struct {
+	cache       *ristretto.Cache
+	provider    ExchangeRateProvider
+	lookups     *prometheus.CounterVec
+	log         *slog.Logger
+	now         func() time.Time
+	TTL         time.Duration
+	PendingTTL  time.Duration
+	NegativeTTL time.Duration
+	Cost        func(exchangeRate *model.ExchangeRate) int64
+}
+
+func NewExchangeRateCache(cache *ristretto.Cache, provider ExchangeRateProvider, lookups *prometheus.CounterVec, log *slog.Logger) *ExchangeRateCacheImpl {
+	return &ExchangeRateCacheImpl{
+		cache:       cache,
+		provider:    provider,
+		lookups:     lookups,
+		log:         log,
+		now:         time.Now,
+		TTL:         24 * time.Hour,
+		PendingTTL:  1 * time.Hour,
+		NegativeTTL: 1 * time.Hour,
+		Cost:        ExchangeRateCost,
+	}
+}
+
+func (e *ExchangeRateCacheImpl) GetExchangeRate(ctx context.Context, country string, date time.Time) (*model.ExchangeRate, error) {
+	country = strings.TrimSpace(country)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	period := util.ExchangeRatePeriod(date)
+	periodKey := exchangeRateCacheKey(country, "period", period)
+	log := util.Logger(ctx, e.log)
+
+	if cached, found := e.cache.Get(periodKey); found && cached.(cachedExchangeRate).covers(day) {
+		exchangeRate := cached.(cachedExchangeRate).exchangeRate
+		e.lookups.WithLabelValues("hit").Inc()
+		log.Debug("Exchange rate found in cache", "key", periodKey, "source", exchangeRate.Source)
+		return exchangeRate, nil
+	}
+
+	dateKey := exchangeRateCacheKey(country, "not_found", day)
+	if _, found := e.cache.Get(dateKey); found {
+		e.lookups.WithLabelValues("negative_hit").Inc()
+		log.Debug("Exchange rate not found recovered from cache", "key", dateKey)
+		return nil, nil
+	}
+
+	e.lookups.WithLabelValues("miss").Inc()
+	exchangeRate, err := e.provider.GetExchangeRate(ctx, country, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if exchangeRate == nil {
+		if !e.cache.SetWithTTL(dateKey, cachedExchangeRate{}, e.Cost(nil), e.NegativeTTL) {
+			log.Error("error saving exchange rate cache", "key", dateKey)
+		}
+
+		return nil, nil
+	}
+
+	entry := cachedExchangeRate{exchangeRate: exchangeRate, through: day}
+	if cached, found := e.cache.Get(periodKey); found && sameExchangeRate(cached.(cachedExchangeRate).exchangeRate, exchangeRate) {
+		if through := cached.(cachedExchangeRate).through; through.After(entry.through) {
+			entry.through = through
+		}
+	}
+
+	if !e.cache.SetWithTTL(periodKey, entry, e.Cost(exchangeRate), e.ttl(period)) {
+		log.Error("error saving exchange rate cache", "key", periodKey)
+	}
+
+	return exchangeRate, nil
+}
+
+// ttl keeps the entry for a short time while the rate of the period of its date may still be published, after that
+// the entry is kept until the next quarterly publication, when amendments may show up
+func (e *ExchangeRateCacheImpl) ttl(period time.Time) time.Duration {
+	now := e.now()
+	if now.Before(period.Add(treasuryPublicationDelay)) {
+		return e.PendingTTL
+	}
+
+	nextPublication := util.ExchangeRatePeriod(now).Add(treasuryPublicationDelay)
+	if !now.Before(nextPublication) {
+		nextPublication = util.NextExchangeRatePeriod(util.ExchangeRatePeriod(now)).Add(treasuryPublicationDelay)
+	}
+
+	return min(e.TTL, max(nextPublication.Sub(now), e.PendingTTL))
+}
+
+// ExchangeRateCost estimates the size in bytes of a cached exchange rate, so it shares the cache budget
+// with the other entries
+func ExchangeRateCost(exchangeRate *model.ExchangeRate) int64 {
+	cost := int64(64)
+	if exchangeRate != nil {
+		cost += int64(160 + len(exchangeRate.Country) + len(exchangeRate.Currency) + len(exchangeRate.Source))
+	}
+
+	return cost
+}
+
+func exchangeRateCacheKey(country, kind string, date time.Time) string {
+	return exchangeRateCacheKeyPrefix + ":" + strings.ToLower(country) + ":" + kind + ":" + date.Format(treasuryDateFormat)
+}
+
+func sameExchangeRate(a, b *model.ExchangeRate) bool {
+	return a != nil && b != nil && a.EffectiveDate.Equal(b.EffectiveDate) && a.Rate.Cmp(b.Rate) == 0
+}
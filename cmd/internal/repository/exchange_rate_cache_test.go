@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/metrics"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestExchangeRateCache(t *testing.T) (*ExchangeRateCacheImpl, *mock_repository.MockExchangeRateProvider, *ristretto.Cache) {
+	cache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: 1000,
+		MaxCost:     1 << 20,
+		BufferItems: 64,
+	})
+	assert.NoError(t, err)
+
+	mockController := gomock.NewController(t)
+	provider := mock_repository.NewMockExchangeRateProvider(mockController)
+	exchangeRateCache := NewExchangeRateCache(cache, provider, metrics.New().ExchangeRateCacheLookups, slog.Default())
+	exchangeRateCache.now = func() time.Time { return time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC) }
+
+	return exchangeRateCache, provider, cache
+}
+
+func cacheLookups(exchangeRateCache *ExchangeRateCacheImpl, result string) float64 {
+	return testutil.ToFloat64(exchangeRateCache.lookups.WithLabelValues(result))
+}
+
+func Test_ExchangeRateCache_GetExchangeRate(t *testing.T) {
+	ctx := context.Background()
+	exchangeRate := &model.ExchangeRate{
+		Country:       "Brazil",
+		Rate:          big.NewRat(5434, 1000),
+		EffectiveDate: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+		Source:        model.ExchangeRateSourceTreasury,
+	}
+	amendedRate := &model.ExchangeRate{
+		Country:       "Brazil",
+		Rate:          big.NewRat(61, 10),
+		EffectiveDate: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+		Source:        model.ExchangeRateSourceTreasury,
+	}
+
+	t.Run("GetExchangeRate recovers from cache for the same country and date", func(t *testing.T) {
+		// Given
+		exchangeRateCache, provider, cache := newTestExchangeRateCache(t)
+		transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(exchangeRate, nil).Times(1)
+
+		// When
+		first, err := exchangeRateCache.GetExchangeRate(ctx, " Brazil ", transactionDate)
+		assert.NoError(t, err)
+		cache.Wait()
+		second, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, exchangeRate, first)
+		assert.Equal(t, exchangeRate, second)
+		assert.Equal(t, float64(1), cacheLookups(exchangeRateCache, "hit"))
+		assert.Equal(t, float64(1), cacheLookups(exchangeRateCache, "miss"))
+	})
+
+	t.Run("GetExchangeRate recovers from cache for the country in any case", func(t *testing.T) {
+		// Given
+		exchangeRateCache, provider, cache := newTestExchangeRateCache(t)
+		transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetExchangeRate(ctx, "brazil", transactionDate).Return(exchangeRate, nil).Times(1)
+
+		// When
+		_, err := exchangeRateCache.GetExchangeRate(ctx, "brazil", transactionDate)
+		assert.NoError(t, err)
+		cache.Wait()
+		response, err := exchangeRateCache.GetExchangeRate(ctx, "BRAZIL", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, exchangeRate, response)
+		assert.Equal(t, float64(1), cacheLookups(exchangeRateCache, "hit"))
+	})
+
+	t.Run("GetExchangeRate reuses the rate of the quarter for the dates between its effective date and a later lookup", func(t *testing.T) {
+		// Given
+		exchangeRateCache, provider, cache := newTestExchangeRateCache(t)
+		firstDate := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+		lateDate := time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", firstDate).Return(exchangeRate, nil).Times(1)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", lateDate).Return(exchangeRate, nil).Times(1)
+
+		// When
+		_, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", firstDate)
+		assert.NoError(t, err)
+		cache.Wait()
+		_, err = exchangeRateCache.GetExchangeRate(ctx, "Brazil", lateDate)
+		assert.NoError(t, err)
+		cache.Wait()
+		between, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		first, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", firstDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, exchangeRate, between)
+		assert.Equal(t, exchangeRate, first)
+		assert.Equal(t, float64(2), cacheLookups(exchangeRateCache, "hit"))
+		assert.Equal(t, float64(2), cacheLookups(exchangeRateCache, "miss"))
+	})
+
+	t.Run("GetExchangeRate does not reuse the rate of an earlier date in the same quarter", func(t *testing.T) {
+		// Given
+		exchangeRateCache, provider, cache := newTestExchangeRateCache(t)
+		earlyDate := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+		lateDate := time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", earlyDate).Return(exchangeRate, nil)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", lateDate).Return(amendedRate, nil)
+
+		// When
+		_, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", earlyDate)
+		assert.NoError(t, err)
+		cache.Wait()
+		response, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", lateDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, amendedRate, response)
+		assert.Equal(t, float64(2), cacheLookups(exchangeRateCache, "miss"))
+	})
+
+	t.Run("GetExchangeRate does not reuse the rate of a later date in the same quarter", func(t *testing.T) {
+		// Given
+		exchangeRateCache, provider, cache := newTestExchangeRateCache(t)
+		lateDate := time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC)
+		earlyDate := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", lateDate).Return(amendedRate, nil)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", earlyDate).Return(exchangeRate, nil)
+
+		// When
+		_, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", lateDate)
+		assert.NoError(t, err)
+		cache.Wait()
+		response, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", earlyDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, exchangeRate, response)
+		assert.Equal(t, float64(2), cacheLookups(exchangeRateCache, "miss"))
+	})
+
+	t.Run("GetExchangeRate caches exchange rate not found only for its date", func(t *testing.T) {
+		// Given
+		exchangeRateCache, provider, cache := newTestExchangeRateCache(t)
+		transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+		nextDate := time.Date(2024, 10, 16, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetExchangeRate(ctx, "Narnia", transactionDate).Return(nil, nil).Times(1)
+		provider.EXPECT().GetExchangeRate(ctx, "Narnia", nextDate).Return(exchangeRate, nil).Times(1)
+
+		// When
+		_, err := exchangeRateCache.GetExchangeRate(ctx, "Narnia", transactionDate)
+		assert.NoError(t, err)
+		cache.Wait()
+		notFound, err := exchangeRateCache.GetExchangeRate(ctx, "Narnia", transactionDate)
+		assert.NoError(t, err)
+		found, err := exchangeRateCache.GetExchangeRate(ctx, "Narnia", nextDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, notFound)
+		assert.Equal(t, exchangeRate, found)
+		assert.Equal(t, float64(1), cacheLookups(exchangeRateCache, "negative_hit"))
+		assert.Equal(t, float64(2), cacheLookups(exchangeRateCache, "miss"))
+	})
+
+	t.Run("GetExchangeRate does not cache errors", func(t *testing.T) {
+		// Given
+		exchangeRateCache, provider, cache := newTestExchangeRateCache(t)
+		transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, errors.New("mock error"))
+		provider.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(exchangeRate, nil)
+
+		// When
+		_, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", transactionDate)
+		assert.Error(t, err)
+		cache.Wait()
+		response, err := exchangeRateCache.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, exchangeRate, response)
+	})
+}
+
+func Test_ExchangeRateCache_TTL(t *testing.T) {
+	exchangeRateCache, _, _ := newTestExchangeRateCache(t)
+
+	tests := []struct {
+		name     string
+		period   time.Time
+		expected time.Duration
+	}{
+		{
+			name:     "Period not published yet uses pending TTL",
+			period:   time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC),
+			expected: exchangeRateCache.PendingTTL,
+		},
+		{
+			name:     "Published period limited by the TTL",
+			period:   time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+			expected: exchangeRateCache.TTL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, exchangeRateCache.ttl(tt.period))
+		})
+	}
+
+	t.Run("Published period expires at the next publication", func(t *testing.T) {
+		exchangeRateCache.now = func() time.Time { return time.Date(2025, 1, 14, 12, 0, 0, 0, time.UTC) }
+
+		assert.Equal(t, 12*time.Hour, exchangeRateCache.ttl(time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)))
+	})
+}
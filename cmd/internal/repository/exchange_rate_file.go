@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// exchangeRateFileColumns are the columns the header of a csv file must have, in any order
+var exchangeRateFileColumns = []string{"country", "currency", "exchange_rate", "effective_date"}
+
+// FileExchangeRateProvider is the ExchangeRateProvider of a static csv or json file, loaded once in memory
+type FileExchangeRateProvider struct {
+	// rates of each lowercase country, the most recent effective date first
+	rates map[string][]*model.ExchangeRate
+}
+
+// NewFileExchangeRateProvider loads the file by its extension: a csv with a header of the columns country,
+// currency, exchange_rate and effective_date, or a json array of objects with the same keys
+func NewFileExchangeRateProvider(name string) (*FileExchangeRateProvider, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var data []model.Data
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		data, err = readExchangeRatesCSV(file)
+	case ".json":
+		err = json.NewDecoder(file).Decode(&data)
+	default:
+		return nil, fmt.Errorf("unsupported exchange rate file %s, must be .csv or .json", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading exchange rate file %s: %w", name, err)
+	}
+
+	rates := map[string][]*model.ExchangeRate{}
+	for _, d := range data {
+		exchangeRate, err := newExchangeRate(d, model.ExchangeRateSourceFile)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(strings.TrimSpace(exchangeRate.Country))
+		rates[key] = append(rates[key], exchangeRate)
+	}
+
+	for _, countryRates := range rates {
+		slices.SortStableFunc(countryRates, func(a, b *model.ExchangeRate) int {
+			return b.EffectiveDate.Compare(a.EffectiveDate)
+		})
+	}
+
+	return &FileExchangeRateProvider{
+		rates: rates,
+	}, nil
+}
+
+func (f *FileExchangeRateProvider) GetExchangeRate(_ context.Context, country string, date time.Time) (*model.ExchangeRate, error) {
+	to := date.Format(treasuryDateFormat)
+	from := date.AddDate(0, -exchangeRateWindowMonths, 0).Format(treasuryDateFormat)
+
+	for _, exchangeRate := range f.rates[strings.ToLower(strings.TrimSpace(country))] {
+		effectiveDate := exchangeRate.EffectiveDate.Format(treasuryDateFormat)
+		if effectiveDate > to {
+			continue
+		}
+
+		if effectiveDate < from {
+			return nil, nil
+		}
+
+		return exchangeRate, nil
+	}
+
+	return nil, nil
+}
+
+func readExchangeRatesCSV(reader io.Reader) ([]model.Data, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("missing header")
+	}
+
+	columns := map[string]int{}
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range exchangeRateFileColumns {
+		if _, found := columns[column]; !found {
+			return nil, fmt.Errorf("missing column %s", column)
+		}
+	}
+
+	data := make([]model.Data, 0, len(records)-1)
+	for _, record := range records[1:] {
+		data = append(data, model.Data{
+			Country:       strings.TrimSpace(record[columns["country"]]),
+			Currency:      strings.TrimSpace(record[columns["currency"]]),
+			ExchangeRate:  strings.TrimSpace(record[columns["exchange_rate"]]),
+			EffectiveDate: strings.TrimSpace(record[columns["effective_date"]]),
+		})
+	}
+
+	return data, nil
+}
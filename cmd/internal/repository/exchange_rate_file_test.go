@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeExchangeRateFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func Test_NewFileExchangeRateProvider(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:    "Load csv file with columns in any order",
+			file:    "rates.csv",
+			content: "effective_date,country,exchange_rate,currency\n2024-09-30,Brazil,5.434,Real\n",
+		},
+		{
+			name:    "Load json file",
+			file:    "rates.json",
+			content: `[{"country":"Brazil","currency":"Real","exchange_rate":"5.434","effective_date":"2024-09-30"}]`,
+		},
+		{
+			name:          "Error unsupported extension",
+			file:          "rates.txt",
+			content:       "Brazil",
+			expectedError: "unsupported exchange rate file",
+		},
+		{
+			name:          "Error csv file without a column",
+			file:          "rates.csv",
+			content:       "country,currency,exchange_rate\nBrazil,Real,5.434\n",
+			expectedError: "missing column effective_date",
+		},
+		{
+			name:          "Error invalid rate",
+			file:          "rates.json",
+			content:       `[{"country":"Brazil","currency":"Real","exchange_rate":"abc","effective_date":"2024-09-30"}]`,
+			expectedError: "invalid exchange rate of Brazil from file",
+		},
+		{
+			name:          "Error rate with exponent",
+			file:          "rates.csv",
+			content:       "country,currency,exchange_rate,effective_date\nBrazil,Real,5434e-3,2024-09-30\n",
+			expectedError: "invalid exchange rate of Brazil from file",
+		},
+		{
+			name:          "Error invalid effective date",
+			file:          "rates.csv",
+			content:       "country,currency,exchange_rate,effective_date\nBrazil,Real,5.434,30/09/2024\n",
+			expectedError: "invalid effective date of Brazil from file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			path := writeExchangeRateFile(t, tt.file, tt.content)
+
+			// When
+			provider, err := NewFileExchangeRateProvider(path)
+
+			// Then
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				assert.Nil(t, provider)
+				return
+			}
+
+			assert.NoError(t, err)
+			exchangeRate, err := provider.GetExchangeRate(context.Background(), "Brazil", time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC))
+			assert.NoError(t, err)
+			assert.Equal(t, &model.ExchangeRate{
+				Country:       "Brazil",
+				Currency:      "Real",
+				Rate:          big.NewRat(5434, 1000),
+				EffectiveDate: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+				Source:        "file",
+			}, exchangeRate)
+		})
+	}
+
+	t.Run("Error missing file", func(t *testing.T) {
+		provider, err := NewFileExchangeRateProvider(filepath.Join(t.TempDir(), "rates.csv"))
+
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+}
+
+func Test_FileExchangeRateProvider_GetExchangeRate(t *testing.T) {
+	path := writeExchangeRateFile(t, "rates.csv", "country,currency,exchange_rate,effective_date\n"+
+		"Brazil,Real,5.0,2024-03-31\n"+
+		"Brazil,Real,6.1,2024-11-15\n"+
+		"Brazil,Real,5.434,2024-09-30\n")
+	provider, err := NewFileExchangeRateProvider(path)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		country      string
+		date         time.Time
+		expectedRate *big.Rat
+	}{
+		{
+			name:         "Most recent rate effective on or before the date",
+			country:      "Brazil",
+			date:         time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+			expectedRate: big.NewRat(5434, 1000),
+		},
+		{
+			name:         "Rate effective on the date",
+			country:      "Brazil",
+			date:         time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+			expectedRate: big.NewRat(61, 10),
+		},
+		{
+			name:         "Country is not case sensitive",
+			country:      " brazil ",
+			date:         time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+			expectedRate: big.NewRat(5434, 1000),
+		},
+		{
+			name:    "No rate older than six months",
+			country: "Brazil",
+			date:    time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC).AddDate(1, 0, 0),
+		},
+		{
+			name:    "No rate before the first effective date",
+			country: "Brazil",
+			date:    time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Country not in the file",
+			country: "Narnia",
+			date:    time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			exchangeRate, err := provider.GetExchangeRate(context.Background(), tt.country, tt.date)
+
+			// Then
+			assert.NoError(t, err)
+			if tt.expectedRate == nil {
+				assert.Nil(t, exchangeRate)
+				return
+			}
+
+			assert.Equal(t, tt.expectedRate, exchangeRate.Rate)
+			assert.Equal(t, model.ExchangeRateSourceFile, exchangeRate.Source)
+		})
+	}
+}
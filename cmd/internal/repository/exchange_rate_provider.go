@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+// ExchangeRateProvider recovers the most recent exchange rate of the country whose effective date is on or before
+// the date and no more than six months earlier, nil when it has none
+type ExchangeRateProvider interface {
+	GetExchangeRate(ctx context.Context, country string, date time.Time) (*model.ExchangeRate, error)
+}
+
+//go:generate mockgen -source=./exchange_rate_provider.go -destination=./mocks/exchange_rate_provider_mock.go
+
+// ExchangeRateChain is an ExchangeRateProvider that asks each provider in order until one has the rate. A provider
+// that fails is skipped, when none has the rate nil is returned if any provider answered and the errors are returned
+// only when every provider failed. Once the context is done the next providers are not asked and the error of the
+// context is returned
+type ExchangeRateChain struct {
+	providers []ExchangeRateProvider
+	log       *slog.Logger
+}
+
+func NewExchangeRateChain(log *slog.Logger, providers ...ExchangeRateProvider) *ExchangeRateChain {
+	return &ExchangeRateChain{
+		providers: providers,
+		log:       log,
+	}
+}
+
+func (e *ExchangeRateChain) GetExchangeRate(ctx context.Context, country string, date time.Time) (*model.ExchangeRate, error) {
+	errs := []error{}
+	answered := false
+	for _, provider := range e.providers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		exchangeRate, err := provider.GetExchangeRate(ctx, country, date)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+
+			util.Logger(ctx, e.log).Error("error getting exchange rate, skipping the provider", "country", country, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		if exchangeRate != nil {
+			util.Logger(ctx, e.log).Debug("Exchange rate found", "country", country, "source", exchangeRate.Source)
+			return exchangeRate, nil
+		}
+
+		answered = true
+	}
+
+	if answered {
+		return nil, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// newExchangeRate normalizes a rate in the format of the Treasury dataset, the one of the local table and the files
+func newExchangeRate(data model.Data, source string) (*model.ExchangeRate, error) {
+	rate, err := util.ParseDecimal(data.ExchangeRate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exchange rate of %s from %s: %w", data.Country, source, err)
+	}
+
+	effectiveDate, err := time.Parse(treasuryDateFormat, data.EffectiveDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective date of %s from %s: %q", data.Country, source, data.EffectiveDate)
+	}
+
+	return &model.ExchangeRate{
+		Country:       data.Country,
+		Currency:      data.Currency,
+		Rate:          rate,
+		EffectiveDate: effectiveDate,
+		Source:        source,
+	}, nil
+}
+
+// TreasuryExchangeRateProvider is the ExchangeRateProvider of the live Treasury API
+type TreasuryExchangeRateProvider struct {
+	repository TreasuryRepository
+}
+
+func NewTreasuryExchangeRateProvider(repository TreasuryRepository) *TreasuryExchangeRateProvider {
+	return &TreasuryExchangeRateProvider{
+		repository: repository,
+	}
+}
+
+func (t *TreasuryExchangeRateProvider) GetExchangeRate(ctx context.Context, country string, date time.Time) (*model.ExchangeRate, error) {
+	exchangeRate, err := t.repository.GetExchangeRateByCountry(ctx, country, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if exchangeRate == nil || len(exchangeRate.Data) == 0 {
+		return nil, nil
+	}
+
+	return newExchangeRate(exchangeRate.Data[0], model.ExchangeRateSourceTreasury)
+}
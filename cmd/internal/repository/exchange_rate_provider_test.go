@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExchangeRateChain_GetExchangeRate(t *testing.T) {
+	ctx := context.Background()
+	transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+	fileRate := &model.ExchangeRate{Country: "Brazil", Rate: big.NewRat(5434, 1000), Source: model.ExchangeRateSourceFile}
+	treasuryRate := &model.ExchangeRate{Country: "Brazil", Rate: big.NewRat(5434, 1000), Source: model.ExchangeRateSourceTreasury}
+
+	t.Run("GetExchangeRate from the first provider with the rate", func(t *testing.T) {
+		// Given
+		mockController := gomock.NewController(t)
+		first := mock_repository.NewMockExchangeRateProvider(mockController)
+		second := mock_repository.NewMockExchangeRateProvider(mockController)
+		third := mock_repository.NewMockExchangeRateProvider(mockController)
+		first.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, nil)
+		second.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(fileRate, nil)
+		chain := NewExchangeRateChain(slog.Default(), first, second, third)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, fileRate, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate skips the provider that fails", func(t *testing.T) {
+		// Given
+		mockController := gomock.NewController(t)
+		first := mock_repository.NewMockExchangeRateProvider(mockController)
+		second := mock_repository.NewMockExchangeRateProvider(mockController)
+		first.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, errors.New("database error"))
+		second.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(treasuryRate, nil)
+		chain := NewExchangeRateChain(slog.Default(), first, second)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, treasuryRate, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate not found in any provider", func(t *testing.T) {
+		// Given
+		mockController := gomock.NewController(t)
+		first := mock_repository.NewMockExchangeRateProvider(mockController)
+		second := mock_repository.NewMockExchangeRateProvider(mockController)
+		first.EXPECT().GetExchangeRate(ctx, "Narnia", transactionDate).Return(nil, nil)
+		second.EXPECT().GetExchangeRate(ctx, "Narnia", transactionDate).Return(nil, nil)
+		chain := NewExchangeRateChain(slog.Default(), first, second)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Narnia", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate not found when a provider answered before another failed", func(t *testing.T) {
+		// Given
+		mockController := gomock.NewController(t)
+		first := mock_repository.NewMockExchangeRateProvider(mockController)
+		second := mock_repository.NewMockExchangeRateProvider(mockController)
+		first.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, nil)
+		second.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, errors.New("treasury error"))
+		chain := NewExchangeRateChain(slog.Default(), first, second)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate not found when a provider answered after another failed", func(t *testing.T) {
+		// Given
+		mockController := gomock.NewController(t)
+		first := mock_repository.NewMockExchangeRateProvider(mockController)
+		second := mock_repository.NewMockExchangeRateProvider(mockController)
+		first.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, errors.New("database error"))
+		second.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, nil)
+		chain := NewExchangeRateChain(slog.Default(), first, second)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate error when every provider failed", func(t *testing.T) {
+		// Given
+		mockController := gomock.NewController(t)
+		first := mock_repository.NewMockExchangeRateProvider(mockController)
+		second := mock_repository.NewMockExchangeRateProvider(mockController)
+		first.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, errors.New("database error"))
+		second.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).Return(nil, errors.New("treasury error"))
+		chain := NewExchangeRateChain(slog.Default(), first, second)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.EqualError(t, err, "database error\ntreasury error")
+		assert.Nil(t, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate stops when the context is done during a provider", func(t *testing.T) {
+		// Given
+		ctx, cancel := context.WithCancel(context.Background())
+		mockController := gomock.NewController(t)
+		first := mock_repository.NewMockExchangeRateProvider(mockController)
+		second := mock_repository.NewMockExchangeRateProvider(mockController)
+		first.EXPECT().GetExchangeRate(ctx, "Brazil", transactionDate).DoAndReturn(
+			func(ctx context.Context, country string, date time.Time) (*model.ExchangeRate, error) {
+				cancel()
+				return nil, errors.New("database error")
+			})
+		chain := NewExchangeRateChain(slog.Default(), first, second)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate does not ask any provider with the context done", func(t *testing.T) {
+		// Given
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		first := mock_repository.NewMockExchangeRateProvider(gomock.NewController(t))
+		chain := NewExchangeRateChain(slog.Default(), first)
+
+		// When
+		exchangeRate, err := chain.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, exchangeRate)
+	})
+}
+
+func Test_TreasuryExchangeRateProvider_GetExchangeRate(t *testing.T) {
+	ctx := context.Background()
+	transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		response      *model.TreasuryRatesExchange
+		responseError error
+		expected      *model.ExchangeRate
+		expectedError string
+	}{
+		{
+			name: "Normalize the first rate of the response",
+			response: &model.TreasuryRatesExchange{
+				Data: []model.Data{{Country: "Brazil", Currency: "Real", ExchangeRate: "5.434", EffectiveDate: "2024-09-30"}},
+			},
+			expected: &model.ExchangeRate{
+				Country:       "Brazil",
+				Currency:      "Real",
+				Rate:          big.NewRat(5434, 1000),
+				EffectiveDate: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+				Source:        "treasury",
+			},
+		},
+		{
+			name:     "No rate in the response",
+			response: &model.TreasuryRatesExchange{Data: []model.Data{}},
+		},
+		{
+			name: "Error invalid rate in the response",
+			response: &model.TreasuryRatesExchange{
+				Data: []model.Data{{Country: "Brazil", ExchangeRate: "abc", EffectiveDate: "2024-09-30"}},
+			},
+			expectedError: "invalid exchange rate of Brazil from treasury",
+		},
+		{
+			name:          "Error calling the Treasury API",
+			responseError: errors.New("treasury error"),
+			expectedError: "treasury error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			repository := mock_repository.NewMockTreasuryRepository(gomock.NewController(t))
+			repository.EXPECT().GetExchangeRateByCountry(ctx, "Brazil", transactionDate).Return(tt.response, tt.responseError)
+			provider := NewTreasuryExchangeRateProvider(repository)
+
+			// When
+			exchangeRate, err := provider.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+			// Then
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, exchangeRate)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// ExchangeRateRepository is the local copy of the Treasury dataset in the rates_of_exchange table, kept up to date
+// by the treasury sync, it is also the "database" ExchangeRateProvider
+type ExchangeRateRepository interface {
+	GetExchangeRate(ctx context.Context, country string, date time.Time) (*model.ExchangeRate, error)
+	SaveExchangeRates(rates []model.Data) error
+	GetLastRecordDate() (string, error)
+}
+
+//go:generate mockgen -source=./exchange_rate_repository.go -destination=./mocks/exchange_rate_repository_mock.go
+
+type ExchangeRateRepositoryImpl struct {
+	log *slog.Logger
+	db  *sql.DB
+}
+
+func NewExchangeRateRepository(log *slog.Logger, db *sql.DB) *ExchangeRateRepositoryImpl {
+	return &ExchangeRateRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+// GetExchangeRate recovers the rate from the local table using the same rules of the Treasury API call
+func (e *ExchangeRateRepositoryImpl) GetExchangeRate(ctx context.Context, country string, date time.Time) (*model.ExchangeRate, error) {
+	var data model.Data
+	err := e.db.QueryRowContext(ctx,
+		"SELECT record_date, country, exchange_rate, currency, effective_date FROM rates_of_exchange WHERE country = ? AND effective_date <= ? AND effective_date >= ? ORDER BY effective_date DESC, record_date DESC LIMIT 1",
+		country,
+		date.Format(treasuryDateFormat),
+		date.AddDate(0, -exchangeRateWindowMonths, 0).Format(treasuryDateFormat),
+	).Scan(&data.RecordDate, &data.Country, &data.ExchangeRate, &data.Currency, &data.EffectiveDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newExchangeRate(data, model.ExchangeRateSourceDatabase)
+}
+
+// SaveExchangeRates upserts the rates by record date, country and currency in a single db transaction
+func (e *ExchangeRateRepositoryImpl) SaveExchangeRates(rates []model.Data) error {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO rates_of_exchange (record_date, country, currency, exchange_rate, effective_date) VALUES (?, ?, ?, ?, ?) ON CONFLICT (record_date, country, currency) DO UPDATE SET exchange_rate = excluded.exchange_rate, effective_date = excluded.effective_date")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, rate := range rates {
+		if _, err := stmt.Exec(rate.RecordDate, rate.Country, rate.Currency, rate.ExchangeRate, rate.EffectiveDate); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLastRecordDate returns the most recent record date stored locally or an empty string when there is none
+func (e *ExchangeRateRepositoryImpl) GetLastRecordDate() (string, error) {
+	var recordDate sql.NullString
+	if err := e.db.QueryRow("SELECT MAX(record_date) FROM rates_of_exchange").Scan(&recordDate); err != nil {
+		return "", err
+	}
+
+	return recordDate.String, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExchangeRateRepository_GetExchangeRate(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewExchangeRateRepository(slog.Default(), db)
+
+	selectQuery := "SELECT record_date, country, exchange_rate, currency, effective_date FROM rates_of_exchange WHERE country = \\? AND effective_date <= \\? AND effective_date >= \\? ORDER BY effective_date DESC, record_date DESC LIMIT 1"
+	columns := []string{"record_date", "country", "exchange_rate", "currency", "effective_date"}
+	transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("GetExchangeRate with success from local table", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery(selectQuery).
+			WithArgs("Brazil", "2024-10-15", "2024-04-15").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("2024-09-30", "Brazil", "5.434", "Real", "2024-09-30"))
+
+		// When
+		exchangeRate, err := repository.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.ExchangeRate{
+			Country:       "Brazil",
+			Currency:      "Real",
+			Rate:          big.NewRat(5434, 1000),
+			EffectiveDate: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+			Source:        "database",
+		}, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate not found locally", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery(selectQuery).
+			WithArgs("Brazil", "2024-10-15", "2024-04-15").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		// When
+		exchangeRate, err := repository.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate error on query", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery(selectQuery).
+			WillReturnError(errors.New("query error"))
+
+		// When
+		exchangeRate, err := repository.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.EqualError(t, err, "query error")
+		assert.Nil(t, exchangeRate)
+	})
+
+	t.Run("GetExchangeRate error invalid rate stored", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery(selectQuery).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("2024-09-30", "Brazil", "n/a", "Real", "2024-09-30"))
+
+		// When
+		_, err := repository.GetExchangeRate(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.EqualError(t, err, "invalid exchange rate of Brazil from database: invalid decimal number: \"n/a\"")
+	})
+}
+
+func Test_ExchangeRateRepository_SaveExchangeRates(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewExchangeRateRepository(slog.Default(), db)
+	upsertQuery := "INSERT INTO rates_of_exchange \\(record_date, country, currency, exchange_rate, effective_date\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\) ON CONFLICT \\(record_date, country, currency\\) DO UPDATE SET exchange_rate = excluded.exchange_rate, effective_date = excluded.effective_date"
+	rates := []model.Data{
+		{RecordDate: "2024-09-30", Country: "Brazil", Currency: "Real", ExchangeRate: "5.434", EffectiveDate: "2024-09-30"},
+		{RecordDate: "2024-09-30", Country: "Canada", Currency: "Dollar", ExchangeRate: "1.351", EffectiveDate: "2024-09-30"},
+	}
+
+	t.Run("SaveExchangeRates with success", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		prepare := mock.ExpectPrepare(upsertQuery)
+		prepare.ExpectExec().WithArgs("2024-09-30", "Brazil", "Real", "5.434", "2024-09-30").WillReturnResult(sqlmock.NewResult(1, 1))
+		prepare.ExpectExec().WithArgs("2024-09-30", "Canada", "Dollar", "1.351", "2024-09-30").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		// When
+		err := repository.SaveExchangeRates(rates)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SaveExchangeRates error rollback on exec", func(t *testing.T) {
+		// Given
+		expectedErrorMessage := "mock exec error"
+		mock.ExpectBegin()
+		prepare := mock.ExpectPrepare(upsertQuery)
+		prepare.ExpectExec().WillReturnError(errors.New(expectedErrorMessage))
+		mock.ExpectRollback()
+
+		// When
+		err := repository.SaveExchangeRates(rates)
+
+		// Then
+		assert.Error(t, err)
+		assert.Equal(t, expectedErrorMessage, err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_ExchangeRateRepository_GetLastRecordDate(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewExchangeRateRepository(slog.Default(), db)
+	selectQuery := "SELECT MAX\\(record_date\\) FROM rates_of_exchange"
+
+	t.Run("GetLastRecordDate with success", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("2024-09-30"))
+
+		recordDate, err := repository.GetLastRecordDate()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-09-30", recordDate)
+	})
+
+	t.Run("GetLastRecordDate empty table", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+
+		recordDate, err := repository.GetLastRecordDate()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", recordDate)
+	})
+
+	t.Run("GetLastRecordDate error on query", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).WillReturnError(errors.New("mock error"))
+
+		_, err := repository.GetLastRecordDate()
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+const cacheProbeKey = "health:probe"
+
+// HealthChecker checks one dependency of the application, an error means it is not working
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// CircuitBreakerReporter is a HealthChecker of a dependency called through a circuit breaker
+type CircuitBreakerReporter interface {
+	HealthChecker
+	CircuitBreakerState() string
+}
+
+//go:generate mockgen -source=./health_checker.go -destination=./mocks/health_checker_mock.go
+
+// DatabaseHealthChecker checks the database answers a query
+type DatabaseHealthChecker struct {
+	db *sql.DB
+}
+
+func NewDatabaseHealthChecker(db *sql.DB) *DatabaseHealthChecker {
+	return &DatabaseHealthChecker{
+		db: db,
+	}
+}
+
+func (d *DatabaseHealthChecker) Check(ctx context.Context) error {
+	var result int
+	return d.db.QueryRowContext(ctx, "SELECT 1").Scan(&result)
+}
+
+// CacheHealthChecker checks the cache stores and returns an entry
+type CacheHealthChecker struct {
+	cache *ristretto.Cache
+}
+
+func NewCacheHealthChecker(cache *ristretto.Cache) *CacheHealthChecker {
+	return &CacheHealthChecker{
+		cache: cache,
+	}
+}
+
+func (c *CacheHealthChecker) Check(_ context.Context) error {
+	probe := time.Now().UnixNano()
+	if !c.cache.SetWithTTL(cacheProbeKey, probe, 1, time.Minute) {
+		return errors.New("cache rejected the probe entry")
+	}
+
+	c.cache.Wait()
+	if value, found := c.cache.Get(cacheProbeKey); !found || value != probe {
+		return errors.New("cache did not return the probe entry")
+	}
+
+	return nil
+}
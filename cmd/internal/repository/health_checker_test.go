@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dgraph-io/ristretto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DatabaseHealthChecker_Check(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	checker := NewDatabaseHealthChecker(db)
+
+	t.Run("Check database answering", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+		// When
+		err := checker.Check(context.TODO())
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Check error database unavailable", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("SELECT 1").WillReturnError(errors.New("database is locked"))
+
+		// When
+		err := checker.Check(context.TODO())
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+	})
+}
+
+func Test_CacheHealthChecker_Check(t *testing.T) {
+	t.Run("Check cache storing the probe entry", func(t *testing.T) {
+		// Given
+		cache, err := ristretto.NewCache(&ristretto.Config{NumCounters: 100, MaxCost: 1 << 20, BufferItems: 64})
+		assert.NoError(t, err)
+		defer cache.Close()
+
+		// When
+		err = NewCacheHealthChecker(cache).Check(context.TODO())
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("Check error closed cache", func(t *testing.T) {
+		// Given
+		cache, err := ristretto.NewCache(&ristretto.Config{NumCounters: 100, MaxCost: 1 << 20, BufferItems: 64})
+		assert.NoError(t, err)
+		cache.Close()
+
+		// When
+		err = NewCacheHealthChecker(cache).Check(context.TODO())
+
+		// Then
+		assert.EqualError(t, err, "cache rejected the probe entry")
+	})
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// ErrIdempotencyKeyNotReserved is returned when the key of the request expired or was completed by another request
+// before the transaction was created, so the transaction is not created twice
+var ErrIdempotencyKeyNotReserved = errors.New("idempotency key is no longer reserved by the request")
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, statusCode int, responseBody []byte, expiresAt time.Time) error
+	Delete(ctx context.Context, key string) error
+}
+
+//go:generate mockgen -source=./idempotency_repository.go -destination=./mocks/idempotency_repository_mock.go
+
+type IdempotencyRepositoryImpl struct {
+	log *slog.Logger
+	db  *sql.DB
+}
+
+func NewIdempotencyRepository(log *slog.Logger, db *sql.DB) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+// Reserve stores the record as in progress and returns nil, when the key is already stored and not expired it
+// returns the stored record instead. The primary key makes only one of concurrent requests reserve the key
+func (i *IdempotencyRepositoryImpl) Reserve(ctx context.Context, record *model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, error) {
+	if _, err := i.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= ?", formatEventTime(now)); err != nil {
+		return nil, err
+	}
+
+	result, err := i.db.ExecContext(ctx,
+		"INSERT INTO idempotency_keys (idempotency_key, fingerprint, expires_at) VALUES (?, ?, ?) ON CONFLICT(idempotency_key) DO NOTHING",
+		record.Key,
+		record.Fingerprint,
+		formatEventTime(record.ExpiresAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rows == 1 {
+		return nil, nil
+	}
+
+	return i.get(ctx, record.Key)
+}
+
+func (i *IdempotencyRepositoryImpl) get(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	var stored model.IdempotencyRecord
+	var transactionID sql.NullInt64
+	var expiresAt string
+
+	err := i.db.QueryRowContext(ctx,
+		"SELECT idempotency_key, fingerprint, completed, status_code, response_body, transaction_id, expires_at FROM idempotency_keys WHERE idempotency_key = ?",
+		key,
+	).Scan(&stored.Key, &stored.Fingerprint, &stored.Completed, &stored.StatusCode, &stored.ResponseBody, &transactionID, &expiresAt)
+
+	// released by the request holding it between the insert and the select, it is still being processed
+	if errors.Is(err, sql.ErrNoRows) {
+		return &model.IdempotencyRecord{Key: key}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored.TransactionID = transactionID.Int64
+	stored.ExpiresAt, err = time.Parse(eventTimeFormat, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// Complete stores the response of the request whose key was completed with the transaction
+func (i *IdempotencyRepositoryImpl) Complete(ctx context.Context, key string, statusCode int, responseBody []byte, expiresAt time.Time) error {
+	_, err := i.db.ExecContext(ctx,
+		"UPDATE idempotency_keys SET completed = 1, status_code = ?, response_body = ?, expires_at = ? WHERE idempotency_key = ?",
+		statusCode,
+		responseBody,
+		formatEventTime(expiresAt),
+		key,
+	)
+
+	return err
+}
+
+// Delete releases the key while it is in progress, a completed key is kept to be replayed
+func (i *IdempotencyRepositoryImpl) Delete(ctx context.Context, key string) error {
+	_, err := i.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE idempotency_key = ? AND completed = 0", key)
+	return err
+}
+
+// completeIdempotencyKey completes the key reserved by the request with the transaction created inside tx, it fails
+// when the key is no longer reserved so the creation is rolled back
+func completeIdempotencyKey(ctx context.Context, tx *sql.Tx, key string, transactionID int64, at time.Time) error {
+	result, err := tx.ExecContext(ctx,
+		"UPDATE idempotency_keys SET completed = 1, status_code = ?, transaction_id = ?, expires_at = ? WHERE idempotency_key = ? AND completed = 0",
+		http.StatusOK,
+		transactionID,
+		formatEventTime(at.Add(model.IdempotencyKeyTTL)),
+		key,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrIdempotencyKeyNotReserved
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IdempotencyRepository_Reserve(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewIdempotencyRepository(slog.Default(), db)
+
+	deleteQuery := "DELETE FROM idempotency_keys WHERE expires_at <= \\?"
+	insertQuery := "INSERT INTO idempotency_keys \\(idempotency_key, fingerprint, expires_at\\) VALUES \\(\\?, \\?, \\?\\) ON CONFLICT\\(idempotency_key\\) DO NOTHING"
+	selectQuery := "SELECT idempotency_key, fingerprint, completed, status_code, response_body, transaction_id, expires_at FROM idempotency_keys WHERE idempotency_key = \\?"
+	columns := []string{"idempotency_key", "fingerprint", "completed", "status_code", "response_body", "transaction_id", "expires_at"}
+
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	record := &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fingerprint", ExpiresAt: now.Add(time.Minute)}
+
+	t.Run("Reserve a new key", func(t *testing.T) {
+		// Given
+		mock.ExpectExec(deleteQuery).WithArgs("2025-05-10T12:00:00.000000Z").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertQuery).WithArgs("key-1", "fingerprint", "2025-05-10T12:01:00.000000Z").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// When
+		stored, err := repository.Reserve(context.TODO(), record, now)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reserve returns the stored record of a key already used", func(t *testing.T) {
+		// Given
+		mock.ExpectExec(deleteQuery).WithArgs("2025-05-10T12:00:00.000000Z").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertQuery).WithArgs("key-1", "fingerprint", "2025-05-10T12:01:00.000000Z").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectQuery).WithArgs("key-1").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("key-1", "fingerprint", true, 200, []byte(`{"id":1}`), 1, "2025-05-11T12:00:00.000000Z"))
+
+		// When
+		stored, err := repository.Reserve(context.TODO(), record, now)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.IdempotencyRecord{
+			Key:           "key-1",
+			Fingerprint:   "fingerprint",
+			Completed:     true,
+			StatusCode:    200,
+			ResponseBody:  []byte(`{"id":1}`),
+			TransactionID: 1,
+			ExpiresAt:     time.Date(2025, 5, 11, 12, 0, 0, 0, time.UTC),
+		}, stored)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reserve treats a key released meanwhile as in progress", func(t *testing.T) {
+		// Given
+		mock.ExpectExec(deleteQuery).WithArgs("2025-05-10T12:00:00.000000Z").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertQuery).WithArgs("key-1", "fingerprint", "2025-05-10T12:01:00.000000Z").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectQuery).WithArgs("key-1").WillReturnRows(sqlmock.NewRows(columns))
+
+		// When
+		stored, err := repository.Reserve(context.TODO(), record, now)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.IdempotencyRecord{Key: "key-1"}, stored)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reserve compares the dates with a fixed width in UTC", func(t *testing.T) {
+		// Given
+		local := time.FixedZone("UTC-3", -3*60*60)
+		now := time.Date(2025, 5, 10, 9, 0, 0, 500000000, local)
+		record := &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fingerprint", ExpiresAt: now.Add(time.Minute)}
+		mock.ExpectExec(deleteQuery).WithArgs("2025-05-10T12:00:00.500000Z").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertQuery).WithArgs("key-1", "fingerprint", "2025-05-10T12:01:00.500000Z").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// When
+		stored, err := repository.Reserve(context.TODO(), record, now)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_IdempotencyRepository_Complete(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewIdempotencyRepository(slog.Default(), db)
+
+	t.Run("Complete stores the response", func(t *testing.T) {
+		// Given
+		mock.ExpectExec("UPDATE idempotency_keys SET completed = 1, status_code = \\?, response_body = \\?, expires_at = \\? WHERE idempotency_key = \\?").
+			WithArgs(200, []byte(`{"id":1}`), "2025-05-11T12:00:00.000000Z", "key-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// When
+		err := repository.Complete(context.TODO(), "key-1", 200, []byte(`{"id":1}`), time.Date(2025, 5, 11, 12, 0, 0, 0, time.UTC))
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_IdempotencyRepository_Delete(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewIdempotencyRepository(slog.Default(), db)
+
+	t.Run("Delete releases only the key in progress", func(t *testing.T) {
+		// Given
+		mock.ExpectExec("DELETE FROM idempotency_keys WHERE idempotency_key = \\? AND completed = 0").
+			WithArgs("key-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// When
+		err := repository.Delete(context.TODO(), "key-1")
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
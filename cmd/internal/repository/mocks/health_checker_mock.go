@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./health_checker.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockHealthChecker) Check(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockHealthCheckerMockRecorder) Check(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockHealthChecker)(nil).Check), ctx)
+}
+
+// MockCircuitBreakerReporter is a mock of CircuitBreakerReporter interface.
+type MockCircuitBreakerReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCircuitBreakerReporterMockRecorder
+}
+
+// MockCircuitBreakerReporterMockRecorder is the mock recorder for MockCircuitBreakerReporter.
+type MockCircuitBreakerReporterMockRecorder struct {
+	mock *MockCircuitBreakerReporter
+}
+
+// NewMockCircuitBreakerReporter creates a new mock instance.
+func NewMockCircuitBreakerReporter(ctrl *gomock.Controller) *MockCircuitBreakerReporter {
+	mock := &MockCircuitBreakerReporter{ctrl: ctrl}
+	mock.recorder = &MockCircuitBreakerReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCircuitBreakerReporter) EXPECT() *MockCircuitBreakerReporterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockCircuitBreakerReporter) Check(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockCircuitBreakerReporterMockRecorder) Check(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockCircuitBreakerReporter)(nil).Check), ctx)
+}
+
+// CircuitBreakerState mocks base method.
+func (m *MockCircuitBreakerReporter) CircuitBreakerState() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CircuitBreakerState")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CircuitBreakerState indicates an expected call of CircuitBreakerState.
+func (mr *MockCircuitBreakerReporterMockRecorder) CircuitBreakerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CircuitBreakerState", reflect.TypeOf((*MockCircuitBreakerReporter)(nil).CircuitBreakerState))
+}
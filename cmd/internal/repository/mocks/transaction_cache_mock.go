@@ -5,6 +5,7 @@
 package mock_repository
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,30 +35,42 @@ func (m *MockTransactionCache) EXPECT() *MockTransactionCacheMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockTransactionCache) Delete(ctx context.Context, transactionID int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", ctx, transactionID)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTransactionCacheMockRecorder) Delete(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactionCache)(nil).Delete), ctx, transactionID)
+}
+
 // Get mocks base method.
-func (m *MockTransactionCache) Get(transactionID int64) *model.Transaction {
+func (m *MockTransactionCache) Get(ctx context.Context, transactionID int64) *model.Transaction {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", transactionID)
+	ret := m.ctrl.Call(m, "Get", ctx, transactionID)
 	ret0, _ := ret[0].(*model.Transaction)
 	return ret0
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockTransactionCacheMockRecorder) Get(transactionID interface{}) *gomock.Call {
+func (mr *MockTransactionCacheMockRecorder) Get(ctx, transactionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransactionCache)(nil).Get), transactionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransactionCache)(nil).Get), ctx, transactionID)
 }
 
 // Save mocks base method.
-func (m *MockTransactionCache) Save(transactionID int64, transaction *model.Transaction) error {
+func (m *MockTransactionCache) Save(ctx context.Context, transactionID int64, transaction *model.Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", transactionID, transaction)
+	ret := m.ctrl.Call(m, "Save", ctx, transactionID, transaction)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockTransactionCacheMockRecorder) Save(transactionID, transaction interface{}) *gomock.Call {
+func (mr *MockTransactionCacheMockRecorder) Save(ctx, transactionID, transaction interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTransactionCache)(nil).Save), transactionID, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTransactionCache)(nil).Save), ctx, transactionID, transaction)
 }
@@ -5,7 +5,10 @@
 package mock_repository
 
 import (
+	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/pablorodrigo52/transaction-api/cmd/internal/model"
@@ -35,61 +38,195 @@ func (m *MockTransactionRepository) EXPECT() *MockTransactionRepositoryMockRecor
 }
 
 // GetTransaction mocks base method.
-func (m *MockTransactionRepository) GetTransaction(transactionID int64) (*model.Transaction, error) {
+func (m *MockTransactionRepository) GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransaction", transactionID)
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, transactionID)
 	ret0, _ := ret[0].(*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransaction indicates an expected call of GetTransaction.
-func (mr *MockTransactionRepositoryMockRecorder) GetTransaction(transactionID interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryMockRecorder) GetTransaction(ctx, transactionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransaction), transactionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransaction), ctx, transactionID)
+}
+
+// GetTransactionEvents mocks base method.
+func (m *MockTransactionRepository) GetTransactionEvents(ctx context.Context, transactionID int64, until *time.Time) ([]model.TransactionEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionEvents", ctx, transactionID, until)
+	ret0, _ := ret[0].([]model.TransactionEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionEvents indicates an expected call of GetTransactionEvents.
+func (mr *MockTransactionRepositoryMockRecorder) GetTransactionEvents(ctx, transactionID, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionEvents", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionEvents), ctx, transactionID, until)
+}
+
+// GetTransactionsByIDs mocks base method.
+func (m *MockTransactionRepository) GetTransactionsByIDs(ctx context.Context, transactionIDs []int64) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByIDs", ctx, transactionIDs)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByIDs indicates an expected call of GetTransactionsByIDs.
+func (mr *MockTransactionRepositoryMockRecorder) GetTransactionsByIDs(ctx, transactionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByIDs", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionsByIDs), ctx, transactionIDs)
+}
+
+// ListTransactions mocks base method.
+func (m *MockTransactionRepository) ListTransactions(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, filter)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockTransactionRepositoryMockRecorder) ListTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransactionRepository)(nil).ListTransactions), ctx, filter)
 }
 
 // LogicalDeleteTransaction mocks base method.
-func (m *MockTransactionRepository) LogicalDeleteTransaction(transactionID int64) (*int64, error) {
+func (m *MockTransactionRepository) LogicalDeleteTransaction(ctx context.Context, transactionID, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogicalDeleteTransaction", transactionID)
-	ret0, _ := ret[0].(*int64)
+	ret := m.ctrl.Call(m, "LogicalDeleteTransaction", ctx, transactionID, expectedVersion, audit)
+	ret0, _ := ret[0].(*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LogicalDeleteTransaction indicates an expected call of LogicalDeleteTransaction.
-func (mr *MockTransactionRepositoryMockRecorder) LogicalDeleteTransaction(transactionID interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryMockRecorder) LogicalDeleteTransaction(ctx, transactionID, expectedVersion, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogicalDeleteTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).LogicalDeleteTransaction), transactionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogicalDeleteTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).LogicalDeleteTransaction), ctx, transactionID, expectedVersion, audit)
+}
+
+// PatchTransaction mocks base method.
+func (m *MockTransactionRepository) PatchTransaction(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTransaction", ctx, transactionID, patch, expectedVersion, audit)
+	ret0, _ := ret[0].(*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTransaction indicates an expected call of PatchTransaction.
+func (mr *MockTransactionRepositoryMockRecorder) PatchTransaction(ctx, transactionID, patch, expectedVersion, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).PatchTransaction), ctx, transactionID, patch, expectedVersion, audit)
+}
+
+// PurgeDeletedTransactions mocks base method.
+func (m *MockTransactionRepository) PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time, audit model.Audit) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedTransactions", ctx, deletedBefore, audit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedTransactions indicates an expected call of PurgeDeletedTransactions.
+func (mr *MockTransactionRepositoryMockRecorder) PurgeDeletedTransactions(ctx, deletedBefore, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedTransactions", reflect.TypeOf((*MockTransactionRepository)(nil).PurgeDeletedTransactions), ctx, deletedBefore, audit)
+}
+
+// RestoreTransaction mocks base method.
+func (m *MockTransactionRepository) RestoreTransaction(ctx context.Context, transactionID, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTransaction", ctx, transactionID, expectedVersion, audit)
+	ret0, _ := ret[0].(*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTransaction indicates an expected call of RestoreTransaction.
+func (mr *MockTransactionRepositoryMockRecorder) RestoreTransaction(ctx, transactionID, expectedVersion, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).RestoreTransaction), ctx, transactionID, expectedVersion, audit)
 }
 
 // SaveTransaction mocks base method.
-func (m *MockTransactionRepository) SaveTransaction(transaction *model.Transaction) (*model.Transaction, error) {
+func (m *MockTransactionRepository) SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveTransaction", transaction)
+	ret := m.ctrl.Call(m, "SaveTransaction", ctx, transaction, audit)
 	ret0, _ := ret[0].(*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveTransaction indicates an expected call of SaveTransaction.
-func (mr *MockTransactionRepositoryMockRecorder) SaveTransaction(transaction interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryMockRecorder) SaveTransaction(ctx, transaction, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).SaveTransaction), transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).SaveTransaction), ctx, transaction, audit)
 }
 
 // UpdateTransaction mocks base method.
-func (m *MockTransactionRepository) UpdateTransaction(transactionID int64, transaction *model.Transaction) (*model.Transaction, error) {
+func (m *MockTransactionRepository) UpdateTransaction(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransaction", transactionID, transaction)
+	ret := m.ctrl.Call(m, "UpdateTransaction", ctx, transactionID, transaction, expectedVersion, audit)
 	ret0, _ := ret[0].(*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateTransaction indicates an expected call of UpdateTransaction.
-func (mr *MockTransactionRepositoryMockRecorder) UpdateTransaction(transactionID, transaction interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryMockRecorder) UpdateTransaction(ctx, transactionID, transaction, expectedVersion, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).UpdateTransaction), ctx, transactionID, transaction, expectedVersion, audit)
+}
+
+// Mockquerier is a mock of querier interface.
+type Mockquerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockquerierMockRecorder
+}
+
+// MockquerierMockRecorder is the mock recorder for Mockquerier.
+type MockquerierMockRecorder struct {
+	mock *Mockquerier
+}
+
+// NewMockquerier creates a new mock instance.
+func NewMockquerier(ctrl *gomock.Controller) *Mockquerier {
+	mock := &Mockquerier{ctrl: ctrl}
+	mock.recorder = &MockquerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockquerier) EXPECT() *MockquerierMockRecorder {
+	return m.recorder
+}
+
+// QueryContext mocks base method.
+func (m *Mockquerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryContext", varargs...)
+	ret0, _ := ret[0].(*sql.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryContext indicates an expected call of QueryContext.
+func (mr *MockquerierMockRecorder) QueryContext(ctx, query interface{}, args ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).UpdateTransaction), transactionID, transaction)
+	varargs := append([]interface{}{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryContext", reflect.TypeOf((*Mockquerier)(nil).QueryContext), varargs...)
 }
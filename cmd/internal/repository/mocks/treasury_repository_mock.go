@@ -7,6 +7,7 @@ package mock_repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/pablorodrigo52/transaction-api/cmd/internal/model"
@@ -36,16 +37,54 @@ func (m *MockTreasuryRepository) EXPECT() *MockTreasuryRepositoryMockRecorder {
 }
 
 // GetExchangeRateByCountry mocks base method.
-func (m *MockTreasuryRepository) GetExchangeRateByCountry(ctx context.Context, country string) (*model.TreasuryRatesExchange, error) {
+func (m *MockTreasuryRepository) GetExchangeRateByCountry(ctx context.Context, country string, transactionDate time.Time) (*model.TreasuryRatesExchange, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExchangeRateByCountry", ctx, country)
+	ret := m.ctrl.Call(m, "GetExchangeRateByCountry", ctx, country, transactionDate)
 	ret0, _ := ret[0].(*model.TreasuryRatesExchange)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetExchangeRateByCountry indicates an expected call of GetExchangeRateByCountry.
-func (mr *MockTreasuryRepositoryMockRecorder) GetExchangeRateByCountry(ctx, country interface{}) *gomock.Call {
+func (mr *MockTreasuryRepositoryMockRecorder) GetExchangeRateByCountry(ctx, country, transactionDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRateByCountry", reflect.TypeOf((*MockTreasuryRepository)(nil).GetExchangeRateByCountry), ctx, country)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRateByCountry", reflect.TypeOf((*MockTreasuryRepository)(nil).GetExchangeRateByCountry), ctx, country, transactionDate)
+}
+
+// MockTreasuryDatasetRepository is a mock of TreasuryDatasetRepository interface.
+type MockTreasuryDatasetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTreasuryDatasetRepositoryMockRecorder
+}
+
+// MockTreasuryDatasetRepositoryMockRecorder is the mock recorder for MockTreasuryDatasetRepository.
+type MockTreasuryDatasetRepositoryMockRecorder struct {
+	mock *MockTreasuryDatasetRepository
+}
+
+// NewMockTreasuryDatasetRepository creates a new mock instance.
+func NewMockTreasuryDatasetRepository(ctrl *gomock.Controller) *MockTreasuryDatasetRepository {
+	mock := &MockTreasuryDatasetRepository{ctrl: ctrl}
+	mock.recorder = &MockTreasuryDatasetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTreasuryDatasetRepository) EXPECT() *MockTreasuryDatasetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetExchangeRatesPage mocks base method.
+func (m *MockTreasuryDatasetRepository) GetExchangeRatesPage(ctx context.Context, recordDateFrom string, pageNumber, pageSize int) (*model.TreasuryRatesExchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRatesPage", ctx, recordDateFrom, pageNumber, pageSize)
+	ret0, _ := ret[0].(*model.TreasuryRatesExchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExchangeRatesPage indicates an expected call of GetExchangeRatesPage.
+func (mr *MockTreasuryDatasetRepositoryMockRecorder) GetExchangeRatesPage(ctx, recordDateFrom, pageNumber, pageSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRatesPage", reflect.TypeOf((*MockTreasuryDatasetRepository)(nil).GetExchangeRatesPage), ctx, recordDateFrom, pageNumber, pageSize)
 }
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./webhook_client.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookClient is a mock of WebhookClient interface.
+type MockWebhookClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookClientMockRecorder
+}
+
+// MockWebhookClientMockRecorder is the mock recorder for MockWebhookClient.
+type MockWebhookClientMockRecorder struct {
+	mock *MockWebhookClient
+}
+
+// NewMockWebhookClient creates a new mock instance.
+func NewMockWebhookClient(ctrl *gomock.Controller) *MockWebhookClient {
+	mock := &MockWebhookClient{ctrl: ctrl}
+	mock.recorder = &MockWebhookClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookClient) EXPECT() *MockWebhookClientMockRecorder {
+	return m.recorder
+}
+
+// Post mocks base method.
+func (m *MockWebhookClient) Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, url, headers, body)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockWebhookClientMockRecorder) Post(ctx, url, headers, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockWebhookClient)(nil).Post), ctx, url, headers, body)
+}
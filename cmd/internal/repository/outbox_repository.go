@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+type OutboxRepository interface {
+	GetPendingEvents(now time.Time, limit int) ([]model.OutboxEvent, error)
+	MarkDelivered(eventID int64, deliveredAt time.Time) error
+	MarkFailed(eventID int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkDead(eventID int64, attempts int, lastError string) error
+}
+
+//go:generate mockgen -source=./outbox_repository.go -destination=./mocks/outbox_repository_mock.go
+
+type OutboxRepositoryImpl struct {
+	log *slog.Logger
+	db  *sql.DB
+}
+
+func NewOutboxRepository(log *slog.Logger, db *sql.DB) *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+// GetPendingEvents returns the oldest events not delivered yet whose next attempt is due
+func (o *OutboxRepositoryImpl) GetPendingEvents(now time.Time, limit int) ([]model.OutboxEvent, error) {
+	result, err := o.db.Query(
+		"SELECT id, event_type, transaction_id, payload, occurred_at, attempts FROM outbox_events WHERE status = ? AND next_attempt_at <= ? ORDER BY id LIMIT ?",
+		model.OutboxPending,
+		formatEventTime(now),
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer result.Close()
+
+	events := make([]model.OutboxEvent, 0, limit)
+	for result.Next() {
+		var event model.OutboxEvent
+		var payload sql.NullString
+		var occurredAt string
+
+		if err := result.Scan(&event.ID, &event.Type, &event.TransactionID, &payload, &occurredAt, &event.Attempts); err != nil {
+			return nil, err
+		}
+
+		if event.OccurredAt, err = time.Parse(eventTimeFormat, occurredAt); err != nil {
+			return nil, err
+		}
+
+		if event.Transaction, err = parseSnapshot(event.TransactionID, payload); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (o *OutboxRepositoryImpl) MarkDelivered(eventID int64, deliveredAt time.Time) error {
+	_, err := o.db.Exec(
+		"UPDATE outbox_events SET status = ?, attempts = attempts + 1, delivered_at = ? WHERE id = ?",
+		model.OutboxDelivered,
+		formatEventTime(deliveredAt),
+		eventID,
+	)
+
+	return err
+}
+
+// MarkFailed keeps the event pending to be retried at nextAttemptAt
+func (o *OutboxRepositoryImpl) MarkFailed(eventID int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	_, err := o.db.Exec(
+		"UPDATE outbox_events SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?",
+		attempts,
+		formatEventTime(nextAttemptAt),
+		lastError,
+		eventID,
+	)
+
+	return err
+}
+
+// MarkDead moves the event to the dead letter, it is not retried anymore
+func (o *OutboxRepositoryImpl) MarkDead(eventID int64, attempts int, lastError string) error {
+	_, err := o.db.Exec(
+		"UPDATE outbox_events SET status = ?, attempts = ?, last_error = ? WHERE id = ?",
+		model.OutboxDead,
+		attempts,
+		lastError,
+		eventID,
+	)
+
+	return err
+}
+
+// insertOutboxEvent enqueues the event of a change, it must run in the same database transaction of the change
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, eventType model.EventType, transaction *model.Transaction, occurredAt time.Time) error {
+	payload, err := formatSnapshot(transaction)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO outbox_events (event_type, transaction_id, payload, occurred_at, next_attempt_at) VALUES (?, ?, ?, ?, ?)",
+		eventType,
+		transaction.ID,
+		payload,
+		formatEventTime(occurredAt),
+		formatEventTime(occurredAt),
+	)
+
+	return err
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OutboxRepository_GetPendingEvents(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewOutboxRepository(slog.Default(), db)
+	selectQuery := "SELECT id, event_type, transaction_id, payload, occurred_at, attempts FROM outbox_events WHERE status = \\? AND next_attempt_at <= \\? ORDER BY id LIMIT \\?"
+	columns := []string{"id", "event_type", "transaction_id", "payload", "occurred_at", "attempts"}
+	now := time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("GetPendingEvents with success", func(t *testing.T) {
+		// Given
+		payload := `{"description":"first","transaction_date":"2023-10-10T00:00:00Z","purchase_amount":1000,"deleted":false,"version":1,"deleted_at":null,"restored_at":null,"restored_by":null}`
+		mock.ExpectQuery(selectQuery).
+			WithArgs(model.OutboxPending, "2023-11-02T12:00:00.000000Z", 10).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(4, "transaction.created", 1, payload, "2023-11-02T11:59:59.500000Z", 2))
+
+		// When
+		events, err := repository.GetPendingEvents(now, 10)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []model.OutboxEvent{{
+			ID:            4,
+			Type:          model.EventTransactionCreated,
+			TransactionID: 1,
+			Transaction:   &model.Transaction{ID: 1, Description: "first", TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC), PurchaseAmount: 1000, Version: 1},
+			OccurredAt:    time.Date(2023, 11, 2, 11, 59, 59, 500000000, time.UTC),
+			Attempts:      2,
+		}}, events)
+	})
+
+	t.Run("GetPendingEvents error on select query", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery(selectQuery).
+			WillReturnError(errors.New("database is locked"))
+
+		// When
+		events, err := repository.GetPendingEvents(now, 10)
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+		assert.Nil(t, events)
+	})
+}
+
+func Test_OutboxRepository_MarkEvents(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewOutboxRepository(slog.Default(), db)
+	now := time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("MarkDelivered", func(t *testing.T) {
+		// Given
+		mock.ExpectExec("UPDATE outbox_events SET status = \\?, attempts = attempts \\+ 1, delivered_at = \\? WHERE id = \\?").
+			WithArgs(model.OutboxDelivered, "2023-11-02T12:00:00.000000Z", int64(4)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// When
+		err := repository.MarkDelivered(4, now)
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("MarkFailed", func(t *testing.T) {
+		// Given
+		mock.ExpectExec("UPDATE outbox_events SET attempts = \\?, next_attempt_at = \\?, last_error = \\? WHERE id = \\?").
+			WithArgs(3, "2023-11-02T12:00:04.000000Z", "connection refused", int64(4)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// When
+		err := repository.MarkFailed(4, 3, now.Add(4*time.Second), "connection refused")
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("MarkDead", func(t *testing.T) {
+		// Given
+		mock.ExpectExec("UPDATE outbox_events SET status = \\?, attempts = \\?, last_error = \\? WHERE id = \\?").
+			WithArgs(model.OutboxDead, 10, "webhook responded with status 500", int64(4)).
+			WillReturnError(errors.New("database is locked"))
+
+		// When
+		err := repository.MarkDead(4, 10, "webhook responded with status 500")
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+	})
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RateLimiter(t *testing.T) {
+	t.Run("Wait lets the burst through and then delays by the rate", func(t *testing.T) {
+		// Given
+		now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+		limiter := NewRateLimiter(10, 2)
+		limiter.now = func() time.Time { return now }
+
+		// When
+		delays := []time.Duration{limiter.reserve(), limiter.reserve(), limiter.reserve(), limiter.reserve()}
+
+		// Then
+		assert.Equal(t, []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}, delays)
+	})
+
+	t.Run("Wait refills the bucket over time up to the burst", func(t *testing.T) {
+		// Given
+		now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+		limiter := NewRateLimiter(10, 2)
+		limiter.now = func() time.Time { return now }
+		limiter.reserve()
+		limiter.reserve()
+
+		// When
+		now = now.Add(time.Hour)
+
+		// Then
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+		assert.Equal(t, 100*time.Millisecond, limiter.reserve())
+	})
+
+	t.Run("Wait error canceled context gives back the token", func(t *testing.T) {
+		// Given
+		limiter := NewRateLimiter(0.001, 1)
+		assert.NoError(t, limiter.Wait(context.Background()))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// When
+		err := limiter.Wait(ctx)
+
+		// Then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.InDelta(t, 0, limiter.tokens, 0.01)
+	})
+}
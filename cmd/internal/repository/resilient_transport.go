@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResilientTransport is an http.RoundTripper that protects the Treasury API and the requests waiting for it, it fails
+// fast while the circuit breaker is open, calls the API at the rate of the limiter and retries the network errors,
+// the 5xx and the 429 responses with jittered backoff, waiting the Retry-After informed by the API
+type ResilientTransport struct {
+	next           http.RoundTripper
+	breaker        *CircuitBreaker
+	limiter        *RateLimiter
+	maxRetries     int
+	attemptTimeout time.Duration
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+	now            func() time.Time
+}
+
+func NewResilientTransport(
+	next http.RoundTripper,
+	breaker *CircuitBreaker,
+	limiter *RateLimiter,
+	maxRetries int,
+	attemptTimeout, baseDelay, maxDelay time.Duration) *ResilientTransport {
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &ResilientTransport{
+		next:           next,
+		breaker:        breaker,
+		limiter:        limiter,
+		maxRetries:     maxRetries,
+		attemptTimeout: attemptTimeout,
+		baseDelay:      baseDelay,
+		maxDelay:       maxDelay,
+		now:            time.Now,
+	}
+}
+
+// RoundTrip counts the call as one success or failure of the circuit breaker, whatever the number of attempts
+func (t *ResilientTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	response, err := t.roundTripWithRetries(request)
+
+	switch {
+	case request.Context().Err() != nil:
+		// the caller gave up, the call tells nothing about the API
+		t.breaker.Release()
+	case err != nil || response.StatusCode >= http.StatusInternalServerError:
+		t.breaker.Failure()
+	default:
+		t.breaker.Success()
+	}
+
+	return response, err
+}
+
+func (t *ResilientTransport) roundTripWithRetries(request *http.Request) (*http.Response, error) {
+	// only the calls without side effects are retried
+	retries := t.maxRetries
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := t.attempt(request)
+		// the deadline of the caller is not retried, the attempt timeout is
+		if attempt == retries || request.Context().Err() != nil || !retryable(response, err) {
+			return response, err
+		}
+
+		delay, ok := t.retryDelay(attempt, response)
+		if !ok {
+			return response, err
+		}
+
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		}
+	}
+}
+
+// attempt calls the API once within the attempt timeout, the timeout lasts until the body of the response is closed
+func (t *ResilientTransport) attempt(request *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(request.Context()); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(request.Context(), t.attemptTimeout)
+	response, err := t.next.RoundTrip(request.Clone(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	response.Body = &cancelBody{ReadCloser: response.Body, cancel: cancel}
+	return response, nil
+}
+
+func retryable(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests
+}
+
+// retryDelay is the Retry-After of the response or a random delay up to the backoff of the attempt, it is not ok to
+// retry when the API asks to wait longer than maxDelay
+func (t *ResilientTransport) retryDelay(attempt int, response *http.Response) (time.Duration, bool) {
+	if response != nil {
+		if retryAfter, found := t.retryAfter(response.Header.Get("Retry-After")); found {
+			return retryAfter, retryAfter <= t.maxDelay
+		}
+	}
+
+	backoff := t.baseDelay
+	for i := 0; i < attempt && backoff < t.maxDelay; i++ {
+		backoff *= 2
+	}
+
+	return rand.N(min(backoff, t.maxDelay) + 1), true
+}
+
+// retryAfter parses the header in seconds or as an HTTP date
+func (t *ResilientTransport) retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(t.now()), 0), true
+	}
+
+	return 0, false
+}
+
+// cancelBody releases the context of the attempt once the body is read and closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelBody) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
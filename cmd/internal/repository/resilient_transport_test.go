@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTreasury answers each call with the next handler, repeating the last one
+func fakeTreasury(t *testing.T, calls *atomic.Int32, handlers ...http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1)) - 1
+		handlers[min(call, len(handlers)-1)](w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func status(code int, headers ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+		w.Write([]byte(`{"data": [{"record_date": "2024-09-30","country": "Brazil","exchange_rate": "5.434","currency": "Real","effective_date": "2024-09-30"}]}`))
+	}
+}
+
+func newResilientTreasuryRepository(url string, breaker *CircuitBreaker) *TreasuryRepositoryImpl {
+	transport := NewResilientTransport(nil, breaker, NewRateLimiter(1000, 100), 2, 50*time.Millisecond, time.Millisecond, 100*time.Millisecond)
+	return NewTreasuryRepository(url, "/rates_of_exchange", time.Second, transport, slog.Default())
+}
+
+func Test_ResilientTransport(t *testing.T) {
+	transactionDate := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Retry the 5xx responses", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, status(http.StatusBadGateway), status(http.StatusServiceUnavailable), status(http.StatusOK))
+		breaker := NewCircuitBreaker(1, time.Minute)
+
+		// When
+		result, err := newResilientTreasuryRepository(server.URL, breaker).GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, CircuitClosed, breaker.State())
+	})
+
+	t.Run("Retry the network errors", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, func(w http.ResponseWriter, r *http.Request) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		}, status(http.StatusOK))
+
+		// When
+		_, err := newResilientTreasuryRepository(server.URL, NewCircuitBreaker(1, time.Minute)).GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Retry the attempts timed out", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}, status(http.StatusOK))
+
+		// When
+		_, err := newResilientTreasuryRepository(server.URL, NewCircuitBreaker(1, time.Minute)).GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Retry after the delay informed by the api", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, status(http.StatusTooManyRequests, "Retry-After", "0"), status(http.StatusOK))
+
+		// When
+		_, err := newResilientTreasuryRepository(server.URL, NewCircuitBreaker(1, time.Minute)).GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Retry error api asks to wait longer than the max delay", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, status(http.StatusServiceUnavailable, "Retry-After", "120"), status(http.StatusOK))
+
+		// When
+		_, err := newResilientTreasuryRepository(server.URL, NewCircuitBreaker(5, time.Minute)).GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+
+		// Then
+		assert.EqualError(t, err, "treasury api call error [status_code:503]")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Retry error client errors are not retried", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, status(http.StatusBadRequest))
+		breaker := NewCircuitBreaker(1, time.Minute)
+
+		// When
+		_, err := newResilientTreasuryRepository(server.URL, breaker).GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+
+		// Then
+		assert.EqualError(t, err, "treasury api call error [status_code:400]")
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, CircuitClosed, breaker.State())
+	})
+
+	t.Run("Circuit breaker fails fast once the retries are exhausted", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, status(http.StatusInternalServerError))
+		breaker := NewCircuitBreaker(1, time.Minute)
+		repository := newResilientTreasuryRepository(server.URL, breaker)
+		_, err := repository.GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+		assert.EqualError(t, err, "treasury api call error [status_code:500]")
+
+		// When
+		_, err = repository.GetExchangeRateByCountry(context.TODO(), "Brazil", transactionDate)
+		checkErr := NewCircuitBreakerHealthChecker(breaker).Check(context.TODO())
+
+		// Then
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.ErrorIs(t, checkErr, ErrCircuitOpen)
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, CircuitOpen, breaker.State())
+	})
+
+	t.Run("Circuit breaker ignores the calls abandoned by the caller", func(t *testing.T) {
+		// Given
+		var calls atomic.Int32
+		server := fakeTreasury(t, &calls, status(http.StatusServiceUnavailable))
+		breaker := NewCircuitBreaker(1, time.Minute)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// When
+		_, err := newResilientTreasuryRepository(server.URL, breaker).GetExchangeRateByCountry(ctx, "Brazil", transactionDate)
+
+		// Then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(0), calls.Load())
+		assert.Equal(t, CircuitClosed, breaker.State())
+	})
+}
+
+func Test_ResilientTransport_RetryDelay(t *testing.T) {
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	transport := NewResilientTransport(nil, nil, nil, 3, time.Second, 100*time.Millisecond, time.Minute)
+	transport.now = func() time.Time { return now }
+
+	tests := []struct {
+		name          string
+		attempt       int
+		retryAfter    string
+		expectedMin   time.Duration
+		expectedMax   time.Duration
+		expectedRetry bool
+	}{
+		{name: "Backoff of the first attempt", attempt: 0, expectedMax: 100 * time.Millisecond, expectedRetry: true},
+		{name: "Backoff doubled by attempt", attempt: 3, expectedMax: 800 * time.Millisecond, expectedRetry: true},
+		{name: "Backoff up to the max delay", attempt: 100, expectedMax: time.Minute, expectedRetry: true},
+		{name: "Retry-After in seconds", retryAfter: "30", expectedMin: 30 * time.Second, expectedMax: 30 * time.Second, expectedRetry: true},
+		{name: "Retry-After as date", retryAfter: now.Add(10 * time.Second).Format(http.TimeFormat), expectedMin: 10 * time.Second, expectedMax: 10 * time.Second, expectedRetry: true},
+		{name: "Retry-After longer than the max delay", retryAfter: "3600", expectedMin: time.Hour, expectedMax: time.Hour, expectedRetry: false},
+		{name: "Retry-After invalid uses the backoff", retryAfter: "soon", expectedMax: 100 * time.Millisecond, expectedRetry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			response := &http.Response{Header: http.Header{}}
+			response.Header.Set("Retry-After", tt.retryAfter)
+
+			// When
+			delay, retry := transport.retryDelay(tt.attempt, response)
+
+			// Then
+			assert.Equal(t, tt.expectedRetry, retry)
+			assert.GreaterOrEqual(t, delay, tt.expectedMin)
+			assert.LessOrEqual(t, delay, tt.expectedMax)
+		})
+	}
+}
@@ -1,35 +1,43 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 )
 
+// TransactionCache takes the context of the request, unused by the in-memory cache, so a remote one may replace it
 type TransactionCache interface {
-	Get(transactionID int64) *model.Transaction
-	Save(transactionID int64, transaction *model.Transaction) error
+	Get(ctx context.Context, transactionID int64) *model.Transaction
+	Save(ctx context.Context, transactionID int64, transaction *model.Transaction) error
+	Delete(ctx context.Context, transactionID int64)
 }
 
 //go:generate mockgen -source=./transaction_cache.go -destination=./mocks/transaction_cache_mock.go
 
+// transactionCacheLocks is the number of locks shared by the transactions, a transaction always takes the same one
+const transactionCacheLocks = 64
+
 type TransactionCacheImpl struct {
 	cache *ristretto.Cache
 	TTL   time.Duration
 	Cost  int64
+	locks [transactionCacheLocks]sync.Mutex
 }
 
-func NewTransactionCache(cache *ristretto.Cache) *TransactionCacheImpl {
+func NewTransactionCache(cache *ristretto.Cache, ttl time.Duration) *TransactionCacheImpl {
 	return &TransactionCacheImpl{
 		cache: cache,
-		TTL:   1 * time.Hour,
+		TTL:   ttl,
 		Cost:  1,
 	}
 }
 
-func (t *TransactionCacheImpl) Get(transactionID int64) *model.Transaction {
+func (t *TransactionCacheImpl) Get(ctx context.Context, transactionID int64) *model.Transaction {
 	if transaction, found := t.cache.Get(transactionID); found {
 		return transaction.(*model.Transaction)
 	}
@@ -37,12 +45,36 @@ func (t *TransactionCacheImpl) Get(transactionID int64) *model.Transaction {
 	return nil
 }
 
-func (t *TransactionCacheImpl) Save(transactionID int64, transaction *model.Transaction) error {
+// Save replaces the cached transaction unless the cached one has a newer version, so a slower request never
+// caches a stale copy. The compare and the write hold the lock of the transaction and wait for ristretto to apply the
+// set, which is asynchronous, so the next Save of the transaction compares with this one
+func (t *TransactionCacheImpl) Save(ctx context.Context, transactionID int64, transaction *model.Transaction) error {
+	lock := t.lock(transactionID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if cached := t.Get(ctx, transactionID); cached != nil && cached.Version > transaction.Version {
+		return nil
+	}
+
 	t.cache.Del(transactionID)
 	if !t.cache.SetWithTTL(transactionID, transaction, t.Cost, t.TTL) {
 		errorMessage := "error saving transaction in cache"
 		return errors.New(errorMessage)
 	}
 
+	t.cache.Wait()
 	return nil
 }
+
+func (t *TransactionCacheImpl) Delete(ctx context.Context, transactionID int64) {
+	lock := t.lock(transactionID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	t.cache.Del(transactionID)
+}
+
+func (t *TransactionCacheImpl) lock(transactionID int64) *sync.Mutex {
+	return &t.locks[uint64(transactionID)%transactionCacheLocks]
+}
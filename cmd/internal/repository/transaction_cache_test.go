@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionCache_Save(t *testing.T) {
+	newTransactionCache := func(t *testing.T) (*TransactionCacheImpl, *ristretto.Cache) {
+		cache, err := ristretto.NewCache(&ristretto.Config{
+			NumCounters: 1000,
+			MaxCost:     1 << 20,
+			BufferItems: 64,
+		})
+		assert.NoError(t, err)
+
+		return NewTransactionCache(cache, time.Hour), cache
+	}
+
+	t.Run("Save replaces the cached transaction with a newer version", func(t *testing.T) {
+		// Given
+		transactionCache, cache := newTransactionCache(t)
+		assert.NoError(t, transactionCache.Save(context.TODO(), 1, &model.Transaction{ID: 1, Description: "old", Version: 1}))
+		cache.Wait()
+
+		// When
+		err := transactionCache.Save(context.TODO(), 1, &model.Transaction{ID: 1, Description: "new", Version: 2})
+		cache.Wait()
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1, Description: "new", Version: 2}, transactionCache.Get(context.TODO(), 1))
+	})
+
+	t.Run("Save keeps the cached transaction with a newer version", func(t *testing.T) {
+		// Given
+		transactionCache, cache := newTransactionCache(t)
+		assert.NoError(t, transactionCache.Save(context.TODO(), 1, &model.Transaction{ID: 1, Description: "new", Version: 2}))
+		cache.Wait()
+
+		// When
+		err := transactionCache.Save(context.TODO(), 1, &model.Transaction{ID: 1, Description: "stale", Version: 1})
+		cache.Wait()
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1, Description: "new", Version: 2}, transactionCache.Get(context.TODO(), 1))
+	})
+	t.Run("Save keeps the newest version of concurrent saves without waiting for the cache", func(t *testing.T) {
+		// Given
+		transactionCache, _ := newTransactionCache(t)
+		var wg sync.WaitGroup
+
+		// When
+		for version := int64(1); version <= 50; version++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, transactionCache.Save(context.TODO(), 1, &model.Transaction{ID: 1, Version: version}))
+			}()
+		}
+		wg.Wait()
+
+		// Then
+		assert.Equal(t, int64(50), transactionCache.Get(context.TODO(), 1).Version)
+	})
+
+	t.Run("Save is visible to the next read right away", func(t *testing.T) {
+		// Given
+		transactionCache, _ := newTransactionCache(t)
+
+		// When
+		err := transactionCache.Save(context.TODO(), 1, &model.Transaction{ID: 1, Version: 1})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1, Version: 1}, transactionCache.Get(context.TODO(), 1))
+	})
+}
@@ -1,22 +1,41 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
 )
 
+const (
+	transactionColumns = "id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by"
+	eventTimeFormat    = "2006-01-02T15:04:05.000000Z07:00"
+)
+
 type TransactionRepository interface {
-	GetTransaction(transactionID int64) (*model.Transaction, error)
-	SaveTransaction(transaction *model.Transaction) (*model.Transaction, error)
-	UpdateTransaction(transactionID int64, transaction *model.Transaction) (*model.Transaction, error)
-	LogicalDeleteTransaction(transactionID int64) (*int64, error)
+	GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error)
+	SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*model.Transaction, error)
+	UpdateTransaction(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*model.Transaction, error)
+	PatchTransaction(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*model.Transaction, error)
+	LogicalDeleteTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error)
+	RestoreTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error)
+	PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time, audit model.Audit) ([]int64, error)
+	ListTransactions(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, int64, error)
+	GetTransactionsByIDs(ctx context.Context, transactionIDs []int64) ([]model.Transaction, error)
+	GetTransactionEvents(ctx context.Context, transactionID int64, until *time.Time) ([]model.TransactionEvent, error)
 }
 
 //go:generate mockgen -source=./transaction_repository.go -destination=./mocks/transaction_repository_mock.go
 
+// querier runs a query on the database or inside a database transaction
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
 type TransactionRepositoryImpl struct {
 	log *slog.Logger
 	db  *sql.DB
@@ -29,8 +48,12 @@ func NewTransactionRepository(log *slog.Logger, db *sql.DB) *TransactionReposito
 	}
 }
 
-func (t *TransactionRepositoryImpl) GetTransaction(transactionID int64) (*model.Transaction, error) {
-	result, err := t.db.Query("SELECT id, description, transaction_date, purchase_amount, deleted FROM transactions WHERE id = ?", transactionID)
+func (t *TransactionRepositoryImpl) GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	return t.getTransaction(ctx, t.db, transactionID)
+}
+
+func (t *TransactionRepositoryImpl) getTransaction(ctx context.Context, db querier, transactionID int64) (*model.Transaction, error) {
+	result, err := db.QueryContext(ctx, "SELECT "+transactionColumns+" FROM transactions WHERE id = ?", transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -38,67 +61,481 @@ func (t *TransactionRepositoryImpl) GetTransaction(transactionID int64) (*model.
 	defer result.Close()
 
 	if result.Next() {
-		var transaction model.Transaction
-		var transactionDate string
+		return t.scanTransaction(result)
+	}
 
-		err := result.Scan(&transaction.ID, &transaction.Description, &transactionDate, &transaction.PurchaseAmount, &transaction.Deleted)
-		if err != nil {
+	return nil, result.Err()
+}
+
+func (t *TransactionRepositoryImpl) SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*model.Transaction, error) {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	trx, err := tx.ExecContext(ctx, "INSERT INTO transactions (description, transaction_date, purchase_amount) VALUES (?, ?, ?)", transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.ID, _ = trx.LastInsertId()
+	transaction.Version = 1
+
+	if err := t.recordChange(ctx, tx, transaction.ID, model.OperationCreate, audit, nil, transaction); err != nil {
+		return nil, err
+	}
+
+	if audit.IdempotencyKey != "" {
+		if err := completeIdempotencyKey(ctx, tx, audit.IdempotencyKey, transaction.ID, audit.At); err != nil {
 			return nil, err
 		}
+	}
 
-		transaction.TransactionDate, err = util.ParseDate(transactionDate)
-		if err != nil {
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// UpdateTransaction changes the transaction only when its version is the expected one, any version when it is 0,
+// and returns nil when no transaction was changed
+func (t *TransactionRepositoryImpl) UpdateTransaction(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	return t.changeTransaction(ctx, transactionID, false, expectedVersion, model.OperationUpdate, audit, func(tx *sql.Tx, old *model.Transaction) (*sql.Rows, error) {
+		return tx.QueryContext(ctx,
+			"UPDATE transactions SET description = ?, transaction_date = ?, purchase_amount = ?, version = version + 1 WHERE id = ? AND version = ? RETURNING "+transactionColumns,
+			transaction.Description,
+			util.FormatDate(transaction.TransactionDate),
+			transaction.PurchaseAmount,
+			transactionID,
+			old.Version,
+		)
+	})
+}
+
+// PatchTransaction changes only the columns of the fields present in the patch, with the same version check of
+// UpdateTransaction, and returns the whole transaction changed or nil when no transaction was changed
+func (t *TransactionRepositoryImpl) PatchTransaction(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	columns := []string{}
+	args := []any{}
+
+	if patch.Description != nil {
+		columns = append(columns, "description = ?")
+		args = append(args, *patch.Description)
+	}
+
+	if patch.TransactionDate != nil {
+		columns = append(columns, "transaction_date = ?")
+		args = append(args, util.FormatDate(*patch.TransactionDate))
+	}
+
+	if patch.PurchaseAmount != nil {
+		columns = append(columns, "purchase_amount = ?")
+		args = append(args, *patch.PurchaseAmount)
+	}
+
+	columns = append(columns, "version = version + 1")
+
+	return t.changeTransaction(ctx, transactionID, false, expectedVersion, model.OperationPatch, audit, func(tx *sql.Tx, old *model.Transaction) (*sql.Rows, error) {
+		return tx.QueryContext(ctx,
+			"UPDATE transactions SET "+strings.Join(columns, ", ")+" WHERE id = ? AND version = ? RETURNING "+transactionColumns,
+			append(args, transactionID, old.Version)...,
+		)
+	})
+}
+
+// LogicalDeleteTransaction deletes the transaction only when its version is the expected one, any version when it
+// is 0, and returns the transaction deleted or nil when no transaction was changed
+func (t *TransactionRepositoryImpl) LogicalDeleteTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	return t.changeTransaction(ctx, transactionID, false, expectedVersion, model.OperationDelete, audit, func(tx *sql.Tx, old *model.Transaction) (*sql.Rows, error) {
+		return tx.QueryContext(ctx,
+			"UPDATE transactions SET deleted = 1, deleted_at = ?, version = version + 1 WHERE id = ? AND version = ? RETURNING "+transactionColumns,
+			formatTimestamp(audit.At),
+			transactionID,
+			old.Version,
+		)
+	})
+}
+
+// RestoreTransaction undeletes the transaction when its version is the expected one, any version when it is 0, and
+// returns the transaction restored or nil when no deleted transaction was changed
+func (t *TransactionRepositoryImpl) RestoreTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	return t.changeTransaction(ctx, transactionID, true, expectedVersion, model.OperationRestore, audit, func(tx *sql.Tx, old *model.Transaction) (*sql.Rows, error) {
+		return tx.QueryContext(ctx,
+			"UPDATE transactions SET deleted = 0, deleted_at = NULL, restored_at = ?, restored_by = ?, version = version + 1 WHERE id = ? AND version = ? RETURNING "+transactionColumns,
+			formatTimestamp(audit.At),
+			audit.Actor,
+			transactionID,
+			old.Version,
+		)
+	})
+}
+
+// PurgeDeletedTransactions removes for good the transactions deleted before the date and returns their IDs
+func (t *TransactionRepositoryImpl) PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time, audit model.Audit) ([]int64, error) {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.QueryContext(ctx, "DELETE FROM transactions WHERE deleted = 1 AND deleted_at < ? RETURNING "+transactionColumns, formatTimestamp(deletedBefore))
+	if err != nil {
+		return nil, err
+	}
+
+	purged, err := t.scanTransactions(result, 0)
+	result.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	purgedIDs := make([]int64, 0, len(purged))
+	for _, transaction := range purged {
+		if err := t.appendEvent(ctx, tx, transaction.ID, model.OperationPurge, audit, &transaction, nil); err != nil {
 			return nil, err
 		}
 
-		return &transaction, nil
+		purgedIDs = append(purgedIDs, transaction.ID)
 	}
 
-	return nil, nil
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return purgedIDs, nil
 }
 
-func (t *TransactionRepositoryImpl) SaveTransaction(transaction *model.Transaction) (*model.Transaction, error) {
-	trx, err := t.db.Exec("INSERT INTO transactions (description, transaction_date, purchase_amount) VALUES (?, ?, ?)", transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount)
+// changeTransaction runs the change of a transaction in the deleted state informed, whose version is the expected
+// one or any version when it is 0, and records it in the same database transaction. The change must update
+// only the version read, so a concurrent change makes it return nil as when no transaction was changed
+func (t *TransactionRepositoryImpl) changeTransaction(
+	ctx context.Context,
+	transactionID int64,
+	deleted bool,
+	expectedVersion int64,
+	operation model.TransactionOperation,
+	audit model.Audit,
+	change func(tx *sql.Tx, old *model.Transaction) (*sql.Rows, error)) (*model.Transaction, error) {
+
+	tx, err := t.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	transaction.ID, _ = trx.LastInsertId()
-	return transaction, nil
+	defer tx.Rollback()
+
+	old, err := t.getTransaction(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if old == nil || old.Deleted != deleted || (expectedVersion != 0 && old.Version != expectedVersion) {
+		return nil, nil
+	}
+
+	result, err := change(tx, old)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := t.scanTransactions(result, 1)
+	result.Close()
+	if err != nil || len(changed) == 0 {
+		return nil, err
+	}
+
+	if err := t.recordChange(ctx, tx, transactionID, operation, audit, old, &changed[0]); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &changed[0], nil
+}
+
+// ListTransactions returns one page of the transactions that match the filter and the total of matching transactions
+func (t *TransactionRepositoryImpl) ListTransactions(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, int64, error) {
+	where, args := t.buildListFilter(filter)
+
+	var total int64
+	if err := t.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM transactions"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	result, err := t.db.QueryContext(ctx, "SELECT "+transactionColumns+" FROM transactions"+where+" ORDER BY id LIMIT ? OFFSET ?", append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer result.Close()
+
+	transactions, err := t.scanTransactions(result, filter.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
 }
 
-func (t *TransactionRepositoryImpl) UpdateTransaction(transactionID int64, transaction *model.Transaction) (*model.Transaction, error) {
-	trx, err := t.db.Exec("UPDATE transactions SET description = ?, transaction_date = ?, purchase_amount = ? WHERE id = ? AND deleted = 0", transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount, transactionID)
+// GetTransactionsByIDs returns the transactions found, including the deleted ones, in no particular order
+func (t *TransactionRepositoryImpl) GetTransactionsByIDs(ctx context.Context, transactionIDs []int64) ([]model.Transaction, error) {
+	if len(transactionIDs) == 0 {
+		return []model.Transaction{}, nil
+	}
+
+	args := make([]any, 0, len(transactionIDs))
+	for _, transactionID := range transactionIDs {
+		args = append(args, transactionID)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(transactionIDs)), ", ")
+	result, err := t.db.QueryContext(ctx, "SELECT "+transactionColumns+" FROM transactions WHERE id IN ("+placeholders+")", args...)
 	if err != nil {
 		return nil, err
 	}
 
-	rowsAffected, err := trx.RowsAffected()
+	defer result.Close()
+
+	return t.scanTransactions(result, len(transactionIDs))
+}
+
+// GetTransactionEvents returns the history of the transaction in the order it happened, only the events that
+// occurred until the time informed when it is not nil
+func (t *TransactionRepositoryImpl) GetTransactionEvents(ctx context.Context, transactionID int64, until *time.Time) ([]model.TransactionEvent, error) {
+	query := "SELECT id, transaction_id, operation, actor, request_id, occurred_at, old_value, new_value FROM transaction_events WHERE transaction_id = ?"
+	args := []any{transactionID}
+
+	if until != nil {
+		query += " AND occurred_at <= ?"
+		args = append(args, formatEventTime(*until))
+	}
+
+	result, err := t.db.QueryContext(ctx, query+" ORDER BY id", args...)
 	if err != nil {
 		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return nil, nil
+	defer result.Close()
+
+	events := []model.TransactionEvent{}
+	for result.Next() {
+		var event model.TransactionEvent
+		var occurredAt string
+		var requestID, oldValue, newValue sql.NullString
+
+		if err := result.Scan(&event.ID, &event.TransactionID, &event.Operation, &event.Actor, &requestID, &occurredAt, &oldValue, &newValue); err != nil {
+			return nil, err
+		}
+
+		if event.OccurredAt, err = time.Parse(eventTimeFormat, occurredAt); err != nil {
+			return nil, err
+		}
+
+		if event.OldValue, err = parseSnapshot(transactionID, oldValue); err != nil {
+			return nil, err
+		}
+
+		if event.NewValue, err = parseSnapshot(transactionID, newValue); err != nil {
+			return nil, err
+		}
+
+		event.RequestID = requestID.String
+		events = append(events, event)
 	}
 
-	return transaction, nil
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
 }
 
-func (t *TransactionRepositoryImpl) LogicalDeleteTransaction(transactionID int64) (*int64, error) {
-	trx, err := t.db.Exec("UPDATE transactions SET deleted = 1 WHERE id = ? AND deleted = 0", transactionID)
+// recordChange appends the change to the audit trail and, when it is published, enqueues its event in the outbox
+func (t *TransactionRepositoryImpl) recordChange(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionID int64,
+	operation model.TransactionOperation,
+	audit model.Audit,
+	oldValue *model.Transaction,
+	newValue *model.Transaction) error {
+
+	if err := t.appendEvent(ctx, tx, transactionID, operation, audit, oldValue, newValue); err != nil {
+		return err
+	}
+
+	eventType, published := operation.EventType()
+	if !published {
+		return nil
+	}
+
+	return insertOutboxEvent(ctx, tx, eventType, newValue, audit.At)
+}
+
+// appendEvent records the change of a transaction, it must run in the same database transaction of the change
+func (t *TransactionRepositoryImpl) appendEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionID int64,
+	operation model.TransactionOperation,
+	audit model.Audit,
+	oldValue *model.Transaction,
+	newValue *model.Transaction) error {
+
+	oldSnapshot, err := formatSnapshot(oldValue)
+	if err != nil {
+		return err
+	}
+
+	newSnapshot, err := formatSnapshot(newValue)
 	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO transaction_events (transaction_id, operation, actor, request_id, occurred_at, old_value, new_value) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		transactionID,
+		operation,
+		audit.Actor,
+		sql.NullString{String: audit.RequestID, Valid: audit.RequestID != ""},
+		formatEventTime(audit.At),
+		oldSnapshot,
+		newSnapshot,
+	)
+
+	return err
+}
+
+func (t *TransactionRepositoryImpl) scanTransactions(result *sql.Rows, capacity int) ([]model.Transaction, error) {
+	transactions := make([]model.Transaction, 0, capacity)
+	for result.Next() {
+		transaction, err := t.scanTransaction(result)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, *transaction)
+	}
+
+	if err := result.Err(); err != nil {
 		return nil, err
 	}
 
-	rowsAffected, err := trx.RowsAffected()
+	return transactions, nil
+}
+
+func (t *TransactionRepositoryImpl) scanTransaction(result *sql.Rows) (*model.Transaction, error) {
+	var transaction model.Transaction
+	var transactionDate string
+	var deletedAt, restoredAt, restoredBy sql.NullString
+
+	err := result.Scan(
+		&transaction.ID,
+		&transaction.Description,
+		&transactionDate,
+		&transaction.PurchaseAmount,
+		&transaction.Deleted,
+		&transaction.Version,
+		&deletedAt,
+		&restoredAt,
+		&restoredBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.TransactionDate, err = util.ParseDate(transactionDate)
 	if err != nil {
 		return nil, err
 	}
 
-	if rowsAffected == 0 {
+	if transaction.DeletedAt, err = parseTimestamp(deletedAt); err != nil {
+		return nil, err
+	}
+
+	if transaction.RestoredAt, err = parseTimestamp(restoredAt); err != nil {
+		return nil, err
+	}
+
+	transaction.RestoredBy = restoredBy.String
+	return &transaction, nil
+}
+
+func (t *TransactionRepositoryImpl) buildListFilter(filter *model.TransactionFilter) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted = 0")
+	}
+
+	// dates are stored with their offset, so they are compared after being normalized by sqlite
+	if filter.TransactionDateFrom != nil {
+		conditions = append(conditions, "datetime(transaction_date) >= datetime(?)")
+		args = append(args, util.FormatDate(*filter.TransactionDateFrom))
+	}
+
+	if filter.TransactionDateTo != nil {
+		conditions = append(conditions, "datetime(transaction_date) <= datetime(?)")
+		args = append(args, util.FormatDate(*filter.TransactionDateTo))
+	}
+
+	if filter.PurchaseAmountMin != nil {
+		conditions = append(conditions, "purchase_amount >= ?")
+		args = append(args, *filter.PurchaseAmountMin)
+	}
+
+	if filter.PurchaseAmountMax != nil {
+		conditions = append(conditions, "purchase_amount <= ?")
+		args = append(args, *filter.PurchaseAmountMax)
+	}
+
+	if filter.Description != "" {
+		conditions = append(conditions, "description LIKE ? ESCAPE '\\'")
+		args = append(args, "%"+escapeLike(filter.Description)+"%")
+	}
+
+	if filter.AfterID > 0 {
+		conditions = append(conditions, "id > ?")
+		args = append(args, filter.AfterID)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// formatTimestamp formats the time of a change in UTC, so the stored timestamps can be compared as text
+func formatTimestamp(timestamp time.Time) string {
+	return timestamp.UTC().Format(time.RFC3339)
+}
+
+func parseTimestamp(timestamp sql.NullString) (*time.Time, error) {
+	if !timestamp.Valid {
 		return nil, nil
 	}
 
-	return &transactionID, nil
+	parsed, err := time.Parse(time.RFC3339, timestamp.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+// formatEventTime formats the time of an event in UTC with a fixed width, so the events can be compared as text
+func formatEventTime(timestamp time.Time) string {
+	return timestamp.UTC().Format(eventTimeFormat)
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
 }
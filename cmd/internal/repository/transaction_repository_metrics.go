@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TransactionRepositoryMetrics is a TransactionRepository that observes the duration of each method of the wrapped
+// repository, the failed calls included
+type TransactionRepositoryMetrics struct {
+	repository TransactionRepository
+	duration   *prometheus.HistogramVec
+}
+
+func NewTransactionRepositoryMetrics(repository TransactionRepository, duration *prometheus.HistogramVec) *TransactionRepositoryMetrics {
+	return &TransactionRepositoryMetrics{
+		repository: repository,
+		duration:   duration,
+	}
+}
+
+func (t *TransactionRepositoryMetrics) observe(method string, start time.Time) {
+	t.duration.WithLabelValues("transaction", method).Observe(time.Since(start).Seconds())
+}
+
+func (t *TransactionRepositoryMetrics) GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	defer t.observe("GetTransaction", time.Now())
+	return t.repository.GetTransaction(ctx, transactionID)
+}
+
+func (t *TransactionRepositoryMetrics) SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*model.Transaction, error) {
+	defer t.observe("SaveTransaction", time.Now())
+	return t.repository.SaveTransaction(ctx, transaction, audit)
+}
+
+func (t *TransactionRepositoryMetrics) UpdateTransaction(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	defer t.observe("UpdateTransaction", time.Now())
+	return t.repository.UpdateTransaction(ctx, transactionID, transaction, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryMetrics) PatchTransaction(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	defer t.observe("PatchTransaction", time.Now())
+	return t.repository.PatchTransaction(ctx, transactionID, patch, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryMetrics) LogicalDeleteTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	defer t.observe("LogicalDeleteTransaction", time.Now())
+	return t.repository.LogicalDeleteTransaction(ctx, transactionID, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryMetrics) RestoreTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	defer t.observe("RestoreTransaction", time.Now())
+	return t.repository.RestoreTransaction(ctx, transactionID, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryMetrics) PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time, audit model.Audit) ([]int64, error) {
+	defer t.observe("PurgeDeletedTransactions", time.Now())
+	return t.repository.PurgeDeletedTransactions(ctx, deletedBefore, audit)
+}
+
+func (t *TransactionRepositoryMetrics) ListTransactions(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, int64, error) {
+	defer t.observe("ListTransactions", time.Now())
+	return t.repository.ListTransactions(ctx, filter)
+}
+
+func (t *TransactionRepositoryMetrics) GetTransactionsByIDs(ctx context.Context, transactionIDs []int64) ([]model.Transaction, error) {
+	defer t.observe("GetTransactionsByIDs", time.Now())
+	return t.repository.GetTransactionsByIDs(ctx, transactionIDs)
+}
+
+func (t *TransactionRepositoryMetrics) GetTransactionEvents(ctx context.Context, transactionID int64, until *time.Time) ([]model.TransactionEvent, error) {
+	defer t.observe("GetTransactionEvents", time.Now())
+	return t.repository.GetTransactionEvents(ctx, transactionID, until)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/metrics"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionRepositoryMetrics(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+
+	t.Run("Observe the duration of each method", func(t *testing.T) {
+		// Given
+		m := metrics.New()
+		repository := NewTransactionRepositoryMetrics(mockRepository, m.RepositoryDuration)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), int64(1)).Return(&model.Transaction{ID: 1}, nil)
+		mockRepository.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).Return(nil, int64(0), nil)
+
+		// When
+		transaction, err := repository.GetTransaction(context.TODO(), 1)
+		_, _, _ = repository.ListTransactions(context.TODO(), &model.TransactionFilter{})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1}, transaction)
+		assert.Equal(t, 2, testutil.CollectAndCount(m.RepositoryDuration))
+	})
+
+	t.Run("Observe the duration of the failed calls", func(t *testing.T) {
+		// Given
+		m := metrics.New()
+		repository := NewTransactionRepositoryMetrics(mockRepository, m.RepositoryDuration)
+		mockRepository.EXPECT().GetTransactionsByIDs(gomock.Any(), []int64{1, 2}).Return(nil, errors.New("database is locked"))
+
+		// When
+		_, err := repository.GetTransactionsByIDs(context.TODO(), []int64{1, 2})
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+		assert.Equal(t, 1, testutil.CollectAndCount(m.RepositoryDuration, "transaction_api_repository_query_duration_seconds"))
+	})
+}
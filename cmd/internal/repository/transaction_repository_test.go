@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
 	"testing"
 	"time"
@@ -13,6 +15,13 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	transactionColumnNames = []string{"id", "description", "transaction_date", "purchase_amount", "deleted", "version", "deleted_at", "restored_at", "restored_by"}
+	selectByIDQuery        = "SELECT id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by FROM transactions WHERE id = \\?"
+	insertOutboxQuery      = "INSERT INTO outbox_events \\(event_type, transaction_id, payload, occurred_at, next_attempt_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)"
+	insertEventQuery       = "INSERT INTO transaction_events \\(transaction_id, operation, actor, request_id, occurred_at, old_value, new_value\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)"
+)
+
 func Test_TransactionRepository_GetTransaction(t *testing.T) {
 	t.Parallel()
 	db, mock, err := sqlmock.New()
@@ -24,7 +33,7 @@ func Test_TransactionRepository_GetTransaction(t *testing.T) {
 
 	logger := slog.Default()
 	repository := NewTransactionRepository(logger, db)
-	selectQuery := "SELECT id, description, transaction_date, purchase_amount, deleted FROM transactions WHERE id = \\?"
+	selectQuery := "SELECT id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by FROM transactions WHERE id = \\?"
 
 	t.Run("GetTransaction with success", func(t *testing.T) {
 		// Given
@@ -33,20 +42,21 @@ func Test_TransactionRepository_GetTransaction(t *testing.T) {
 			ID:              transactionID,
 			Description:     "Test Transaction",
 			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  100.0,
+			PurchaseAmount:  10000,
 			Deleted:         false,
+			Version:         1,
 		}
 
 		rows := sqlmock.
-			NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted"}).
-			AddRow(expectedTransaction.ID, expectedTransaction.Description, expectedTransaction.TransactionDate.Format(time.RFC3339), expectedTransaction.PurchaseAmount, expectedTransaction.Deleted)
+			NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted", "version", "deleted_at", "restored_at", "restored_by"}).
+			AddRow(expectedTransaction.ID, expectedTransaction.Description, expectedTransaction.TransactionDate.Format(time.RFC3339), expectedTransaction.PurchaseAmount, expectedTransaction.Deleted, expectedTransaction.Version, nil, nil, nil)
 
 		mock.ExpectQuery(selectQuery).
 			WithArgs(transactionID).
 			WillReturnRows(rows)
 
 		// When
-		transaction, err := repository.GetTransaction(transactionID)
+		transaction, err := repository.GetTransaction(context.TODO(), transactionID)
 
 		// Then
 		assert.NoError(t, err)
@@ -60,10 +70,10 @@ func Test_TransactionRepository_GetTransaction(t *testing.T) {
 
 		mock.ExpectQuery(selectQuery).
 			WithArgs(transactionID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted"}))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted", "version", "deleted_at", "restored_at", "restored_by"}))
 
 		// When
-		transaction, err := repository.GetTransaction(transactionID)
+		transaction, err := repository.GetTransaction(context.TODO(), transactionID)
 
 		// Then
 		assert.NoError(t, err)
@@ -80,7 +90,7 @@ func Test_TransactionRepository_GetTransaction(t *testing.T) {
 			WillReturnError(errors.New(expectedErrorMessage))
 
 		// When
-		transaction, err := repository.GetTransaction(transactionID)
+		transaction, err := repository.GetTransaction(context.TODO(), transactionID)
 
 		// Then
 		assert.Error(t, err)
@@ -93,15 +103,15 @@ func Test_TransactionRepository_GetTransaction(t *testing.T) {
 		transactionID := int64(1)
 
 		rows := sqlmock.
-			NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted"}).
-			AddRow(nil, nil, nil, nil, nil)
+			NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted", "version", "deleted_at", "restored_at", "restored_by"}).
+			AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		mock.ExpectQuery(selectQuery).
 			WithArgs(transactionID).
 			WillReturnRows(rows)
 
 		// When
-		transaction, err := repository.GetTransaction(transactionID)
+		transaction, err := repository.GetTransaction(context.TODO(), transactionID)
 
 		// Then
 		assert.Error(t, err)
@@ -114,15 +124,15 @@ func Test_TransactionRepository_GetTransaction(t *testing.T) {
 		transactionID := int64(4)
 		transactionDate := "invalid-date"
 
-		rows := sqlmock.NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted"}).
-			AddRow(transactionID, "Test Transaction", transactionDate, 100.0, false)
+		rows := sqlmock.NewRows([]string{"id", "description", "transaction_date", "purchase_amount", "deleted", "version", "deleted_at", "restored_at", "restored_by"}).
+			AddRow(transactionID, "Test Transaction", transactionDate, 10000, false, 1, nil, nil, nil)
 
 		mock.ExpectQuery(selectQuery).
 			WithArgs(transactionID).
 			WillReturnRows(rows)
 
 		// When
-		transaction, err := repository.GetTransaction(transactionID)
+		transaction, err := repository.GetTransaction(context.TODO(), transactionID)
 
 		// Then
 		assert.Error(t, err)
@@ -143,26 +153,36 @@ func Test_TransactionRepository_SaveTransaction(t *testing.T) {
 	logger := slog.Default()
 	repository := NewTransactionRepository(logger, db)
 	insertQuery := "INSERT INTO transactions \\(description, transaction_date, purchase_amount\\) VALUES \\(\\?, \\?, \\?\\)"
+	audit := model.Audit{Actor: "alice", RequestID: "request-1", At: time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)}
 
 	t.Run("SaveTransaction with success", func(t *testing.T) {
 		// Given
 		expectedTransaction := &model.Transaction{
 			Description:     "test transaction",
 			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  100.0,
+			PurchaseAmount:  10000,
 		}
 
+		mock.ExpectBegin()
 		mock.ExpectExec(insertQuery).
 			WithArgs(expectedTransaction.Description, util.FormatDate(expectedTransaction.TransactionDate), expectedTransaction.PurchaseAmount).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertEventQuery).
+			WithArgs(int64(1), model.OperationCreate, "alice", "request-1", "2023-11-02T12:00:00.000000Z", nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).
+			WithArgs(model.EventTransactionCreated, int64(1), sqlmock.AnyArg(), "2023-11-02T12:00:00.000000Z", "2023-11-02T12:00:00.000000Z").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		// When
-		transaction, err := repository.SaveTransaction(expectedTransaction)
+		transaction, err := repository.SaveTransaction(context.TODO(), expectedTransaction, audit)
 
 		// Then
 		assert.NoError(t, err)
 		assert.NotNil(t, transaction)
 		assert.Equal(t, expectedTransaction, transaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("SaveTransaction error on execute query", func(t *testing.T) {
@@ -171,19 +191,101 @@ func Test_TransactionRepository_SaveTransaction(t *testing.T) {
 		transaction := &model.Transaction{
 			Description:     "test transaction",
 			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  100.0,
+			PurchaseAmount:  10000,
 		}
 
 		// When
+		mock.ExpectBegin()
 		mock.ExpectExec(insertQuery).
 			WithArgs(transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount).
 			WillReturnError(errors.New(expectedErrorMessage))
+		mock.ExpectRollback()
 
-		_, err := repository.SaveTransaction(transaction)
+		_, err := repository.SaveTransaction(context.TODO(), transaction, audit)
 
 		// Then
 		assert.Error(t, err)
 		assert.Equal(t, expectedErrorMessage, err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SaveTransaction rolls back when the event is not recorded", func(t *testing.T) {
+		// Given
+		transaction := &model.Transaction{
+			Description:     "test transaction",
+			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
+			PurchaseAmount:  10000,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(insertQuery).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(insertEventQuery).
+			WillReturnError(errors.New("database is locked"))
+		mock.ExpectRollback()
+
+		// When
+		_, err := repository.SaveTransaction(context.TODO(), transaction, audit)
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	completeIdempotencyQuery := "UPDATE idempotency_keys SET completed = 1, status_code = \\?, transaction_id = \\?, expires_at = \\? WHERE idempotency_key = \\? AND completed = 0"
+	idempotentAudit := audit
+	idempotentAudit.IdempotencyKey = "key-1"
+
+	t.Run("SaveTransaction completes the idempotency key in the same transaction", func(t *testing.T) {
+		// Given
+		transaction := &model.Transaction{
+			Description:     "test transaction",
+			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
+			PurchaseAmount:  10000,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec(insertEventQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(completeIdempotencyQuery).
+			WithArgs(200, int64(3), "2023-11-03T12:00:00.000000Z", "key-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// When
+		saved, err := repository.SaveTransaction(context.TODO(), transaction, idempotentAudit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), saved.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SaveTransaction rolls back when the idempotency key is no longer reserved", func(t *testing.T) {
+		// Given
+		transaction := &model.Transaction{
+			Description:     "test transaction",
+			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
+			PurchaseAmount:  10000,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec(insertEventQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(completeIdempotencyQuery).
+			WithArgs(200, int64(4), "2023-11-03T12:00:00.000000Z", "key-1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		// When
+		saved, err := repository.SaveTransaction(context.TODO(), transaction, idempotentAudit)
+
+		// Then
+		assert.ErrorIs(t, err, ErrIdempotencyKeyNotReserved)
+		assert.Nil(t, saved)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -198,99 +300,220 @@ func Test_TransactionRepository_UpdateTransaction(t *testing.T) {
 
 	logger := slog.Default()
 	repository := NewTransactionRepository(logger, db)
-	updateQuery := "UPDATE transactions SET description = \\?, transaction_date = \\?, purchase_amount = \\? WHERE id = \\? AND deleted = 0"
+	updateQuery := "UPDATE transactions SET description = \\?, transaction_date = \\?, purchase_amount = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\? RETURNING id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by"
+	audit := model.Audit{Actor: "alice", RequestID: "request-1", At: time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)}
+	transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
 
 	t.Run("UpdateTransaction with success", func(t *testing.T) {
 		// Given
 		transactionID := int64(1)
-		expectedTransaction := &model.Transaction{
+		transaction := &model.Transaction{
 			ID:              transactionID,
 			Description:     "Updated Transaction",
-			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  150.0,
+			TransactionDate: transactionDate,
+			PurchaseAmount:  15000,
 		}
 
-		mock.ExpectExec(updateQuery).
-			WithArgs(expectedTransaction.Description, util.FormatDate(expectedTransaction.TransactionDate), expectedTransaction.PurchaseAmount, transactionID).
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(transactionID).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "Transaction", transactionDate.Format(time.RFC3339), 10000, false, 2, nil, nil, nil))
+		mock.ExpectQuery(updateQuery).
+			WithArgs(transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount, transactionID, int64(2)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "Updated Transaction", transactionDate.Format(time.RFC3339), 15000, false, 3, nil, nil, nil))
+		mock.ExpectExec(insertEventQuery).
+			WithArgs(transactionID, model.OperationUpdate, "alice", "request-1", "2023-11-02T12:00:00.000000Z", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).
+			WithArgs(model.EventTransactionUpdated, transactionID, sqlmock.AnyArg(), "2023-11-02T12:00:00.000000Z", "2023-11-02T12:00:00.000000Z").
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		// When
-		transaction, err := repository.UpdateTransaction(transactionID, expectedTransaction)
+		updatedTransaction, err := repository.UpdateTransaction(context.TODO(), transactionID, transaction, 2, audit)
 
 		// Then
 		assert.NoError(t, err)
-		assert.NotNil(t, transaction)
-		assert.Equal(t, expectedTransaction, transaction)
+		assert.Equal(t, &model.Transaction{
+			ID:              transactionID,
+			Description:     "Updated Transaction",
+			TransactionDate: transactionDate,
+			PurchaseAmount:  15000,
+			Version:         3,
+		}, updatedTransaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("UpdateTransaction transaction not found", func(t *testing.T) {
 		// Given
 		transactionID := int64(2)
-		transaction := &model.Transaction{
-			ID:              transactionID,
-			Description:     "Updated Transaction",
-			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  150.0,
-		}
+		transaction := &model.Transaction{ID: transactionID, Description: "Updated Transaction", TransactionDate: transactionDate, PurchaseAmount: 15000}
 
-		mock.ExpectExec(updateQuery).
-			WithArgs(transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount, transactionID).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(transactionID).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames))
+		mock.ExpectRollback()
+
+		// When
+		updatedTransaction, err := repository.UpdateTransaction(context.TODO(), transactionID, transaction, 0, audit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, updatedTransaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UpdateTransaction version is not the expected one", func(t *testing.T) {
+		// Given
+		transactionID := int64(2)
+		transaction := &model.Transaction{ID: transactionID, Description: "Updated Transaction", TransactionDate: transactionDate, PurchaseAmount: 15000}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(transactionID).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(2, "Transaction", transactionDate.Format(time.RFC3339), 10000, false, 3, nil, nil, nil))
+		mock.ExpectRollback()
+
+		// When
+		updatedTransaction, err := repository.UpdateTransaction(context.TODO(), transactionID, transaction, 2, audit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, updatedTransaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UpdateTransaction changed by another request after it was read", func(t *testing.T) {
+		// Given
+		transactionID := int64(2)
+		transaction := &model.Transaction{ID: transactionID, Description: "Updated Transaction", TransactionDate: transactionDate, PurchaseAmount: 15000}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(transactionID).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(2, "Transaction", transactionDate.Format(time.RFC3339), 10000, false, 3, nil, nil, nil))
+		mock.ExpectQuery(updateQuery).
+			WithArgs(transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount, transactionID, int64(3)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames))
+		mock.ExpectRollback()
 
 		// When
-		updatedTransaction, err := repository.UpdateTransaction(transactionID, transaction)
+		updatedTransaction, err := repository.UpdateTransaction(context.TODO(), transactionID, transaction, 0, audit)
 
 		// Then
 		assert.NoError(t, err)
 		assert.Nil(t, updatedTransaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("UpdateTransaction error on execute query", func(t *testing.T) {
 		// Given
 		transactionID := int64(3)
 		expectedErrorMessage := "mock error run query"
-		transaction := &model.Transaction{
-			ID:              transactionID,
-			Description:     "Updated Transaction",
-			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  150.0,
-		}
+		transaction := &model.Transaction{ID: transactionID, Description: "Updated Transaction", TransactionDate: transactionDate, PurchaseAmount: 15000}
 
-		mock.ExpectExec(updateQuery).
-			WithArgs(transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount, transactionID).
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(transactionID).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(3, "Transaction", transactionDate.Format(time.RFC3339), 10000, false, 1, nil, nil, nil))
+		mock.ExpectQuery(updateQuery).
+			WithArgs(transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount, transactionID, int64(1)).
 			WillReturnError(errors.New(expectedErrorMessage))
+		mock.ExpectRollback()
 
 		// When
-		updatedTransaction, err := repository.UpdateTransaction(transactionID, transaction)
+		updatedTransaction, err := repository.UpdateTransaction(context.TODO(), transactionID, transaction, 0, audit)
 
 		// Then
 		assert.Error(t, err)
 		assert.Nil(t, updatedTransaction)
 		assert.Equal(t, expectedErrorMessage, err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+}
 
-	t.Run("UpdateTransaction error on get rows", func(t *testing.T) {
+func Test_TransactionRepository_PatchTransaction(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewTransactionRepository(slog.Default(), db)
+	audit := model.Audit{Actor: "alice", At: time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)}
+	transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("PatchTransaction changes only the description", func(t *testing.T) {
 		// Given
-		transactionID := int64(4)
-		expectedErrorMessage := "mock error rows affected"
-		transaction := &model.Transaction{
-			ID:              transactionID,
-			Description:     "Updated Transaction",
-			TransactionDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  150.0,
-		}
+		description := "fixed typo"
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "fixed typp", transactionDate.Format(time.RFC3339), 15000, false, 2, nil, nil, nil))
+		mock.ExpectQuery("UPDATE transactions SET description = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\? RETURNING id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by").
+			WithArgs(description, int64(1), int64(2)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, description, transactionDate.Format(time.RFC3339), 15000, false, 3, nil, nil, nil))
+		mock.ExpectExec(insertEventQuery).
+			WithArgs(int64(1), model.OperationPatch, "alice", nil, "2023-11-02T12:00:00.000000Z", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).
+			WithArgs(model.EventTransactionUpdated, int64(1), sqlmock.AnyArg(), "2023-11-02T12:00:00.000000Z", "2023-11-02T12:00:00.000000Z").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		// When
+		transaction, err := repository.PatchTransaction(context.TODO(), 1, &model.TransactionPatch{Description: &description}, 2, audit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1, Description: description, TransactionDate: transactionDate, PurchaseAmount: 15000, Version: 3}, transaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 
-		mock.ExpectExec(updateQuery).
-			WithArgs(transaction.Description, util.FormatDate(transaction.TransactionDate), transaction.PurchaseAmount, transactionID).
-			WillReturnResult(sqlmock.NewErrorResult(errors.New(expectedErrorMessage)))
+	t.Run("PatchTransaction changes the date and the amount", func(t *testing.T) {
+		// Given
+		purchaseAmount := int64(2000)
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "description", transactionDate.Format(time.RFC3339), 1000, false, 3, nil, nil, nil))
+		mock.ExpectQuery("UPDATE transactions SET transaction_date = \\?, purchase_amount = \\?, version = version \\+ 1 WHERE id = \\?").
+			WithArgs(util.FormatDate(transactionDate), purchaseAmount, int64(1), int64(3)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "description", transactionDate.Format(time.RFC3339), purchaseAmount, false, 4, nil, nil, nil))
+		mock.ExpectExec(insertEventQuery).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(insertOutboxQuery).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		// When
-		updatedTransaction, err := repository.UpdateTransaction(transactionID, transaction)
+		transaction, err := repository.PatchTransaction(context.TODO(), 1, &model.TransactionPatch{TransactionDate: &transactionDate, PurchaseAmount: &purchaseAmount}, 0, audit)
 
 		// Then
-		assert.Error(t, err)
-		assert.Nil(t, updatedTransaction)
-		assert.Equal(t, expectedErrorMessage, err.Error())
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1, Description: "description", TransactionDate: transactionDate, PurchaseAmount: purchaseAmount, Version: 4}, transaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PatchTransaction transaction deleted", func(t *testing.T) {
+		// Given
+		description := "fixed typo"
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(2, "description", transactionDate.Format(time.RFC3339), 1000, true, 2, "2023-11-01T12:00:00Z", nil, nil))
+		mock.ExpectRollback()
+
+		// When
+		transaction, err := repository.PatchTransaction(context.TODO(), 2, &model.TransactionPatch{Description: &description}, 0, audit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, transaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -305,74 +528,481 @@ func Test_TransactionRepository_LogicalDeleteTransaction(t *testing.T) {
 
 	logger := slog.Default()
 	repository := NewTransactionRepository(logger, db)
-	deleteQuery := "UPDATE transactions SET deleted = 1 WHERE id = \\? AND deleted = 0"
+	audit := model.Audit{Actor: "alice", RequestID: "request-1", At: time.Date(2023, 10, 10, 9, 0, 0, 0, time.FixedZone("BRT", -3*60*60))}
+	deleteQuery := "UPDATE transactions SET deleted = 1, deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\? RETURNING id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by"
+	transactionDate := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
 
 	t.Run("LogicalDeleteTransaction with success", func(t *testing.T) {
 		// Given
 		transactionID := int64(1)
 
-		mock.ExpectExec(deleteQuery).
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
 			WithArgs(transactionID).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "first", transactionDate.Format(time.RFC3339), 1000, false, 2, nil, nil, nil))
+		mock.ExpectQuery(deleteQuery).
+			WithArgs("2023-10-10T12:00:00Z", transactionID, int64(2)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "first", transactionDate.Format(time.RFC3339), 1000, true, 3, "2023-10-10T12:00:00Z", nil, nil))
+		mock.ExpectExec(insertEventQuery).
+			WithArgs(transactionID, model.OperationDelete, "alice", "request-1", "2023-10-10T12:00:00.000000Z", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).
+			WithArgs(model.EventTransactionDeleted, transactionID, sqlmock.AnyArg(), "2023-10-10T12:00:00.000000Z", "2023-10-10T12:00:00.000000Z").
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		// When
-		deletedID, err := repository.LogicalDeleteTransaction(transactionID)
+		deleted, err := repository.LogicalDeleteTransaction(context.TODO(), transactionID, 2, audit)
 
 		// Then
+		deletedAt := time.Date(2023, 10, 10, 12, 0, 0, 0, time.UTC)
 		assert.NoError(t, err)
-		assert.NotNil(t, deletedID)
-		assert.Equal(t, transactionID, *deletedID)
+		assert.Equal(t, &model.Transaction{ID: 1, Description: "first", TransactionDate: transactionDate, PurchaseAmount: 1000, Deleted: true, Version: 3, DeletedAt: &deletedAt}, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("LogicalDeleteTransaction transaction not found", func(t *testing.T) {
+	t.Run("LogicalDeleteTransaction transaction already deleted", func(t *testing.T) {
 		// Given
 		transactionID := int64(2)
 
-		mock.ExpectExec(deleteQuery).
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
 			WithArgs(transactionID).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(2, "second", transactionDate.Format(time.RFC3339), 1000, true, 2, "2023-10-09T12:00:00Z", nil, nil))
+		mock.ExpectRollback()
 
 		// When
-		deletedID, err := repository.LogicalDeleteTransaction(transactionID)
+		deleted, err := repository.LogicalDeleteTransaction(context.TODO(), transactionID, 0, audit)
 
 		// Then
 		assert.NoError(t, err)
-		assert.Nil(t, deletedID)
+		assert.Nil(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("LogicalDeleteTransaction error on execute query", func(t *testing.T) {
+	t.Run("LogicalDeleteTransaction error on begin", func(t *testing.T) {
 		// Given
-		transactionID := int64(3)
-		expectedErrorMessage := "mock error run query"
+		expectedErrorMessage := "mock error begin"
+		mock.ExpectBegin().WillReturnError(errors.New(expectedErrorMessage))
+
+		// When
+		deleted, err := repository.LogicalDeleteTransaction(context.TODO(), 3, 0, audit)
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, deleted)
+		assert.Equal(t, expectedErrorMessage, err.Error())
+	})
+
+	t.Run("LogicalDeleteTransaction error on commit", func(t *testing.T) {
+		// Given
+		transactionID := int64(4)
+		expectedErrorMessage := "mock error commit"
 
-		mock.ExpectExec(deleteQuery).
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
 			WithArgs(transactionID).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(4, "fourth", transactionDate.Format(time.RFC3339), 1000, false, 1, nil, nil, nil))
+		mock.ExpectQuery(deleteQuery).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(4, "fourth", transactionDate.Format(time.RFC3339), 1000, true, 2, "2023-10-10T12:00:00Z", nil, nil))
+		mock.ExpectExec(insertEventQuery).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit().WillReturnError(errors.New(expectedErrorMessage))
+
+		// When
+		deleted, err := repository.LogicalDeleteTransaction(context.TODO(), transactionID, 0, audit)
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, deleted)
+		assert.Equal(t, expectedErrorMessage, err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_TransactionRepository_ListTransactions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	logger := slog.Default()
+	repository := NewTransactionRepository(logger, db)
+	columns := []string{"id", "description", "transaction_date", "purchase_amount", "deleted", "version", "deleted_at", "restored_at", "restored_by"}
+
+	t.Run("ListTransactions with success without filters", func(t *testing.T) {
+		// Given
+		filter := &model.TransactionFilter{Limit: 2, Offset: 0}
+		transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM transactions WHERE deleted = 0").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectQuery("SELECT id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by FROM transactions WHERE deleted = 0 ORDER BY id LIMIT \\? OFFSET \\?").
+			WithArgs(2, 0).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "first", transactionDate.Format(time.RFC3339), 1000, false, 1, nil, nil, nil).
+				AddRow(2, "second", transactionDate.Format(time.RFC3339), 2000, false, 1, nil, nil, nil))
+
+		// When
+		transactions, total, err := repository.ListTransactions(context.TODO(), filter)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []model.Transaction{
+			{ID: 1, Description: "first", TransactionDate: transactionDate, PurchaseAmount: 1000, Version: 1},
+			{ID: 2, Description: "second", TransactionDate: transactionDate, PurchaseAmount: 2000, Version: 1},
+		}, transactions)
+	})
+
+	t.Run("ListTransactions after an id", func(t *testing.T) {
+		// Given
+		filter := &model.TransactionFilter{Limit: 2, AfterID: 5}
+		transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM transactions WHERE deleted = 0 AND id > \\?").
+			WithArgs(int64(5)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by FROM transactions WHERE deleted = 0 AND id > \\? ORDER BY id LIMIT \\? OFFSET \\?").
+			WithArgs(int64(5), 2, 0).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(6, "sixth", transactionDate.Format(time.RFC3339), 1000, false, 1, nil, nil, nil))
+
+		// When
+		transactions, _, err := repository.ListTransactions(context.TODO(), filter)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Transaction{
+			{ID: 6, Description: "sixth", TransactionDate: transactionDate, PurchaseAmount: 1000, Version: 1},
+		}, transactions)
+	})
+
+	t.Run("ListTransactions with success with all filters", func(t *testing.T) {
+		// Given
+		dateFrom := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		dateTo := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+		amountMin := int64(1000)
+		amountMax := int64(10000)
+		filter := &model.TransactionFilter{
+			TransactionDateFrom: &dateFrom,
+			TransactionDateTo:   &dateTo,
+			PurchaseAmountMin:   &amountMin,
+			PurchaseAmountMax:   &amountMax,
+			Description:         "50%_off",
+			IncludeDeleted:      true,
+			Limit:               10,
+			Offset:              10,
+		}
+		where := "WHERE datetime\\(transaction_date\\) >= datetime\\(\\?\\) AND datetime\\(transaction_date\\) <= datetime\\(\\?\\) AND purchase_amount >= \\? AND purchase_amount <= \\? AND description LIKE \\? ESCAPE '\\\\'"
+		args := []driver.Value{util.FormatDate(dateFrom), util.FormatDate(dateTo), amountMin, amountMax, "%50\\%\\_off%"}
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM transactions " + where).
+			WithArgs(args...).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery("SELECT id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by FROM transactions " + where + " ORDER BY id LIMIT \\? OFFSET \\?").
+			WithArgs(append(args, 10, 10)...).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		// When
+		transactions, total, err := repository.ListTransactions(context.TODO(), filter)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, transactions)
+	})
+
+	t.Run("ListTransactions error on count query", func(t *testing.T) {
+		// Given
+		expectedErrorMessage := "mock count error"
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM transactions").
 			WillReturnError(errors.New(expectedErrorMessage))
 
 		// When
-		deletedID, err := repository.LogicalDeleteTransaction(transactionID)
+		transactions, _, err := repository.ListTransactions(context.TODO(), &model.TransactionFilter{Limit: 1})
 
 		// Then
 		assert.Error(t, err)
-		assert.Nil(t, deletedID)
+		assert.Nil(t, transactions)
 		assert.Equal(t, expectedErrorMessage, err.Error())
 	})
 
-	t.Run("LogicalDeleteTransaction error on get rows affected", func(t *testing.T) {
+	t.Run("ListTransactions error on select query", func(t *testing.T) {
 		// Given
-		transactionID := int64(4)
-		expectedErrorMessage := "mock error rows affected"
+		expectedErrorMessage := "mock select error"
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM transactions").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by FROM transactions").
+			WillReturnError(errors.New(expectedErrorMessage))
 
-		mock.ExpectExec(deleteQuery).
-			WithArgs(transactionID).
-			WillReturnResult(sqlmock.NewErrorResult(errors.New(expectedErrorMessage)))
+		// When
+		transactions, _, err := repository.ListTransactions(context.TODO(), &model.TransactionFilter{Limit: 1})
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, transactions)
+		assert.Equal(t, expectedErrorMessage, err.Error())
+	})
+}
+
+func Test_TransactionRepository_GetTransactionsByIDs(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	logger := slog.Default()
+	repository := NewTransactionRepository(logger, db)
+	columns := []string{"id", "description", "transaction_date", "purchase_amount", "deleted", "version", "deleted_at", "restored_at", "restored_by"}
+	selectQuery := "SELECT id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by FROM transactions WHERE id IN \\(\\?, \\?\\)"
+
+	t.Run("GetTransactionsByIDs with success", func(t *testing.T) {
+		// Given
+		transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(selectQuery).
+			WithArgs(int64(1), int64(2)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "first", transactionDate.Format(time.RFC3339), 1000, false, 2, nil, nil, nil).
+				AddRow(2, "second", transactionDate.Format(time.RFC3339), 2000, true, 2, "2023-11-01T12:00:00Z", nil, nil))
+
+		// When
+		transactions, err := repository.GetTransactionsByIDs(context.TODO(), []int64{1, 2})
+
+		// Then
+		deletedAt := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Transaction{
+			{ID: 1, Description: "first", TransactionDate: transactionDate, PurchaseAmount: 1000, Version: 2},
+			{ID: 2, Description: "second", TransactionDate: transactionDate, PurchaseAmount: 2000, Deleted: true, Version: 2, DeletedAt: &deletedAt},
+		}, transactions)
+	})
+
+	t.Run("GetTransactionsByIDs empty without ids", func(t *testing.T) {
+		// When
+		transactions, err := repository.GetTransactionsByIDs(context.TODO(), nil)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Empty(t, transactions)
+	})
+
+	t.Run("GetTransactionsByIDs error on select query", func(t *testing.T) {
+		// Given
+		expectedErrorMessage := "mock select error"
+		mock.ExpectQuery(selectQuery).
+			WillReturnError(errors.New(expectedErrorMessage))
 
 		// When
-		deletedID, err := repository.LogicalDeleteTransaction(transactionID)
+		transactions, err := repository.GetTransactionsByIDs(context.TODO(), []int64{1, 2})
 
 		// Then
 		assert.Error(t, err)
-		assert.Nil(t, deletedID)
+		assert.Nil(t, transactions)
 		assert.Equal(t, expectedErrorMessage, err.Error())
 	})
 }
+
+func Test_TransactionRepository_RestoreTransaction(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewTransactionRepository(slog.Default(), db)
+	restoreQuery := "UPDATE transactions SET deleted = 0, deleted_at = NULL, restored_at = \\?, restored_by = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\? RETURNING id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by"
+	transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+	restoredAt := time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)
+	audit := model.Audit{Actor: "alice", RequestID: "request-1", At: restoredAt}
+
+	t.Run("RestoreTransaction with success", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "first", transactionDate.Format(time.RFC3339), 1000, true, 3, "2023-11-01T12:00:00Z", nil, nil))
+		mock.ExpectQuery(restoreQuery).
+			WithArgs("2023-11-02T12:00:00Z", "alice", int64(1), int64(3)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(1, "first", transactionDate.Format(time.RFC3339), 1000, false, 4, nil, "2023-11-02T12:00:00Z", "alice"))
+		mock.ExpectExec(insertEventQuery).
+			WithArgs(int64(1), model.OperationRestore, "alice", "request-1", "2023-11-02T12:00:00.000000Z", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertOutboxQuery).
+			WithArgs(model.EventTransactionUpdated, int64(1), sqlmock.AnyArg(), "2023-11-02T12:00:00.000000Z", "2023-11-02T12:00:00.000000Z").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		// When
+		transaction, err := repository.RestoreTransaction(context.TODO(), 1, 3, audit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{
+			ID:              1,
+			Description:     "first",
+			TransactionDate: transactionDate,
+			PurchaseAmount:  1000,
+			Version:         4,
+			RestoredAt:      &restoredAt,
+			RestoredBy:      "alice",
+		}, transaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("RestoreTransaction transaction not deleted", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectByIDQuery).
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).AddRow(2, "second", transactionDate.Format(time.RFC3339), 1000, false, 1, nil, nil, nil))
+		mock.ExpectRollback()
+
+		// When
+		transaction, err := repository.RestoreTransaction(context.TODO(), 2, 0, audit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, transaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_TransactionRepository_PurgeDeletedTransactions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewTransactionRepository(slog.Default(), db)
+	purgeQuery := "DELETE FROM transactions WHERE deleted = 1 AND deleted_at < \\? RETURNING id, description, transaction_date, purchase_amount, deleted, version, deleted_at, restored_at, restored_by"
+	deletedBefore := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	audit := model.Audit{Actor: "purge-job", At: time.Date(2023, 10, 30, 0, 0, 0, 0, time.UTC)}
+	transactionDate := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("PurgeDeletedTransactions with success", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectQuery(purgeQuery).
+			WithArgs("2023-08-01T00:00:00Z").
+			WillReturnRows(sqlmock.NewRows(transactionColumnNames).
+				AddRow(3, "third", transactionDate.Format(time.RFC3339), 1000, true, 2, "2023-07-01T00:00:00Z", nil, nil).
+				AddRow(7, "seventh", transactionDate.Format(time.RFC3339), 2000, true, 2, "2023-07-02T00:00:00Z", nil, nil))
+		mock.ExpectExec(insertEventQuery).
+			WithArgs(int64(3), model.OperationPurge, "purge-job", nil, "2023-10-30T00:00:00.000000Z", sqlmock.AnyArg(), nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertEventQuery).
+			WithArgs(int64(7), model.OperationPurge, "purge-job", nil, "2023-10-30T00:00:00.000000Z", sqlmock.AnyArg(), nil).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		// When
+		purgedIDs, err := repository.PurgeDeletedTransactions(context.TODO(), deletedBefore, audit)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{3, 7}, purgedIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PurgeDeletedTransactions error on execute query", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectQuery(purgeQuery).
+			WithArgs("2023-08-01T00:00:00Z").
+			WillReturnError(errors.New("database is locked"))
+		mock.ExpectRollback()
+
+		// When
+		purgedIDs, err := repository.PurgeDeletedTransactions(context.TODO(), deletedBefore, audit)
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+		assert.Nil(t, purgedIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_TransactionRepository_GetTransactionEvents(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewTransactionRepository(slog.Default(), db)
+	selectQuery := "SELECT id, transaction_id, operation, actor, request_id, occurred_at, old_value, new_value FROM transaction_events WHERE transaction_id = \\?"
+	columns := []string{"id", "transaction_id", "operation", "actor", "request_id", "occurred_at", "old_value", "new_value"}
+	created := `{"description":"first","transaction_date":"2023-10-10T00:00:00Z","purchase_amount":1000,"deleted":false,"version":1,"deleted_at":null,"restored_at":null,"restored_by":null}`
+	deleted := `{"description":"first","transaction_date":"2023-10-10T00:00:00Z","purchase_amount":1000,"deleted":true,"version":2,"deleted_at":"2023-11-01T12:00:00Z","restored_at":null,"restored_by":null}`
+	transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("GetTransactionEvents with success", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery(selectQuery + " ORDER BY id").
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, "create", "alice", "request-1", "2023-10-10T12:00:00.000000Z", nil, created).
+				AddRow(2, 1, "delete", "bob", nil, "2023-11-01T12:00:00.250000Z", created, deleted))
+
+		// When
+		events, err := repository.GetTransactionEvents(context.TODO(), 1, nil)
+
+		// Then
+		createdTransaction := &model.Transaction{ID: 1, Description: "first", TransactionDate: transactionDate, PurchaseAmount: 1000, Version: 1}
+		assert.NoError(t, err)
+		assert.Equal(t, []model.TransactionEvent{
+			{ID: 1, TransactionID: 1, Operation: model.OperationCreate, Actor: "alice", RequestID: "request-1", OccurredAt: time.Date(2023, 10, 10, 12, 0, 0, 0, time.UTC), NewValue: createdTransaction},
+			{ID: 2, TransactionID: 1, Operation: model.OperationDelete, Actor: "bob", OccurredAt: time.Date(2023, 11, 1, 12, 0, 0, 250000000, time.UTC), OldValue: createdTransaction,
+				NewValue: &model.Transaction{ID: 1, Description: "first", TransactionDate: transactionDate, PurchaseAmount: 1000, Deleted: true, Version: 2, DeletedAt: &deletedAt}},
+		}, events)
+	})
+
+	t.Run("GetTransactionEvents until a point in time", func(t *testing.T) {
+		// Given
+		until := time.Date(2023, 10, 20, 9, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+		mock.ExpectQuery(selectQuery+" AND occurred_at <= \\? ORDER BY id").
+			WithArgs(int64(1), "2023-10-20T12:00:00.000000Z").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, "create", "alice", "request-1", "2023-10-10T12:00:00.000000Z", nil, created))
+
+		// When
+		events, err := repository.GetTransactionEvents(context.TODO(), 1, &until)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("GetTransactionEvents error on invalid snapshot", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery(selectQuery).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, "create", "alice", nil, "2023-10-10T12:00:00.000000Z", nil, "{"))
+
+		// When
+		events, err := repository.GetTransactionEvents(context.TODO(), 1, nil)
+
+		// Then
+		assert.EqualError(t, err, "unexpected end of JSON input")
+		assert.Nil(t, events)
+	})
+}
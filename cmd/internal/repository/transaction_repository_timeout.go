@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+// TransactionRepositoryTimeout is a TransactionRepository that gives each method of the wrapped repository a
+// deadline, the read timeout for the queries and the write timeout for the changes, on top of the one of the
+// request
+type TransactionRepositoryTimeout struct {
+	repository   TransactionRepository
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+func NewTransactionRepositoryTimeout(repository TransactionRepository, readTimeout, writeTimeout time.Duration) *TransactionRepositoryTimeout {
+	return &TransactionRepositoryTimeout{
+		repository:   repository,
+		readTimeout:  readTimeout,
+		writeTimeout: writeTimeout,
+	}
+}
+
+func (t *TransactionRepositoryTimeout) GetTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.readTimeout)
+	defer cancel()
+	return t.repository.GetTransaction(ctx, transactionID)
+}
+
+func (t *TransactionRepositoryTimeout) SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*model.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.writeTimeout)
+	defer cancel()
+	return t.repository.SaveTransaction(ctx, transaction, audit)
+}
+
+func (t *TransactionRepositoryTimeout) UpdateTransaction(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.writeTimeout)
+	defer cancel()
+	return t.repository.UpdateTransaction(ctx, transactionID, transaction, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTimeout) PatchTransaction(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.writeTimeout)
+	defer cancel()
+	return t.repository.PatchTransaction(ctx, transactionID, patch, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTimeout) LogicalDeleteTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.writeTimeout)
+	defer cancel()
+	return t.repository.LogicalDeleteTransaction(ctx, transactionID, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTimeout) RestoreTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*model.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.writeTimeout)
+	defer cancel()
+	return t.repository.RestoreTransaction(ctx, transactionID, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTimeout) PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time, audit model.Audit) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.writeTimeout)
+	defer cancel()
+	return t.repository.PurgeDeletedTransactions(ctx, deletedBefore, audit)
+}
+
+func (t *TransactionRepositoryTimeout) ListTransactions(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.readTimeout)
+	defer cancel()
+	return t.repository.ListTransactions(ctx, filter)
+}
+
+func (t *TransactionRepositoryTimeout) GetTransactionsByIDs(ctx context.Context, transactionIDs []int64) ([]model.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.readTimeout)
+	defer cancel()
+	return t.repository.GetTransactionsByIDs(ctx, transactionIDs)
+}
+
+func (t *TransactionRepositoryTimeout) GetTransactionEvents(ctx context.Context, transactionID int64, until *time.Time) ([]model.TransactionEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.readTimeout)
+	defer cancel()
+	return t.repository.GetTransactionEvents(ctx, transactionID, until)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionRepositoryTimeout(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+	repository := NewTransactionRepositoryTimeout(mockRepository, time.Second, time.Minute)
+
+	t.Run("Read with the read timeout", func(t *testing.T) {
+		// Given
+		var deadline time.Time
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), int64(1)).DoAndReturn(func(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+			deadline, _ = ctx.Deadline()
+			return &model.Transaction{ID: 1}, nil
+		})
+
+		// When
+		transaction, err := repository.GetTransaction(context.TODO(), 1)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1}, transaction)
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 500*time.Millisecond)
+	})
+
+	t.Run("Write with the write timeout", func(t *testing.T) {
+		// Given
+		var deadline time.Time
+		mockRepository.EXPECT().SaveTransaction(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*model.Transaction, error) {
+			deadline, _ = ctx.Deadline()
+			return transaction, nil
+		})
+
+		// When
+		_, err := repository.SaveTransaction(context.TODO(), &model.Transaction{}, model.Audit{})
+
+		// Then
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 500*time.Millisecond)
+	})
+
+	t.Run("Keep the earlier deadline of the request", func(t *testing.T) {
+		// Given
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		requestDeadline, _ := ctx.Deadline()
+		var deadline time.Time
+		mockRepository.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, int64, error) {
+			deadline, _ = ctx.Deadline()
+			return nil, 0, nil
+		})
+
+		// When
+		_, _, err := repository.ListTransactions(ctx, &model.TransactionFilter{})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, requestDeadline, deadline)
+	})
+}
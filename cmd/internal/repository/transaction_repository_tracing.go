@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+
+// TransactionRepositoryTracing is a TransactionRepository that traces each method of the wrapped repository as a
+// client span of the database
+type TransactionRepositoryTracing struct {
+	repository TransactionRepository
+	tracer     trace.Tracer
+}
+
+func NewTransactionRepositoryTracing(repository TransactionRepository) *TransactionRepositoryTracing {
+	return &TransactionRepositoryTracing{
+		repository: repository,
+		tracer:     otel.Tracer(tracerName),
+	}
+}
+
+func (t *TransactionRepositoryTracing) start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "TransactionRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attributes, attribute.String("db.system", "sqlite"), attribute.String("db.operation.name", method))...))
+}
+
+func (t *TransactionRepositoryTracing) GetTransaction(ctx context.Context, transactionID int64) (transaction *model.Transaction, err error) {
+	ctx, span := t.start(ctx, "GetTransaction", attribute.Int64("transaction.id", transactionID))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.GetTransaction(ctx, transactionID)
+}
+
+func (t *TransactionRepositoryTracing) SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (saved *model.Transaction, err error) {
+	ctx, span := t.start(ctx, "SaveTransaction")
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.SaveTransaction(ctx, transaction, audit)
+}
+
+func (t *TransactionRepositoryTracing) UpdateTransaction(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (updated *model.Transaction, err error) {
+	ctx, span := t.start(ctx, "UpdateTransaction", attribute.Int64("transaction.id", transactionID))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.UpdateTransaction(ctx, transactionID, transaction, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTracing) PatchTransaction(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (patched *model.Transaction, err error) {
+	ctx, span := t.start(ctx, "PatchTransaction", attribute.Int64("transaction.id", transactionID))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.PatchTransaction(ctx, transactionID, patch, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTracing) LogicalDeleteTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (deleted *model.Transaction, err error) {
+	ctx, span := t.start(ctx, "LogicalDeleteTransaction", attribute.Int64("transaction.id", transactionID))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.LogicalDeleteTransaction(ctx, transactionID, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTracing) RestoreTransaction(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (restored *model.Transaction, err error) {
+	ctx, span := t.start(ctx, "RestoreTransaction", attribute.Int64("transaction.id", transactionID))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.RestoreTransaction(ctx, transactionID, expectedVersion, audit)
+}
+
+func (t *TransactionRepositoryTracing) PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time, audit model.Audit) (purgedIDs []int64, err error) {
+	ctx, span := t.start(ctx, "PurgeDeletedTransactions")
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.PurgeDeletedTransactions(ctx, deletedBefore, audit)
+}
+
+func (t *TransactionRepositoryTracing) ListTransactions(ctx context.Context, filter *model.TransactionFilter) (transactions []model.Transaction, total int64, err error) {
+	ctx, span := t.start(ctx, "ListTransactions", attribute.Int("page.limit", filter.Limit), attribute.Int("page.offset", filter.Offset))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.ListTransactions(ctx, filter)
+}
+
+func (t *TransactionRepositoryTracing) GetTransactionsByIDs(ctx context.Context, transactionIDs []int64) (transactions []model.Transaction, err error) {
+	ctx, span := t.start(ctx, "GetTransactionsByIDs", attribute.Int("transaction.count", len(transactionIDs)))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.GetTransactionsByIDs(ctx, transactionIDs)
+}
+
+func (t *TransactionRepositoryTracing) GetTransactionEvents(ctx context.Context, transactionID int64, until *time.Time) (events []model.TransactionEvent, err error) {
+	ctx, span := t.start(ctx, "GetTransactionEvents", attribute.Int64("transaction.id", transactionID))
+	defer func() { util.EndSpan(span, err) }()
+	return t.repository.GetTransactionEvents(ctx, transactionID, until)
+}
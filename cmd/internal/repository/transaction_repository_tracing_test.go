@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_TransactionRepositoryTracing(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+
+	newRepository := func() (*TransactionRepositoryTracing, *tracetest.SpanRecorder) {
+		recorder := tracetest.NewSpanRecorder()
+		repository := NewTransactionRepositoryTracing(mockRepository)
+		repository.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+		return repository, recorder
+	}
+
+	t.Run("Trace the method as a child of the span in the context", func(t *testing.T) {
+		// Given
+		repository, recorder := newRepository()
+		parentCtx, parent := repository.tracer.Start(context.Background(), "GET /v1/transaction/{id}")
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), int64(1)).DoAndReturn(func(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+			// the wrapped repository runs inside the span
+			assert.Equal(t, "TransactionRepository.GetTransaction", trace.SpanFromContext(ctx).(sdktrace.ReadOnlySpan).Name())
+			return &model.Transaction{ID: 1}, nil
+		})
+
+		// When
+		transaction, err := repository.GetTransaction(parentCtx, 1)
+		parent.End()
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transaction{ID: 1}, transaction)
+		span := recorder.Ended()[0]
+		assert.Equal(t, "TransactionRepository.GetTransaction", span.Name())
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(), attribute.String("db.system", "sqlite"))
+		assert.Contains(t, span.Attributes(), attribute.Int64("transaction.id", 1))
+	})
+
+	t.Run("Trace the error of the method", func(t *testing.T) {
+		// Given
+		repository, recorder := newRepository()
+		mockRepository.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).Return(nil, int64(0), errors.New("database is locked"))
+
+		// When
+		_, _, err := repository.ListTransactions(context.Background(), &model.TransactionFilter{Limit: 20})
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+		span := recorder.Ended()[0]
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, "database is locked", span.Status().Description)
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+// transactionSnapshot is the JSON stored in the events, with the values formatted as in the transactions table
+type transactionSnapshot struct {
+	Description     string  `json:"description"`
+	TransactionDate string  `json:"transaction_date"`
+	PurchaseAmount  int64   `json:"purchase_amount"`
+	Deleted         bool    `json:"deleted"`
+	Version         int64   `json:"version"`
+	DeletedAt       *string `json:"deleted_at"`
+	RestoredAt      *string `json:"restored_at"`
+	RestoredBy      *string `json:"restored_by"`
+}
+
+func formatSnapshot(transaction *model.Transaction) (sql.NullString, error) {
+	if transaction == nil {
+		return sql.NullString{}, nil
+	}
+
+	snapshot := transactionSnapshot{
+		Description:     transaction.Description,
+		TransactionDate: util.FormatDate(transaction.TransactionDate),
+		PurchaseAmount:  transaction.PurchaseAmount,
+		Deleted:         transaction.Deleted,
+		Version:         transaction.Version,
+	}
+
+	if transaction.DeletedAt != nil {
+		deletedAt := formatTimestamp(*transaction.DeletedAt)
+		snapshot.DeletedAt = &deletedAt
+	}
+
+	if transaction.RestoredAt != nil {
+		restoredAt := formatTimestamp(*transaction.RestoredAt)
+		snapshot.RestoredAt = &restoredAt
+	}
+
+	if transaction.RestoredBy != "" {
+		snapshot.RestoredBy = &transaction.RestoredBy
+	}
+
+	value, err := json.Marshal(snapshot)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(value), Valid: true}, nil
+}
+
+func parseSnapshot(transactionID int64, value sql.NullString) (*model.Transaction, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+
+	var snapshot transactionSnapshot
+	if err := json.Unmarshal([]byte(value.String), &snapshot); err != nil {
+		return nil, err
+	}
+
+	transactionDate, err := util.ParseDate(snapshot.TransactionDate)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := &model.Transaction{
+		ID:              transactionID,
+		Description:     snapshot.Description,
+		TransactionDate: transactionDate,
+		PurchaseAmount:  snapshot.PurchaseAmount,
+		Deleted:         snapshot.Deleted,
+		Version:         snapshot.Version,
+	}
+
+	if transaction.DeletedAt, err = parseTimestamp(nullString(snapshot.DeletedAt)); err != nil {
+		return nil, err
+	}
+
+	if transaction.RestoredAt, err = parseTimestamp(nullString(snapshot.RestoredAt)); err != nil {
+		return nil, err
+	}
+
+	transaction.RestoredBy = nullString(snapshot.RestoredBy).String
+	return transaction, nil
+}
+
+func nullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: *value, Valid: true}
+}
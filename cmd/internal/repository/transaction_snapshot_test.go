@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionSnapshot(t *testing.T) {
+	transactionDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+	restoredAt := time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		transaction *model.Transaction
+		expected    sql.NullString
+	}{
+		{
+			name:        "Snapshot of no transaction is null",
+			transaction: nil,
+			expected:    sql.NullString{},
+		},
+		{
+			name:        "Snapshot of a restored transaction",
+			transaction: &model.Transaction{ID: 1, Description: "first", TransactionDate: transactionDate, PurchaseAmount: 1000, Version: 4, RestoredAt: &restoredAt, RestoredBy: "alice"},
+			expected: sql.NullString{
+				String: `{"description":"first","transaction_date":"` + transactionDate.Local().Format(time.RFC3339) + `","purchase_amount":1000,"deleted":false,"version":4,"deleted_at":null,"restored_at":"2023-11-02T12:00:00Z","restored_by":"alice"}`,
+				Valid:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			snapshot, err := formatSnapshot(tt.transaction)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, snapshot)
+
+			transaction, err := parseSnapshot(1, snapshot)
+			assert.NoError(t, err)
+			if tt.transaction == nil {
+				assert.Nil(t, transaction)
+				return
+			}
+
+			assert.True(t, tt.transaction.TransactionDate.Equal(transaction.TransactionDate))
+			assert.Equal(t, tt.transaction.Version, transaction.Version)
+			assert.Equal(t, tt.transaction.RestoredAt, transaction.RestoredAt)
+			assert.Equal(t, tt.transaction.RestoredBy, transaction.RestoredBy)
+		})
+	}
+}
@@ -13,8 +13,19 @@ import (
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 )
 
+const (
+	// exchangeRateWindowMonths is how far before the transaction date an effective rate is still acceptable
+	exchangeRateWindowMonths = 6
+	treasuryDateFormat       = "2006-01-02"
+)
+
 type TreasuryRepository interface {
-	GetExchangeRateByCountry(ctx context.Context, country string) (*model.TreasuryRatesExchange, error)
+	GetExchangeRateByCountry(ctx context.Context, country string, transactionDate time.Time) (*model.TreasuryRatesExchange, error)
+}
+
+// TreasuryDatasetRepository pages through the whole rates of exchange dataset
+type TreasuryDatasetRepository interface {
+	GetExchangeRatesPage(ctx context.Context, recordDateFrom string, pageNumber, pageSize int) (*model.TreasuryRatesExchange, error)
 }
 
 //go:generate mockgen -source=./treasury_repository.go -destination=./mocks/treasury_repository_mock.go
@@ -26,28 +37,57 @@ type TreasuryRepositoryImpl struct {
 	log    *slog.Logger
 }
 
-func NewTreasuryRepository(domain, path string, timeout time.Duration, log *slog.Logger) *TreasuryRepositoryImpl {
+// NewTreasuryRepository calls the treasury api through the transport, the default one when it is nil
+func NewTreasuryRepository(domain, path string, timeout time.Duration, transport http.RoundTripper, log *slog.Logger) *TreasuryRepositoryImpl {
 	return &TreasuryRepositoryImpl{
 		domain: domain,
 		path:   path,
 		client: http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport,
 		},
 		log: log,
 	}
 }
 
-func (r *TreasuryRepositoryImpl) GetExchangeRateByCountry(ctx context.Context, country string) (*model.TreasuryRatesExchange, error) {
+// GetExchangeRateByCountry recovers the most recent exchange rate of the country whose effective date is
+// on or before the transaction date and no more than six months earlier
+func (r *TreasuryRepositoryImpl) GetExchangeRateByCountry(ctx context.Context, country string, transactionDate time.Time) (*model.TreasuryRatesExchange, error) {
 
-	completeUrl := fmt.Sprintf(
-		"%s%s?fields=record_date,country,exchange_rate,currency,effective_date&filter=country:eq:%s&sort=-record_date&page[number]=1&page[size]=1&format=json",
-		r.domain,
-		r.path,
+	query := fmt.Sprintf(
+		"fields=record_date,country,exchange_rate,currency,effective_date&filter=country:eq:%s,effective_date:lte:%s,effective_date:gte:%s&sort=-effective_date&page[number]=1&page[size]=1&format=json",
 		url.QueryEscape(country),
+		transactionDate.Format(treasuryDateFormat),
+		transactionDate.AddDate(0, -exchangeRateWindowMonths, 0).Format(treasuryDateFormat),
+	)
+
+	return r.getRatesOfExchange(ctx, query)
+}
+
+// GetExchangeRatesPage recovers one page of all the exchange rates recorded on or after recordDateFrom,
+// sorted by record date
+func (r *TreasuryRepositoryImpl) GetExchangeRatesPage(ctx context.Context, recordDateFrom string, pageNumber, pageSize int) (*model.TreasuryRatesExchange, error) {
+
+	query := fmt.Sprintf(
+		"fields=record_date,country,exchange_rate,currency,effective_date&filter=record_date:gte:%s&sort=record_date&page[number]=%d&page[size]=%d&format=json",
+		url.QueryEscape(recordDateFrom),
+		pageNumber,
+		pageSize,
 	)
 
+	return r.getRatesOfExchange(ctx, query)
+}
+
+func (r *TreasuryRepositoryImpl) getRatesOfExchange(ctx context.Context, query string) (*model.TreasuryRatesExchange, error) {
+	completeUrl := fmt.Sprintf("%s%s?%s", r.domain, r.path, query)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, completeUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	r.log.Info("Executing api call to", "url", completeUrl)
-	resp, err := r.client.Get(completeUrl)
+	resp, err := r.client.Do(request)
 	if err != nil {
 		return nil, err
 	}
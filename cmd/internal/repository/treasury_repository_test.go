@@ -6,11 +6,15 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func Test_GetExchangeRateByCountry_APICall(t *testing.T) {
@@ -72,9 +76,10 @@ func Test_GetExchangeRateByCountry_APICall(t *testing.T) {
 				mockServer.URL,
 				"/services/api/fiscal_service/v1/accounting/od/rates_of_exchange",
 				20*time.Millisecond,
+				nil,
 				slog.Default(),
 			)
-			result, err := repo.GetExchangeRateByCountry(context.TODO(), tt.country)
+			result, err := repo.GetExchangeRateByCountry(context.TODO(), tt.country, time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC))
 
 			if err != nil {
 				assert.Error(t, err)
@@ -87,13 +92,80 @@ func Test_GetExchangeRateByCountry_APICall(t *testing.T) {
 	}
 }
 
+func Test_GetExchangeRateByCountry_TransactionDateFilter(t *testing.T) {
+	var receivedQuery url.Values
+	mockServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedQuery = r.URL.Query()
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": []}`))
+		}))
+	defer mockServer.Close()
+
+	repo := NewTreasuryRepository(mockServer.URL, "/rates_of_exchange", 1*time.Second, nil, slog.Default())
+
+	_, err := repo.GetExchangeRateByCountry(context.TODO(), "Brazil", time.Date(2024, 8, 31, 10, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "country:eq:Brazil,effective_date:lte:2024-08-31,effective_date:gte:2024-03-02", receivedQuery.Get("filter"))
+	assert.Equal(t, "-effective_date", receivedQuery.Get("sort"))
+	assert.Equal(t, "1", receivedQuery.Get("page[size]"))
+}
+
+func Test_GetExchangeRatesPage(t *testing.T) {
+	var receivedQuery url.Values
+	mockServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedQuery = r.URL.Query()
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": [{"record_date": "2024-09-30","country": "Brazil","exchange_rate": "5.434","currency": "Real","effective_date": "2024-09-30"}], "meta": {"total-pages": 3}, "links": {"next": "&page%5Bnumber%5D=3"}}`))
+		}))
+	defer mockServer.Close()
+
+	repo := NewTreasuryRepository(mockServer.URL, "/rates_of_exchange", 1*time.Second, nil, slog.Default())
+
+	result, err := repo.GetExchangeRatesPage(context.TODO(), "2024-06-30", 2, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "record_date:gte:2024-06-30", receivedQuery.Get("filter"))
+	assert.Equal(t, "record_date", receivedQuery.Get("sort"))
+	assert.Equal(t, "2", receivedQuery.Get("page[number]"))
+	assert.Equal(t, "500", receivedQuery.Get("page[size]"))
+	assert.Len(t, result.Data, 1)
+	assert.Equal(t, 3, result.Meta.TotalPages)
+	assert.Equal(t, "&page%5Bnumber%5D=3", *result.Links["next"])
+}
+
 func Test_GetExchangeRateByCountry_Client(t *testing.T) {
 
 	t.Run("GetExchangeRateByCountry error on create client", func(t *testing.T) {
-		treasuryRepository := NewTreasuryRepository("http://127.0.0.1", "\u2342", 1*time.Second, slog.Default())
+		treasuryRepository := NewTreasuryRepository("http://127.0.0.1", "\u2342", 1*time.Second, nil, slog.Default())
 
-		_, err := treasuryRepository.GetExchangeRateByCountry(context.TODO(), "Brazil")
+		_, err := treasuryRepository.GetExchangeRateByCountry(context.TODO(), "Brazil", time.Now())
 
 		assert.Error(t, err)
 	})
 }
+
+func Test_GetExchangeRateByCountry_TraceContext(t *testing.T) {
+	// Given
+	var traceparent string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer mockServer.Close()
+
+	provider := sdktrace.NewTracerProvider()
+	transport := otelhttp.NewTransport(nil, otelhttp.WithTracerProvider(provider), otelhttp.WithPropagators(propagation.TraceContext{}))
+	repo := NewTreasuryRepository(mockServer.URL, "/rates_of_exchange", 1*time.Second, transport, slog.Default())
+	ctx, span := provider.Tracer("test").Start(context.Background(), "GET /v1/converter/transaction/{id}/currency/{country}")
+	defer span.End()
+
+	// When
+	_, err := repo.GetExchangeRateByCountry(ctx, "Brazil", time.Date(2024, 8, 31, 10, 0, 0, 0, time.UTC))
+
+	// Then
+	assert.NoError(t, err)
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+}
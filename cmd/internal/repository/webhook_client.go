@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+// WebhookClient posts a signed delivery to a subscriber and returns the status it responded
+type WebhookClient interface {
+	Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+//go:generate mockgen -source=./webhook_client.go -destination=./mocks/webhook_client_mock.go
+
+type WebhookClientImpl struct {
+	client http.Client
+}
+
+// NewWebhookClient connects only to public addresses, checked on each connection after the DNS resolution so a
+// subscriber cannot rebind its host to the internal network, and does not follow redirects
+func NewWebhookClient(timeout time.Duration) *WebhookClientImpl {
+	return newWebhookClient(timeout, publicAddressOnly)
+}
+
+func newWebhookClient(timeout time.Duration, control func(network, address string, conn syscall.RawConn) error) *WebhookClientImpl {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the only address checked
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}).DialContext
+
+	return &WebhookClientImpl{
+		client: http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			// the redirect is the response of the delivery, a status other than 2xx fails it
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (w *WebhookClientImpl) Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return response.StatusCode, nil
+}
+
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !util.IsPublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WebhookClient_Post(t *testing.T) {
+	t.Run("Post returns the status of the subscriber", func(t *testing.T) {
+		// Given
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		client := newWebhookClient(time.Second, nil)
+
+		// When
+		status, err := client.Post(context.Background(), server.URL, map[string]string{"X-Webhook-Signature": "sha256=abc"}, []byte(`{"event_id":7}`))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, status)
+		assert.Equal(t, http.MethodPost, received.Method)
+		assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+		assert.Equal(t, "sha256=abc", received.Header.Get("X-Webhook-Signature"))
+		assert.Equal(t, `{"event_id":7}`, string(body))
+	})
+
+	t.Run("Post error on unreachable subscriber", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		client := newWebhookClient(time.Second, nil)
+
+		// When
+		status, err := client.Post(context.Background(), server.URL, nil, []byte(`{}`))
+
+		// Then
+		assert.Error(t, err)
+		assert.Zero(t, status)
+	})
+	t.Run("Post error on subscriber in a loopback address", func(t *testing.T) {
+		// Given
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+		client := NewWebhookClient(time.Second)
+
+		// When
+		status, err := client.Post(context.Background(), server.URL, nil, []byte(`{}`))
+
+		// Then
+		assert.ErrorContains(t, err, "webhook address 127.0.0.1 is not public")
+		assert.Zero(t, status)
+		assert.False(t, called)
+	})
+
+	t.Run("Post does not follow redirects", func(t *testing.T) {
+		// Given
+		redirected := false
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			redirected = true
+		}))
+		defer target.Close()
+		server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+		defer server.Close()
+		client := newWebhookClient(time.Second, nil)
+
+		// When
+		status, err := client.Post(context.Background(), server.URL, nil, []byte(`{}`))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTemporaryRedirect, status)
+		assert.False(t, redirected)
+	})
+}
+
+func Test_PublicAddressOnly(t *testing.T) {
+	tests := []struct {
+		address       string
+		expectedError string
+	}{
+		{address: "93.184.216.34:443"},
+		{address: "127.0.0.1:8080", expectedError: "webhook address 127.0.0.1 is not public"},
+		{address: "169.254.169.254:80", expectedError: "webhook address 169.254.169.254 is not public"},
+		{address: "10.1.2.3:80", expectedError: "webhook address 10.1.2.3 is not public"},
+		{address: "[::1]:80", expectedError: "webhook address ::1 is not public"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := publicAddressOnly("tcp", tt.address, nil)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
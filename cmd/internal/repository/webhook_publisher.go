@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+const (
+	EventIDHeader   = "X-Event-ID"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// WebhookEventPublisher posts each event to an URL, any status other than 2xx is a failed delivery
+type WebhookEventPublisher struct {
+	url    string
+	client http.Client
+	log    *slog.Logger
+}
+
+func NewWebhookEventPublisher(url string, timeout time.Duration, log *slog.Logger) *WebhookEventPublisher {
+	return &WebhookEventPublisher{
+		url: url,
+		client: http.Client{
+			Timeout: timeout,
+		},
+		log: log,
+	}
+}
+
+func (w *WebhookEventPublisher) Publish(ctx context.Context, message *model.EventMessage) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(message.Body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, strconv.FormatInt(message.ID, 10))
+	request.Header.Set(EventTypeHeader, string(message.Type))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	w.log.Debug("Event delivered to webhook", "event_id", message.ID, "event_type", message.Type)
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WebhookEventPublisher_Publish(t *testing.T) {
+	message := &model.EventMessage{ID: 7, Type: model.EventTransactionUpdated, Body: []byte(`{"event_id":7}`)}
+
+	tests := []struct {
+		name          string
+		status        int
+		expectedError string
+	}{
+		{name: "Publish with success", status: http.StatusNoContent},
+		{name: "Publish error on status not 2xx", status: http.StatusServiceUnavailable, expectedError: "webhook responded with status 503"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			var received *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			publisher := NewWebhookEventPublisher(server.URL, time.Second, slog.Default())
+
+			// When
+			err := publisher.Publish(context.Background(), message)
+
+			// Then
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, http.MethodPost, received.Method)
+			assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+			assert.Equal(t, "7", received.Header.Get(EventIDHeader))
+			assert.Equal(t, "transaction.updated", received.Header.Get(EventTypeHeader))
+			assert.Equal(t, `{"event_id":7}`, string(body))
+		})
+	}
+
+	t.Run("Publish error on unreachable webhook", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		publisher := NewWebhookEventPublisher(server.URL, time.Second, slog.Default())
+
+		// When
+		err := publisher.Publish(context.Background(), message)
+
+		// Then
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+)
+
+const (
+	subscriptionColumns = "id, url, event_types, secret, active, consecutive_failures, disabled_at, created_at"
+	deliveryColumns     = "id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at"
+)
+
+type WebhookRepository interface {
+	SaveSubscription(subscription *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	GetSubscription(subscriptionID int64) (*model.WebhookSubscription, error)
+	ListSubscriptions() ([]model.WebhookSubscription, error)
+	DeleteSubscription(subscriptionID int64) (bool, error)
+	EnableSubscription(subscriptionID int64) (*model.WebhookSubscription, error)
+	EnqueueDeliveries(eventID int64, eventType model.EventType, payload []byte, now time.Time) (int64, error)
+	GetPendingDeliveries(now time.Time, limit int) ([]model.PendingWebhookDelivery, error)
+	RecordDeliverySuccess(attempt *model.WebhookAttempt) error
+	RecordDeliveryFailure(attempt *model.WebhookAttempt, disableAfter int) (bool, error)
+	ListDeliveries(subscriptionID int64, limit, offset int) ([]model.WebhookDelivery, int64, error)
+}
+
+//go:generate mockgen -source=./webhook_repository.go -destination=./mocks/webhook_repository_mock.go
+
+type WebhookRepositoryImpl struct {
+	log *slog.Logger
+	db  *sql.DB
+}
+
+func NewWebhookRepository(log *slog.Logger, db *sql.DB) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (w *WebhookRepositoryImpl) SaveSubscription(subscription *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := w.db.Exec(
+		"INSERT INTO webhook_subscriptions (url, event_types, secret, created_at) VALUES (?, ?, ?, ?)",
+		subscription.URL,
+		string(eventTypes),
+		subscription.Secret,
+		formatEventTime(subscription.CreatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.ID, _ = result.LastInsertId()
+	subscription.Active = true
+	return subscription, nil
+}
+
+func (w *WebhookRepositoryImpl) GetSubscription(subscriptionID int64) (*model.WebhookSubscription, error) {
+	result, err := w.db.Query("SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE id = ?", subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer result.Close()
+
+	if result.Next() {
+		return w.scanSubscription(result)
+	}
+
+	return nil, result.Err()
+}
+
+func (w *WebhookRepositoryImpl) ListSubscriptions() ([]model.WebhookSubscription, error) {
+	result, err := w.db.Query("SELECT " + subscriptionColumns + " FROM webhook_subscriptions ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+
+	defer result.Close()
+
+	subscriptions := []model.WebhookSubscription{}
+	for result.Next() {
+		subscription, err := w.scanSubscription(result)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription removes the subscription with its delivery log and returns false when it does not exist
+func (w *WebhookRepositoryImpl) DeleteSubscription(subscriptionID int64) (bool, error) {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM webhook_deliveries WHERE subscription_id = ?", subscriptionID); err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec("DELETE FROM webhook_subscriptions WHERE id = ?", subscriptionID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, tx.Commit()
+}
+
+// EnableSubscription reactivates a subscription disabled after too many failures, its pending deliveries are
+// sent again
+func (w *WebhookRepositoryImpl) EnableSubscription(subscriptionID int64) (*model.WebhookSubscription, error) {
+	result, err := w.db.Query(
+		"UPDATE webhook_subscriptions SET active = 1, consecutive_failures = 0, disabled_at = NULL WHERE id = ? RETURNING "+subscriptionColumns,
+		subscriptionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer result.Close()
+
+	if result.Next() {
+		return w.scanSubscription(result)
+	}
+
+	return nil, result.Err()
+}
+
+// EnqueueDeliveries creates a delivery of the event to each active subscription of its type and returns how many
+// were created. An event published again does not create the same delivery twice
+func (w *WebhookRepositoryImpl) EnqueueDeliveries(eventID int64, eventType model.EventType, payload []byte, now time.Time) (int64, error) {
+	result, err := w.db.Exec(
+		`INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, next_attempt_at, created_at)
+		SELECT id, ?, ?, ?, ?, ? FROM webhook_subscriptions
+		WHERE active = 1 AND EXISTS (SELECT 1 FROM json_each(event_types) WHERE value IN (?, ?))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		eventID,
+		eventType,
+		string(payload),
+		formatEventTime(now),
+		formatEventTime(now),
+		eventType,
+		model.AllEvents,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetPendingDeliveries returns the oldest deliveries due of the active subscriptions
+func (w *WebhookRepositoryImpl) GetPendingDeliveries(now time.Time, limit int) ([]model.PendingWebhookDelivery, error) {
+	result, err := w.db.Query(
+		`SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+		d.last_status_code, d.last_error, d.created_at, d.delivered_at, s.url, s.secret
+		FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND s.active = 1 ORDER BY d.id LIMIT ?`,
+		model.DeliveryPending,
+		formatEventTime(now),
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer result.Close()
+
+	deliveries := make([]model.PendingWebhookDelivery, 0, limit)
+	for result.Next() {
+		var delivery model.PendingWebhookDelivery
+		if err := w.scanDelivery(result, &delivery.WebhookDelivery, &delivery.URL, &delivery.Secret); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordDeliverySuccess marks the delivery as delivered and resets the failures of its subscription
+func (w *WebhookRepositoryImpl) RecordDeliverySuccess(attempt *model.WebhookAttempt) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		"UPDATE webhook_deliveries SET status = ?, attempts = ?, last_status_code = ?, last_error = NULL, delivered_at = ? WHERE id = ?",
+		model.DeliveryDelivered,
+		attempt.Attempts,
+		attempt.StatusCode,
+		formatEventTime(attempt.At),
+		attempt.DeliveryID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = ?", attempt.SubscriptionID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordDeliveryFailure keeps the delivery pending until its next attempt, or marks it as failed when there is
+// none, and disables the subscription when it reaches disableAfter consecutive failures. It returns true when the
+// subscription was disabled
+func (w *WebhookRepositoryImpl) RecordDeliveryFailure(attempt *model.WebhookAttempt, disableAfter int) (bool, error) {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	status := model.DeliveryFailed
+	nextAttemptAt := attempt.At
+	if attempt.NextAttemptAt != nil {
+		status = model.DeliveryPending
+		nextAttemptAt = *attempt.NextAttemptAt
+	}
+
+	_, err = tx.Exec(
+		"UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ? WHERE id = ?",
+		status,
+		attempt.Attempts,
+		formatEventTime(nextAttemptAt),
+		sql.NullInt64{Int64: int64(attempt.StatusCode), Valid: attempt.StatusCode != 0},
+		attempt.Error,
+		attempt.DeliveryID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	var active bool
+	err = tx.QueryRow(
+		`UPDATE webhook_subscriptions SET consecutive_failures = consecutive_failures + 1,
+		active = CASE WHEN consecutive_failures + 1 >= ? THEN 0 ELSE active END,
+		disabled_at = CASE WHEN active = 1 AND consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END
+		WHERE id = ? RETURNING active`,
+		disableAfter,
+		disableAfter,
+		formatEventTime(attempt.At),
+		attempt.SubscriptionID,
+	).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+
+	return !active, tx.Commit()
+}
+
+// ListDeliveries returns one page of the delivery log of the subscription, the newest first, and its total
+func (w *WebhookRepositoryImpl) ListDeliveries(subscriptionID int64, limit, offset int) ([]model.WebhookDelivery, int64, error) {
+	var total int64
+	if err := w.db.QueryRow("SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = ?", subscriptionID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	result, err := w.db.Query(
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE subscription_id = ? ORDER BY id DESC LIMIT ? OFFSET ?",
+		subscriptionID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer result.Close()
+
+	deliveries := make([]model.WebhookDelivery, 0, limit)
+	for result.Next() {
+		var delivery model.WebhookDelivery
+		if err := w.scanDelivery(result, &delivery); err != nil {
+			return nil, 0, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (w *WebhookRepositoryImpl) scanSubscription(result *sql.Rows) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	var eventTypes, createdAt string
+	var disabledAt sql.NullString
+
+	err := result.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&eventTypes,
+		&subscription.Secret,
+		&subscription.Active,
+		&subscription.ConsecutiveFailures,
+		&disabledAt,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(eventTypes), &subscription.EventTypes); err != nil {
+		return nil, err
+	}
+
+	if subscription.CreatedAt, err = time.Parse(eventTimeFormat, createdAt); err != nil {
+		return nil, err
+	}
+
+	if subscription.DisabledAt, err = parseEventTime(disabledAt); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// scanDelivery scans the delivery columns followed by the extra destinations informed
+func (w *WebhookRepositoryImpl) scanDelivery(result *sql.Rows, delivery *model.WebhookDelivery, extra ...any) error {
+	var payload, nextAttemptAt, createdAt string
+	var lastStatusCode sql.NullInt64
+	var lastError, deliveredAt sql.NullString
+
+	destinations := append([]any{
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&nextAttemptAt,
+		&lastStatusCode,
+		&lastError,
+		&createdAt,
+		&deliveredAt,
+	}, extra...)
+
+	err := result.Scan(destinations...)
+	if err != nil {
+		return err
+	}
+
+	if delivery.NextAttemptAt, err = time.Parse(eventTimeFormat, nextAttemptAt); err != nil {
+		return err
+	}
+
+	if delivery.CreatedAt, err = time.Parse(eventTimeFormat, createdAt); err != nil {
+		return err
+	}
+
+	if delivery.DeliveredAt, err = parseEventTime(deliveredAt); err != nil {
+		return err
+	}
+
+	delivery.Payload = []byte(payload)
+	delivery.LastStatusCode = int(lastStatusCode.Int64)
+	delivery.LastError = lastError.String
+	return nil
+}
+
+func parseEventTime(timestamp sql.NullString) (*time.Time, error) {
+	if !timestamp.Valid {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(eventTimeFormat, timestamp.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	webhookSubscriptionColumns = []string{"id", "url", "event_types", "secret", "active", "consecutive_failures", "disabled_at", "created_at"}
+	webhookDeliveryColumns     = []string{"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts", "next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at"}
+)
+
+func Test_WebhookRepository_Subscriptions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewWebhookRepository(slog.Default(), db)
+	createdAt := time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)
+	disabledAt := time.Date(2023, 11, 3, 12, 0, 0, 0, time.UTC)
+
+	t.Run("SaveSubscription with success", func(t *testing.T) {
+		// Given
+		mock.ExpectExec("INSERT INTO webhook_subscriptions \\(url, event_types, secret, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+			WithArgs("https://example.com/hook", `["transaction.created","transaction.deleted"]`, "0123456789abcdef", "2023-11-02T12:00:00.000000Z").
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		// When
+		subscription, err := repository.SaveSubscription(&model.WebhookSubscription{
+			URL:        "https://example.com/hook",
+			EventTypes: []model.EventType{model.EventTransactionCreated, model.EventTransactionDeleted},
+			Secret:     "0123456789abcdef",
+			CreatedAt:  createdAt,
+		})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), subscription.ID)
+		assert.True(t, subscription.Active)
+	})
+
+	t.Run("GetSubscription with success", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE id = \\?").
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows(webhookSubscriptionColumns).
+				AddRow(3, "https://example.com/hook", `["*"]`, "0123456789abcdef", false, 20, "2023-11-03T12:00:00.000000Z", "2023-11-02T12:00:00.000000Z"))
+
+		// When
+		subscription, err := repository.GetSubscription(3)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &model.WebhookSubscription{
+			ID:                  3,
+			URL:                 "https://example.com/hook",
+			EventTypes:          []model.EventType{model.AllEvents},
+			Secret:              "0123456789abcdef",
+			ConsecutiveFailures: 20,
+			DisabledAt:          &disabledAt,
+			CreatedAt:           createdAt,
+		}, subscription)
+	})
+
+	t.Run("GetSubscription not found", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE id = \\?").
+			WithArgs(int64(4)).
+			WillReturnRows(sqlmock.NewRows(webhookSubscriptionColumns))
+
+		// When
+		subscription, err := repository.GetSubscription(4)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, subscription)
+	})
+
+	t.Run("ListSubscriptions error on select query", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("SELECT " + subscriptionColumns + " FROM webhook_subscriptions ORDER BY id").
+			WillReturnError(errors.New("database is locked"))
+
+		// When
+		subscriptions, err := repository.ListSubscriptions()
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+		assert.Nil(t, subscriptions)
+	})
+
+	t.Run("DeleteSubscription removes its deliveries", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM webhook_deliveries WHERE subscription_id = \\?").WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec("DELETE FROM webhook_subscriptions WHERE id = \\?").WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// When
+		deleted, err := repository.DeleteSubscription(3)
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("DeleteSubscription not found", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM webhook_deliveries WHERE subscription_id = \\?").WithArgs(int64(4)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM webhook_subscriptions WHERE id = \\?").WithArgs(int64(4)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		// When
+		deleted, err := repository.DeleteSubscription(4)
+
+		// Then
+		assert.NoError(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("EnableSubscription resets the failures", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("UPDATE webhook_subscriptions SET active = 1, consecutive_failures = 0, disabled_at = NULL WHERE id = \\? RETURNING").
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows(webhookSubscriptionColumns).
+				AddRow(3, "https://example.com/hook", `["*"]`, "0123456789abcdef", true, 0, nil, "2023-11-02T12:00:00.000000Z"))
+
+		// When
+		subscription, err := repository.EnableSubscription(3)
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, subscription.Active)
+		assert.Nil(t, subscription.DisabledAt)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_WebhookRepository_Deliveries(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	repository := NewWebhookRepository(slog.Default(), db)
+	now := time.Date(2023, 11, 2, 12, 0, 0, 0, time.UTC)
+	nextAttemptAt := now.Add(time.Minute)
+
+	t.Run("EnqueueDeliveries to the subscriptions of the event type", func(t *testing.T) {
+		// Given
+		mock.ExpectExec("INSERT INTO webhook_deliveries .* SELECT id, \\?, \\?, \\?, \\?, \\? FROM webhook_subscriptions WHERE active = 1 AND EXISTS .* ON CONFLICT \\(subscription_id, event_id\\) DO NOTHING").
+			WithArgs(int64(7), model.EventTransactionCreated, `{"event_id":7}`, "2023-11-02T12:00:00.000000Z", "2023-11-02T12:00:00.000000Z", model.EventTransactionCreated, model.AllEvents).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		// When
+		enqueued, err := repository.EnqueueDeliveries(7, model.EventTransactionCreated, []byte(`{"event_id":7}`), now)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), enqueued)
+	})
+
+	t.Run("GetPendingDeliveries with the subscription URL and secret", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("SELECT d.id, .* FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id WHERE d.status = \\? AND d.next_attempt_at <= \\? AND s.active = 1 ORDER BY d.id LIMIT \\?").
+			WithArgs(model.DeliveryPending, "2023-11-02T12:00:00.000000Z", 10).
+			WillReturnRows(sqlmock.NewRows(append(webhookDeliveryColumns, "url", "secret")).
+				AddRow(9, 3, 7, "transaction.created", `{"event_id":7}`, "pending", 1, "2023-11-02T12:00:00.000000Z", 503, "webhook responded with status 503", "2023-11-02T11:59:00.000000Z", nil, "https://example.com/hook", "0123456789abcdef"))
+
+		// When
+		deliveries, err := repository.GetPendingDeliveries(now, 10)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []model.PendingWebhookDelivery{{
+			WebhookDelivery: model.WebhookDelivery{
+				ID:             9,
+				SubscriptionID: 3,
+				EventID:        7,
+				EventType:      model.EventTransactionCreated,
+				Payload:        []byte(`{"event_id":7}`),
+				Status:         model.DeliveryPending,
+				Attempts:       1,
+				NextAttemptAt:  now,
+				LastStatusCode: 503,
+				LastError:      "webhook responded with status 503",
+				CreatedAt:      now.Add(-time.Minute),
+			},
+			URL:    "https://example.com/hook",
+			Secret: "0123456789abcdef",
+		}}, deliveries)
+	})
+
+	t.Run("RecordDeliverySuccess resets the failures of the subscription", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE webhook_deliveries SET status = \\?, attempts = \\?, last_status_code = \\?, last_error = NULL, delivered_at = \\? WHERE id = \\?").
+			WithArgs(model.DeliveryDelivered, 2, 200, "2023-11-02T12:00:00.000000Z", int64(9)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = \\?").
+			WithArgs(int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// When
+		err := repository.RecordDeliverySuccess(&model.WebhookAttempt{DeliveryID: 9, SubscriptionID: 3, Attempts: 2, StatusCode: 200, At: now})
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("RecordDeliveryFailure keeps the delivery pending until the next attempt", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE webhook_deliveries SET status = \\?, attempts = \\?, next_attempt_at = \\?, last_status_code = \\?, last_error = \\? WHERE id = \\?").
+			WithArgs(model.DeliveryPending, 2, "2023-11-02T12:01:00.000000Z", int64(503), "webhook responded with status 503", int64(9)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("UPDATE webhook_subscriptions SET consecutive_failures = consecutive_failures \\+ 1, .* WHERE id = \\? RETURNING active").
+			WithArgs(20, 20, "2023-11-02T12:00:00.000000Z", int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(true))
+		mock.ExpectCommit()
+
+		// When
+		disabled, err := repository.RecordDeliveryFailure(&model.WebhookAttempt{
+			DeliveryID:     9,
+			SubscriptionID: 3,
+			Attempts:       2,
+			StatusCode:     503,
+			Error:          "webhook responded with status 503",
+			At:             now,
+			NextAttemptAt:  &nextAttemptAt,
+		}, 20)
+
+		// Then
+		assert.NoError(t, err)
+		assert.False(t, disabled)
+	})
+
+	t.Run("RecordDeliveryFailure without next attempt fails the delivery and disables the subscription", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE webhook_deliveries SET status = \\?, attempts = \\?, next_attempt_at = \\?, last_status_code = \\?, last_error = \\? WHERE id = \\?").
+			WithArgs(model.DeliveryFailed, 8, "2023-11-02T12:00:00.000000Z", nil, "connection refused", int64(9)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("UPDATE webhook_subscriptions SET consecutive_failures = consecutive_failures \\+ 1, .* WHERE id = \\? RETURNING active").
+			WithArgs(20, 20, "2023-11-02T12:00:00.000000Z", int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(false))
+		mock.ExpectCommit()
+
+		// When
+		disabled, err := repository.RecordDeliveryFailure(&model.WebhookAttempt{DeliveryID: 9, SubscriptionID: 3, Attempts: 8, Error: "connection refused", At: now}, 20)
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, disabled)
+	})
+
+	t.Run("RecordDeliveryFailure error rolls back", func(t *testing.T) {
+		// Given
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE webhook_deliveries SET").WillReturnError(errors.New("database is locked"))
+		mock.ExpectRollback()
+
+		// When
+		disabled, err := repository.RecordDeliveryFailure(&model.WebhookAttempt{DeliveryID: 9, SubscriptionID: 3, Attempts: 8, At: now}, 20)
+
+		// Then
+		assert.EqualError(t, err, "database is locked")
+		assert.False(t, disabled)
+	})
+
+	t.Run("ListDeliveries newest first with the total", func(t *testing.T) {
+		// Given
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM webhook_deliveries WHERE subscription_id = \\?").
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+		mock.ExpectQuery("SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE subscription_id = \\? ORDER BY id DESC LIMIT \\? OFFSET \\?").
+			WithArgs(int64(3), 1, 10).
+			WillReturnRows(sqlmock.NewRows(webhookDeliveryColumns).
+				AddRow(2, 3, 5, "transaction.deleted", `{"event_id":5}`, "delivered", 1, "2023-11-02T12:00:00.000000Z", 204, nil, "2023-11-02T12:00:00.000000Z", "2023-11-02T12:00:00.000000Z"))
+
+		// When
+		deliveries, total, err := repository.ListDeliveries(3, 1, 10)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), total)
+		assert.Equal(t, []model.WebhookDelivery{{
+			ID:             2,
+			SubscriptionID: 3,
+			EventID:        5,
+			EventType:      model.EventTransactionDeleted,
+			Payload:        []byte(`{"event_id":5}`),
+			Status:         model.DeliveryDelivered,
+			Attempts:       1,
+			NextAttemptAt:  now,
+			LastStatusCode: 204,
+			CreatedAt:      now,
+			DeliveredAt:    &now,
+		}}, deliveries)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
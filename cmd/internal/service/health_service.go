@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+type HealthService interface {
+	Check(ctx context.Context) *presentation.HealthDTO
+}
+
+//go:generate mockgen -source=./health_service.go -destination=./mocks/health_service_mock.go
+
+type healthCheck struct {
+	name    string
+	checker repository.HealthChecker
+	// the server is not ready while a critical dependency is down
+	critical bool
+}
+
+type HealthServiceImpl struct {
+	checks  []healthCheck
+	timeout time.Duration
+	log     *slog.Logger
+	now     func() time.Time
+}
+
+func NewHealthService(timeout time.Duration, log *slog.Logger) *HealthServiceImpl {
+	return &HealthServiceImpl{
+		timeout: timeout,
+		log:     log,
+		now:     time.Now,
+	}
+}
+
+// Register adds the health check of a dependency, it must be called before the server starts
+func (h *HealthServiceImpl) Register(name string, checker repository.HealthChecker, critical bool) {
+	h.checks = append(h.checks, healthCheck{name: name, checker: checker, critical: critical})
+}
+
+// Check runs every registered check at the same time, each one with its own timeout
+func (h *HealthServiceImpl) Check(ctx context.Context) *presentation.HealthDTO {
+	components := make([]presentation.HealthComponentDTO, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = h.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	byName := make(map[string]presentation.HealthComponentDTO, len(h.checks))
+	for i, check := range h.checks {
+		byName[check.name] = components[i]
+	}
+
+	return presentation.NewHealthDTO(byName)
+}
+
+func (h *HealthServiceImpl) run(ctx context.Context, check healthCheck) presentation.HealthComponentDTO {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := h.now()
+	err := check.checker.Check(ctx)
+	latency := h.now().Sub(start)
+
+	if err != nil {
+		h.log.Warn("Health check failed", "component", check.name, "critical", check.critical, "error", err)
+	}
+
+	component := presentation.NewHealthComponentDTO(check.critical, latency, err)
+	if reporter, ok := check.checker.(repository.CircuitBreakerReporter); ok {
+		component.CircuitBreaker = reporter.CircuitBreakerState()
+	}
+
+	return component
+}
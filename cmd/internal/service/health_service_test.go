@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HealthService_Check(t *testing.T) {
+	t.Parallel()
+
+	newHealthService := func(t *testing.T) (*HealthServiceImpl, *mock_repository.MockHealthChecker, *mock_repository.MockHealthChecker) {
+		mockController := gomock.NewController(t)
+		database := mock_repository.NewMockHealthChecker(mockController)
+		treasury := mock_repository.NewMockHealthChecker(mockController)
+
+		healthService := NewHealthService(50*time.Millisecond, slog.Default())
+		healthService.Register("database", database, true)
+		healthService.Register("treasury", treasury, false)
+		return healthService, database, treasury
+	}
+
+	t.Run("Check up with every component up and their latency", func(t *testing.T) {
+		// given
+		healthService, database, treasury := newHealthService(t)
+		start := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+		healthService.now = func() time.Time { return start }
+		database.EXPECT().Check(gomock.Any()).Return(nil)
+		treasury.EXPECT().Check(gomock.Any()).Return(nil)
+
+		// when
+		health := healthService.Check(context.Background())
+
+		// then
+		assert.Equal(t, &presentation.HealthDTO{
+			Status: "up",
+			Components: map[string]presentation.HealthComponentDTO{
+				"database": {Status: "up", Critical: true},
+				"treasury": {Status: "up"},
+			},
+		}, health)
+	})
+
+	t.Run("Check degraded with a non critical component down", func(t *testing.T) {
+		// given
+		healthService, database, treasury := newHealthService(t)
+		database.EXPECT().Check(gomock.Any()).Return(nil)
+		treasury.EXPECT().Check(gomock.Any()).Return(errors.New("treasury api call error [status_code:503]"))
+
+		// when
+		health := healthService.Check(context.Background())
+
+		// then
+		assert.Equal(t, "degraded", health.Status)
+		assert.Equal(t, "treasury api call error [status_code:503]", health.Components["treasury"].Error)
+	})
+
+	t.Run("Check down when a critical component does not answer before the timeout", func(t *testing.T) {
+		// given
+		healthService, database, treasury := newHealthService(t)
+		database.EXPECT().Check(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		treasury.EXPECT().Check(gomock.Any()).Return(nil)
+
+		// when
+		health := healthService.Check(context.Background())
+
+		// then
+		assert.Equal(t, "down", health.Status)
+		assert.Equal(t, "down", health.Components["database"].Status)
+		assert.Equal(t, "context deadline exceeded", health.Components["database"].Error)
+		assert.GreaterOrEqual(t, health.Components["database"].LatencyMs, float64(50))
+		assert.Equal(t, "up", health.Components["treasury"].Status)
+	})
+
+	t.Run("Check with the state of the circuit breaker", func(t *testing.T) {
+		// given
+		mockController := gomock.NewController(t)
+		treasury := mock_repository.NewMockCircuitBreakerReporter(mockController)
+		healthService := NewHealthService(50*time.Millisecond, slog.Default())
+		healthService.Register("treasury", treasury, false)
+		treasury.EXPECT().Check(gomock.Any()).Return(errors.New("circuit breaker is open"))
+		treasury.EXPECT().CircuitBreakerState().Return("open")
+
+		// when
+		health := healthService.Check(context.Background())
+
+		// then
+		assert.Equal(t, "degraded", health.Status)
+		assert.Equal(t, "open", health.Components["treasury"].CircuitBreaker)
+		assert.Equal(t, "circuit breaker is open", health.Components["treasury"].Error)
+	})
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+)
+
+const idempotencyKeyMaxLength = 255
+
+type IdempotencyService interface {
+	Start(ctx context.Context, key string, fingerprint string) (*model.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, statusCode int, responseBody []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+//go:generate mockgen -source=./idempotency_service.go -destination=./mocks/idempotency_service_mock.go
+
+type IdempotencyServiceImpl struct {
+	log        *slog.Logger
+	repository repository.IdempotencyRepository
+	// inFlightTimeout is the write timeout of the database, the key of a request that never completed, e.g. the
+	// server stopped, is released once its write can no longer commit
+	inFlightTimeout time.Duration
+	now             func() time.Time
+}
+
+func NewIdempotencyService(log *slog.Logger, repository repository.IdempotencyRepository, inFlightTimeout time.Duration) *IdempotencyServiceImpl {
+	return &IdempotencyServiceImpl{
+		log:             log,
+		repository:      repository,
+		inFlightTimeout: inFlightTimeout,
+		now:             time.Now,
+	}
+}
+
+// Start reserves the key for the request, it returns nil when the request must be processed or the completed
+// record whose response must be replayed
+func (i *IdempotencyServiceImpl) Start(ctx context.Context, key string, fingerprint string) (*model.IdempotencyRecord, error) {
+	if key == "" || len(key) > idempotencyKeyMaxLength {
+		return nil, model.NewValidationError(fmt.Sprintf("invalid idempotency key, it must be between 1 and %d characters", idempotencyKeyMaxLength))
+	}
+
+	now := i.now()
+	stored, err := i.repository.Reserve(ctx, &model.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   i.inFlightExpiry(ctx, now),
+	}, now)
+	if err != nil {
+		return nil, fmt.Errorf("error reserving idempotency key: %w", err)
+	}
+
+	if stored == nil {
+		return nil, nil
+	}
+
+	if stored.Fingerprint != "" && stored.Fingerprint != fingerprint {
+		return nil, model.NewIdempotencyKeyReuseError("idempotency key already used with a different request body")
+	}
+
+	if !stored.Completed {
+		return nil, model.NewConflictError("a request with this idempotency key is still in progress")
+	}
+
+	util.Logger(ctx, i.log).Debug("Replaying idempotent response", "idempotency_key", key)
+	return stored, nil
+}
+
+// Complete stores the response of the request to be replayed to the retries with the same key
+func (i *IdempotencyServiceImpl) Complete(ctx context.Context, key string, statusCode int, responseBody []byte) error {
+	if err := i.repository.Complete(ctx, key, statusCode, responseBody, i.now().Add(model.IdempotencyKeyTTL)); err != nil {
+		return fmt.Errorf("error completing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Release removes the key of a failed request so it can be retried
+func (i *IdempotencyServiceImpl) Release(ctx context.Context, key string) error {
+	if err := i.repository.Delete(ctx, key); err != nil {
+		return fmt.Errorf("error releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// inFlightExpiry keeps the key reserved until the write of the request times out, or the request does when earlier
+func (i *IdempotencyServiceImpl) inFlightExpiry(ctx context.Context, now time.Time) time.Time {
+	expiresAt := now.Add(i.inFlightTimeout)
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(expiresAt) {
+		return deadline
+	}
+
+	return expiresAt
+}
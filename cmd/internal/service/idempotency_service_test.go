@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IdempotencyService_Start(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	reservation := &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fingerprint", ExpiresAt: now.Add(time.Minute)}
+	completed := &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fingerprint", Completed: true, StatusCode: 200, ResponseBody: []byte(`{"id":1}`)}
+
+	tests := []struct {
+		name           string
+		key            string
+		stored         *model.IdempotencyRecord
+		repositoryErr  error
+		expectReserve  bool
+		expectedRecord *model.IdempotencyRecord
+		expectedError  error
+	}{
+		{name: "Reserve a new key", key: "key-1", expectReserve: true},
+		{name: "Replay a completed request", key: "key-1", stored: completed, expectReserve: true, expectedRecord: completed},
+		{
+			name:          "Request with the same key still in progress",
+			key:           "key-1",
+			stored:        &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fingerprint"},
+			expectReserve: true,
+			expectedError: model.NewConflictError("a request with this idempotency key is still in progress"),
+		},
+		{
+			name:          "Key reused with a different body",
+			key:           "key-1",
+			stored:        &model.IdempotencyRecord{Key: "key-1", Fingerprint: "other", Completed: true},
+			expectReserve: true,
+			expectedError: model.NewIdempotencyKeyReuseError("idempotency key already used with a different request body"),
+		},
+		{
+			name:          "Key too long",
+			key:           strings.Repeat("a", 256),
+			expectedError: model.NewValidationError("invalid idempotency key, it must be between 1 and 255 characters"),
+		},
+		{
+			name:          "Repository error",
+			key:           "key-1",
+			repositoryErr: errors.New("database is locked"),
+			expectReserve: true,
+			expectedError: errors.New("error reserving idempotency key: database is locked"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			mockController := gomock.NewController(t)
+			mockRepository := mock_repository.NewMockIdempotencyRepository(mockController)
+			idempotencyService := NewIdempotencyService(slog.Default(), mockRepository, time.Minute)
+			idempotencyService.now = func() time.Time { return now }
+
+			if tt.expectReserve {
+				reservation.Key = tt.key
+				mockRepository.EXPECT().Reserve(gomock.Any(), reservation, now).Return(tt.stored, tt.repositoryErr)
+			}
+
+			// when
+			record, err := idempotencyService.Start(context.Background(), tt.key, "fingerprint")
+
+			// then
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Equal(t, errors.Is(tt.expectedError, model.ErrConflict), errors.Is(err, model.ErrConflict))
+				assert.Equal(t, errors.Is(tt.expectedError, model.ErrIdempotencyKeyReuse), errors.Is(err, model.ErrIdempotencyKeyReuse))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRecord, record)
+		})
+	}
+}
+
+func Test_IdempotencyService_Start_RequestDeadline(t *testing.T) {
+	t.Parallel()
+	// given
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(10*time.Second))
+	defer cancel()
+	mockRepository := mock_repository.NewMockIdempotencyRepository(gomock.NewController(t))
+	idempotencyService := NewIdempotencyService(slog.Default(), mockRepository, time.Minute)
+	idempotencyService.now = func() time.Time { return now }
+	mockRepository.EXPECT().Reserve(ctx, &model.IdempotencyRecord{Key: "key-1", Fingerprint: "fingerprint", ExpiresAt: now.Add(10 * time.Second)}, now).Return(nil, nil)
+
+	// when
+	record, err := idempotencyService.Start(ctx, "key-1", "fingerprint")
+
+	// then
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func Test_IdempotencyService_Complete(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockIdempotencyRepository(mockController)
+	idempotencyService := NewIdempotencyService(slog.Default(), mockRepository, time.Minute)
+	idempotencyService.now = func() time.Time { return now }
+
+	t.Run("Complete stores the response until the key expires", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().Complete(gomock.Any(), "key-1", 200, []byte(`{"id":1}`), now.Add(model.IdempotencyKeyTTL)).Return(nil)
+
+		// when
+		err := idempotencyService.Complete(context.Background(), "key-1", 200, []byte(`{"id":1}`))
+
+		// then
+		assert.NoError(t, err)
+	})
+
+	t.Run("Release deletes the key", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().Delete(gomock.Any(), "key-1").Return(errors.New("database is locked"))
+
+		// when
+		err := idempotencyService.Release(context.Background(), "key-1")
+
+		// then
+		assert.EqualError(t, err, "error releasing idempotency key: database is locked")
+	})
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./outbox_dispatcher.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxDispatcher is a mock of OutboxDispatcher interface.
+type MockOutboxDispatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxDispatcherMockRecorder
+}
+
+// MockOutboxDispatcherMockRecorder is the mock recorder for MockOutboxDispatcher.
+type MockOutboxDispatcherMockRecorder struct {
+	mock *MockOutboxDispatcher
+}
+
+// NewMockOutboxDispatcher creates a new mock instance.
+func NewMockOutboxDispatcher(ctrl *gomock.Controller) *MockOutboxDispatcher {
+	mock := &MockOutboxDispatcher{ctrl: ctrl}
+	mock.recorder = &MockOutboxDispatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxDispatcher) EXPECT() *MockOutboxDispatcherMockRecorder {
+	return m.recorder
+}
+
+// Dispatch mocks base method.
+func (m *MockOutboxDispatcher) Dispatch(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockOutboxDispatcherMockRecorder) Dispatch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockOutboxDispatcher)(nil).Dispatch), ctx)
+}
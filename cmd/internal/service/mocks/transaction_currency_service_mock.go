@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	model "github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	presentation "github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
 )
 
@@ -35,12 +36,42 @@ func (m *MockTransactionCurrencyService) EXPECT() *MockTransactionCurrencyServic
 	return m.recorder
 }
 
+// ConvertTransactionsCurrency mocks base method.
+func (m *MockTransactionCurrencyService) ConvertTransactionsCurrency(ctx context.Context, country string, transactionIDs []int64, filter *model.TransactionFilter, emit func(presentation.TransactionCurrencyResultDTO)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertTransactionsCurrency", ctx, country, transactionIDs, filter, emit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConvertTransactionsCurrency indicates an expected call of ConvertTransactionsCurrency.
+func (mr *MockTransactionCurrencyServiceMockRecorder) ConvertTransactionsCurrency(ctx, country, transactionIDs, filter, emit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertTransactionsCurrency", reflect.TypeOf((*MockTransactionCurrencyService)(nil).ConvertTransactionsCurrency), ctx, country, transactionIDs, filter, emit)
+}
+
+// GetTransactionCurrenciesConverted mocks base method.
+func (m *MockTransactionCurrencyService) GetTransactionCurrenciesConverted(ctx context.Context, transactionID int64, countries []string) ([]presentation.TransactionCurrencyResultDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionCurrenciesConverted", ctx, transactionID, countries)
+	ret0, _ := ret[0].([]presentation.TransactionCurrencyResultDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionCurrenciesConverted indicates an expected call of GetTransactionCurrenciesConverted.
+func (mr *MockTransactionCurrencyServiceMockRecorder) GetTransactionCurrenciesConverted(ctx, transactionID, countries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionCurrenciesConverted", reflect.TypeOf((*MockTransactionCurrencyService)(nil).GetTransactionCurrenciesConverted), ctx, transactionID, countries)
+}
+
 // GetTransactionCurrencyConverted mocks base method.
-func (m *MockTransactionCurrencyService) GetTransactionCurrencyConverted(ctx context.Context, transactionID int64, country string) *presentation.TransactionCurrencyDTO {
+func (m *MockTransactionCurrencyService) GetTransactionCurrencyConverted(ctx context.Context, transactionID int64, country string) (*presentation.TransactionCurrencyDTO, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTransactionCurrencyConverted", ctx, transactionID, country)
 	ret0, _ := ret[0].(*presentation.TransactionCurrencyDTO)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetTransactionCurrencyConverted indicates an expected call of GetTransactionCurrencyConverted.
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./transaction_purge_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTransactionPurgeService is a mock of TransactionPurgeService interface.
+type MockTransactionPurgeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionPurgeServiceMockRecorder
+}
+
+// MockTransactionPurgeServiceMockRecorder is the mock recorder for MockTransactionPurgeService.
+type MockTransactionPurgeServiceMockRecorder struct {
+	mock *MockTransactionPurgeService
+}
+
+// NewMockTransactionPurgeService creates a new mock instance.
+func NewMockTransactionPurgeService(ctrl *gomock.Controller) *MockTransactionPurgeService {
+	mock := &MockTransactionPurgeService{ctrl: ctrl}
+	mock.recorder = &MockTransactionPurgeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionPurgeService) EXPECT() *MockTransactionPurgeServiceMockRecorder {
+	return m.recorder
+}
+
+// Purge mocks base method.
+func (m *MockTransactionPurgeService) Purge(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTransactionPurgeServiceMockRecorder) Purge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTransactionPurgeService)(nil).Purge), ctx)
+}
@@ -5,7 +5,9 @@
 package mock_service
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/pablorodrigo52/transaction-api/cmd/internal/model"
@@ -36,55 +38,120 @@ func (m *MockTransactionService) EXPECT() *MockTransactionServiceMockRecorder {
 }
 
 // DeleteTransactionByID mocks base method.
-func (m *MockTransactionService) DeleteTransactionByID(transactionID int64) {
+func (m *MockTransactionService) DeleteTransactionByID(ctx context.Context, transactionID, expectedVersion int64, audit model.Audit) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "DeleteTransactionByID", transactionID)
+	ret := m.ctrl.Call(m, "DeleteTransactionByID", ctx, transactionID, expectedVersion, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // DeleteTransactionByID indicates an expected call of DeleteTransactionByID.
-func (mr *MockTransactionServiceMockRecorder) DeleteTransactionByID(transactionID interface{}) *gomock.Call {
+func (mr *MockTransactionServiceMockRecorder) DeleteTransactionByID(ctx, transactionID, expectedVersion, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).DeleteTransactionByID), transactionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).DeleteTransactionByID), ctx, transactionID, expectedVersion, audit)
 }
 
 // GetTransactionByID mocks base method.
-func (m *MockTransactionService) GetTransactionByID(transactionID int64) *presentation.TransactionDTO {
+func (m *MockTransactionService) GetTransactionByID(ctx context.Context, transactionID int64) (*presentation.TransactionDTO, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionByID", transactionID)
+	ret := m.ctrl.Call(m, "GetTransactionByID", ctx, transactionID)
 	ret0, _ := ret[0].(*presentation.TransactionDTO)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetTransactionByID indicates an expected call of GetTransactionByID.
-func (mr *MockTransactionServiceMockRecorder) GetTransactionByID(transactionID interface{}) *gomock.Call {
+func (mr *MockTransactionServiceMockRecorder) GetTransactionByID(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).GetTransactionByID), ctx, transactionID)
+}
+
+// GetTransactionHistory mocks base method.
+func (m *MockTransactionService) GetTransactionHistory(ctx context.Context, transactionID int64, asOf *time.Time) (*presentation.TransactionHistoryDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionHistory", ctx, transactionID, asOf)
+	ret0, _ := ret[0].(*presentation.TransactionHistoryDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionHistory indicates an expected call of GetTransactionHistory.
+func (mr *MockTransactionServiceMockRecorder) GetTransactionHistory(ctx, transactionID, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionHistory", reflect.TypeOf((*MockTransactionService)(nil).GetTransactionHistory), ctx, transactionID, asOf)
+}
+
+// ListTransactions mocks base method.
+func (m *MockTransactionService) ListTransactions(ctx context.Context, filter *model.TransactionFilter) (*presentation.TransactionPageDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, filter)
+	ret0, _ := ret[0].(*presentation.TransactionPageDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockTransactionServiceMockRecorder) ListTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransactionService)(nil).ListTransactions), ctx, filter)
+}
+
+// PatchTransactionByID mocks base method.
+func (m *MockTransactionService) PatchTransactionByID(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTransactionByID", ctx, transactionID, patch, expectedVersion, audit)
+	ret0, _ := ret[0].(*presentation.TransactionDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTransactionByID indicates an expected call of PatchTransactionByID.
+func (mr *MockTransactionServiceMockRecorder) PatchTransactionByID(ctx, transactionID, patch, expectedVersion, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).GetTransactionByID), transactionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).PatchTransactionByID), ctx, transactionID, patch, expectedVersion, audit)
+}
+
+// RestoreTransactionByID mocks base method.
+func (m *MockTransactionService) RestoreTransactionByID(ctx context.Context, transactionID, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTransactionByID", ctx, transactionID, expectedVersion, audit)
+	ret0, _ := ret[0].(*presentation.TransactionDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTransactionByID indicates an expected call of RestoreTransactionByID.
+func (mr *MockTransactionServiceMockRecorder) RestoreTransactionByID(ctx, transactionID, expectedVersion, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).RestoreTransactionByID), ctx, transactionID, expectedVersion, audit)
 }
 
 // SaveTransaction mocks base method.
-func (m *MockTransactionService) SaveTransaction(transaction *model.Transaction) *presentation.TransactionDTO {
+func (m *MockTransactionService) SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*presentation.TransactionDTO, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveTransaction", transaction)
+	ret := m.ctrl.Call(m, "SaveTransaction", ctx, transaction, audit)
 	ret0, _ := ret[0].(*presentation.TransactionDTO)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // SaveTransaction indicates an expected call of SaveTransaction.
-func (mr *MockTransactionServiceMockRecorder) SaveTransaction(transaction interface{}) *gomock.Call {
+func (mr *MockTransactionServiceMockRecorder) SaveTransaction(ctx, transaction, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTransaction", reflect.TypeOf((*MockTransactionService)(nil).SaveTransaction), transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTransaction", reflect.TypeOf((*MockTransactionService)(nil).SaveTransaction), ctx, transaction, audit)
 }
 
 // UpdateTransactionByID mocks base method.
-func (m *MockTransactionService) UpdateTransactionByID(transactionID int64, transaction *model.Transaction) *presentation.TransactionDTO {
+func (m *MockTransactionService) UpdateTransactionByID(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionByID", transactionID, transaction)
+	ret := m.ctrl.Call(m, "UpdateTransactionByID", ctx, transactionID, transaction, expectedVersion, audit)
 	ret0, _ := ret[0].(*presentation.TransactionDTO)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateTransactionByID indicates an expected call of UpdateTransactionByID.
-func (mr *MockTransactionServiceMockRecorder) UpdateTransactionByID(transactionID, transaction interface{}) *gomock.Call {
+func (mr *MockTransactionServiceMockRecorder) UpdateTransactionByID(ctx, transactionID, transaction, expectedVersion, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).UpdateTransactionByID), transactionID, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionByID", reflect.TypeOf((*MockTransactionService)(nil).UpdateTransactionByID), ctx, transactionID, transaction, expectedVersion, audit)
 }
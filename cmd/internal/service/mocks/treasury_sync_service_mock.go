@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./treasury_sync_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTreasurySyncService is a mock of TreasurySyncService interface.
+type MockTreasurySyncService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTreasurySyncServiceMockRecorder
+}
+
+// MockTreasurySyncServiceMockRecorder is the mock recorder for MockTreasurySyncService.
+type MockTreasurySyncServiceMockRecorder struct {
+	mock *MockTreasurySyncService
+}
+
+// NewMockTreasurySyncService creates a new mock instance.
+func NewMockTreasurySyncService(ctrl *gomock.Controller) *MockTreasurySyncService {
+	mock := &MockTreasurySyncService{ctrl: ctrl}
+	mock.recorder = &MockTreasurySyncServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTreasurySyncService) EXPECT() *MockTreasurySyncServiceMockRecorder {
+	return m.recorder
+}
+
+// Sync mocks base method.
+func (m *MockTreasurySyncService) Sync(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockTreasurySyncServiceMockRecorder) Sync(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockTreasurySyncService)(nil).Sync), ctx)
+}
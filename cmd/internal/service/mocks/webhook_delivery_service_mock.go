@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./webhook_delivery_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookDeliveryService is a mock of WebhookDeliveryService interface.
+type MockWebhookDeliveryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryServiceMockRecorder
+}
+
+// MockWebhookDeliveryServiceMockRecorder is the mock recorder for MockWebhookDeliveryService.
+type MockWebhookDeliveryServiceMockRecorder struct {
+	mock *MockWebhookDeliveryService
+}
+
+// NewMockWebhookDeliveryService creates a new mock instance.
+func NewMockWebhookDeliveryService(ctrl *gomock.Controller) *MockWebhookDeliveryService {
+	mock := &MockWebhookDeliveryService{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveryService) EXPECT() *MockWebhookDeliveryServiceMockRecorder {
+	return m.recorder
+}
+
+// Deliver mocks base method.
+func (m *MockWebhookDeliveryService) Deliver(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliver", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deliver indicates an expected call of Deliver.
+func (mr *MockWebhookDeliveryServiceMockRecorder) Deliver(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliver", reflect.TypeOf((*MockWebhookDeliveryService)(nil).Deliver), ctx)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+const (
+	outboxBatchSize = 100
+	// the retries of an event wait 1s, 2s, 4s... up to outboxMaxBackoff
+	outboxBaseBackoff = time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+type OutboxDispatcher interface {
+	Dispatch(ctx context.Context) (int, error)
+}
+
+//go:generate mockgen -source=./outbox_dispatcher.go -destination=./mocks/outbox_dispatcher_mock.go
+
+type OutboxDispatcherImpl struct {
+	repository  repository.OutboxRepository
+	publisher   repository.EventPublisher
+	interval    time.Duration
+	maxAttempts int
+	log         *slog.Logger
+	now         func() time.Time
+}
+
+func NewOutboxDispatcher(
+	repository repository.OutboxRepository,
+	publisher repository.EventPublisher,
+	interval time.Duration,
+	maxAttempts int,
+	log *slog.Logger) *OutboxDispatcherImpl {
+
+	return &OutboxDispatcherImpl{
+		repository:  repository,
+		publisher:   publisher,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		log:         log,
+		now:         time.Now,
+	}
+}
+
+// Dispatch publishes the pending events in the order they happened and returns how many were delivered. An event
+// is marked as delivered only after it is published, so it can be delivered more than once but it is never lost
+func (o *OutboxDispatcherImpl) Dispatch(ctx context.Context) (int, error) {
+	events, err := o.repository.GetPendingEvents(o.now(), outboxBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("error getting pending events: %w", err)
+	}
+
+	delivered := 0
+	for _, event := range events {
+		if ctx.Err() != nil {
+			break
+		}
+
+		published, err := o.deliver(ctx, &event)
+		if err != nil {
+			return delivered, fmt.Errorf("error updating event %d: %w", event.ID, err)
+		}
+
+		if published {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// deliver publishes the event and records the result, a failed event is retried with backoff until it reaches
+// the max attempts and goes to the dead letter
+func (o *OutboxDispatcherImpl) deliver(ctx context.Context, event *model.OutboxEvent) (bool, error) {
+	body, err := json.Marshal(presentation.NewEventDTO(event))
+	if err != nil {
+		return false, err
+	}
+
+	publishErr := o.publisher.Publish(ctx, &model.EventMessage{ID: event.ID, Type: event.Type, Body: body})
+	if publishErr == nil {
+		return true, o.repository.MarkDelivered(event.ID, o.now())
+	}
+
+	attempts := event.Attempts + 1
+	if attempts >= o.maxAttempts {
+		o.log.Error("Event moved to the dead letter", "event_id", event.ID, "event_type", event.Type, "attempts", attempts, "error", publishErr)
+		return false, o.repository.MarkDead(event.ID, attempts, publishErr.Error())
+	}
+
+	nextAttemptAt := o.now().Add(retryBackoff(attempts, outboxBaseBackoff, outboxMaxBackoff))
+	o.log.Warn("Error publishing event, it will be retried", "event_id", event.ID, "event_type", event.Type, "attempts", attempts, "next_attempt_at", nextAttemptAt, "error", publishErr)
+	return false, o.repository.MarkFailed(event.ID, attempts, nextAttemptAt, publishErr.Error())
+}
+
+// Start dispatches the pending events right away and then once per interval until the context is done
+func (o *OutboxDispatcherImpl) Start(ctx context.Context) {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		if delivered, err := o.Dispatch(ctx); err != nil {
+			o.log.Error("error dispatching events", "error", err)
+		} else if delivered > 0 {
+			o.log.Debug("Events dispatched", "delivered", delivered)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// retryBackoff doubles the wait from base after each failed attempt up to limit
+func retryBackoff(attempts int, base, limit time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && backoff < limit; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, limit)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OutboxDispatcher_Dispatch(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockOutboxRepository(mockController)
+	mockPublisher := mock_repository.NewMockEventPublisher(mockController)
+
+	dispatcher := NewOutboxDispatcher(mockRepository, mockPublisher, time.Second, 3, slog.Default())
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+
+	transaction := &model.Transaction{ID: 1, Description: "mock", TransactionDate: now, PurchaseAmount: 100, Version: 1}
+	created := model.OutboxEvent{ID: 1, Type: model.EventTransactionCreated, TransactionID: 1, Transaction: transaction, OccurredAt: now}
+
+	t.Run("Dispatch publishes the pending events and marks them as delivered", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetPendingEvents(now, outboxBatchSize).Return([]model.OutboxEvent{created}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *model.EventMessage) error {
+			assert.Equal(t, int64(1), message.ID)
+			assert.Equal(t, model.EventTransactionCreated, message.Type)
+			assert.JSONEq(t, `{"event_id":1,"type":"transaction.created","occurred_at":"`+now.UTC().Format(time.RFC3339Nano)+`","data":{"transaction_id":1,"description":"mock","transaction_date":"`+now.Local().Format(time.RFC3339)+`","purchase_amount":1.00,"version":1}}`, string(message.Body))
+			return nil
+		})
+		mockRepository.EXPECT().MarkDelivered(int64(1), now).Return(nil)
+
+		// when
+		delivered, err := dispatcher.Dispatch(context.Background())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+	})
+
+	t.Run("Dispatch retries a failed event with backoff", func(t *testing.T) {
+		// given
+		failed := created
+		failed.Attempts = 1
+		mockRepository.EXPECT().GetPendingEvents(now, outboxBatchSize).Return([]model.OutboxEvent{failed}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+		mockRepository.EXPECT().MarkFailed(int64(1), 2, now.Add(2*time.Second), "connection refused").Return(nil)
+
+		// when
+		delivered, err := dispatcher.Dispatch(context.Background())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Dispatch moves the event to the dead letter after the max attempts", func(t *testing.T) {
+		// given
+		failed := created
+		failed.Attempts = 2
+		mockRepository.EXPECT().GetPendingEvents(now, outboxBatchSize).Return([]model.OutboxEvent{failed}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("webhook responded with status 500"))
+		mockRepository.EXPECT().MarkDead(int64(1), 3, "webhook responded with status 500").Return(nil)
+
+		// when
+		delivered, err := dispatcher.Dispatch(context.Background())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Dispatch error marking the event stops the batch", func(t *testing.T) {
+		// given
+		second := created
+		second.ID = 2
+		mockRepository.EXPECT().GetPendingEvents(now, outboxBatchSize).Return([]model.OutboxEvent{created, second}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+		mockRepository.EXPECT().MarkDelivered(int64(1), now).Return(errors.New("database is locked"))
+
+		// when
+		delivered, err := dispatcher.Dispatch(context.Background())
+
+		// then
+		assert.EqualError(t, err, "error updating event 1: database is locked")
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Dispatch error on repository", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetPendingEvents(now, outboxBatchSize).Return(nil, errors.New("database is locked"))
+
+		// when
+		delivered, err := dispatcher.Dispatch(context.Background())
+
+		// then
+		assert.EqualError(t, err, "error getting pending events: database is locked")
+		assert.Equal(t, 0, delivered)
+	})
+}
+
+func Test_RetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Second},
+		{attempts: 2, expected: 2 * time.Second},
+		{attempts: 5, expected: 16 * time.Second},
+		{attempts: 13, expected: time.Hour},
+		{attempts: 100, expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, retryBackoff(tt.attempts, outboxBaseBackoff, outboxMaxBackoff), "attempts %d", tt.attempts)
+	}
+}
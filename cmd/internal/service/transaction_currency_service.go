@@ -2,103 +2,318 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
-	"net/http"
-	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	exchangeRateDateFormat = "2006-01-02"
+	// maxParallelConversions bounds the concurrent exchange rate lookups of a multi-currency conversion
+	maxParallelConversions = 4
+	tracerName             = "github.com/pablorodrigo52/transaction-api/cmd/internal/service"
 )
 
 type TransactionCurrencyService interface {
-	GetTransactionCurrencyConverted(ctx context.Context, transactionID int64, country string) *presentation.TransactionCurrencyDTO
+	GetTransactionCurrencyConverted(ctx context.Context, transactionID int64, country string) (*presentation.TransactionCurrencyDTO, error)
+	GetTransactionCurrenciesConverted(ctx context.Context, transactionID int64, countries []string) ([]presentation.TransactionCurrencyResultDTO, error)
+	ConvertTransactionsCurrency(ctx context.Context, country string, transactionIDs []int64, filter *model.TransactionFilter, emit func(presentation.TransactionCurrencyResultDTO)) error
 }
 
 //go:generate mockgen -source=./transaction_currency_service.go -destination=./mocks/transaction_currency_service_mock.go
 
 type TransactionCurrencyServiceImpl struct {
-	treasuryRepository    repository.TreasuryRepository
+	exchangeRateProvider  repository.ExchangeRateProvider
 	transactionRepository repository.TransactionRepository
-	log                   *slog.Logger
+	// rejected counts the conversions without a rate effective in the six months before the purchase
+	rejected prometheus.Counter
+	tracer   trace.Tracer
+	log      *slog.Logger
 }
 
-func NewTransactionCurrencyService(treasuryRepository repository.TreasuryRepository, transactionRepository repository.TransactionRepository, log *slog.Logger) *TransactionCurrencyServiceImpl {
+func NewTransactionCurrencyService(exchangeRateProvider repository.ExchangeRateProvider, transactionRepository repository.TransactionRepository, rejected prometheus.Counter, log *slog.Logger) *TransactionCurrencyServiceImpl {
 
 	return &TransactionCurrencyServiceImpl{
-		treasuryRepository:    treasuryRepository,
+		exchangeRateProvider:  exchangeRateProvider,
 		transactionRepository: transactionRepository,
+		rejected:              rejected,
+		tracer:                otel.Tracer(tracerName),
 		log:                   log,
 	}
 }
 
-func (s *TransactionCurrencyServiceImpl) GetTransactionCurrencyConverted(ctx context.Context, transactionID int64, country string) *presentation.TransactionCurrencyDTO {
+func (s *TransactionCurrencyServiceImpl) GetTransactionCurrencyConverted(ctx context.Context, transactionID int64, country string) (conversion *presentation.TransactionCurrencyDTO, err error) {
+	ctx, span := s.tracer.Start(ctx, "TransactionCurrencyService.GetTransactionCurrencyConverted",
+		trace.WithAttributes(attribute.Int64("transaction.id", transactionID), attribute.String("country", country)))
+	defer func() { util.EndSpan(span, err) }()
 
 	if transactionID <= 0 {
-		s.throwError(http.StatusBadRequest, "invalid transaction id")
+		return nil, model.NewValidationError("invalid transaction id")
 	}
 
 	if country == "" {
-		panic(presentation.NewApiError(http.StatusBadRequest, "invalid country name"))
+		return nil, model.NewValidationError("invalid country name")
 	}
 
-	// get transaction by id
-	trx, err := s.transactionRepository.GetTransaction(transactionID)
+	trx, err := s.getTransaction(ctx, transactionID)
 	if err != nil {
-		s.throwError(http.StatusFailedDependency, err.Error())
+		return nil, err
+	}
+
+	return s.convert(ctx, trx, country)
+}
+
+// GetTransactionCurrenciesConverted converts the transaction to the currency of each country concurrently,
+// a country that cannot be converted has its error in the result instead of failing the whole request
+func (s *TransactionCurrencyServiceImpl) GetTransactionCurrenciesConverted(ctx context.Context, transactionID int64, countries []string) (conversions []presentation.TransactionCurrencyResultDTO, err error) {
+	ctx, span := s.tracer.Start(ctx, "TransactionCurrencyService.GetTransactionCurrenciesConverted",
+		trace.WithAttributes(attribute.Int64("transaction.id", transactionID), attribute.StringSlice("countries", countries)))
+	defer func() { util.EndSpan(span, err) }()
+
+	if transactionID <= 0 {
+		return nil, model.NewValidationError("invalid transaction id")
+	}
+
+	if len(countries) == 0 {
+		return nil, model.NewValidationError("invalid country names")
+	}
+
+	trx, err := s.getTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]presentation.TransactionCurrencyResultDTO, len(countries))
+	semaphore := make(chan struct{}, maxParallelConversions)
+	var wg sync.WaitGroup
+
+	for i, country := range countries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			response, err := s.convert(ctx, trx, country)
+			results[i] = presentation.TransactionCurrencyResultDTO{
+				TransactionID: trx.ID,
+				Country:       country,
+				Conversion:    response,
+			}
+			if err != nil {
+				results[i].Error = presentation.NewApiErrorFromError(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+func (s *TransactionCurrencyServiceImpl) getTransaction(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	trx, err := s.transactionRepository.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting transaction: %w", err)
 	}
 
 	if trx == nil || trx.Deleted {
-		s.throwError(http.StatusNotFound, "transaction not found")
+		return nil, model.NewNotFoundError("transaction not found")
+	}
+
+	return trx, nil
+}
+
+// ConvertTransactionsCurrency converts the transactions selected by ID, or by the filter when there are no IDs,
+// to the currency of the country, emitting each result as soon as it is converted. A transaction that cannot be
+// converted has its error in the result instead of failing the whole batch
+func (s *TransactionCurrencyServiceImpl) ConvertTransactionsCurrency(ctx context.Context, country string, transactionIDs []int64, filter *model.TransactionFilter, emit func(presentation.TransactionCurrencyResultDTO)) (err error) {
+	ctx, span := s.tracer.Start(ctx, "TransactionCurrencyService.ConvertTransactionsCurrency",
+		trace.WithAttributes(attribute.String("country", country), attribute.Int("transaction.count", len(transactionIDs))))
+	defer func() { util.EndSpan(span, err) }()
+
+	if country == "" {
+		return model.NewValidationError("invalid country name")
+	}
+
+	periodRates := map[time.Time]*periodExchangeRate{}
+
+	if filter == nil {
+		return s.convertTransactionsByIDs(ctx, country, transactionIDs, periodRates, emit)
 	}
 
-	// get treasury by country
-	exchangeRate, err := s.treasuryRepository.GetExchangeRateByCountry(ctx, country)
+	// pages by id, the transactions created or deleted while streaming do not shift the next pages
+	page := *filter
+	page.Offset = 0
+	for ctx.Err() == nil {
+		transactions, _, err := s.transactionRepository.ListTransactions(ctx, &page)
+		if err != nil {
+			return fmt.Errorf("error listing transactions: %w", err)
+		}
+
+		for i := range transactions {
+			emit(s.convertInPeriod(ctx, country, &transactions[i], periodRates))
+		}
+
+		if len(transactions) == 0 || len(transactions) < page.Limit {
+			return nil
+		}
+
+		page.AfterID = transactions[len(transactions)-1].ID
+	}
+
+	return ctx.Err()
+}
+
+func (s *TransactionCurrencyServiceImpl) convertTransactionsByIDs(ctx context.Context, country string, transactionIDs []int64, periodRates map[time.Time]*periodExchangeRate, emit func(presentation.TransactionCurrencyResultDTO)) error {
+	transactions, err := s.transactionRepository.GetTransactionsByIDs(ctx, transactionIDs)
 	if err != nil {
-		s.throwError(http.StatusBadGateway, err.Error())
+		return fmt.Errorf("error getting transactions: %w", err)
 	}
 
-	if len(exchangeRate.Data) == 0 {
-		s.throwError(http.StatusBadGateway, "purchase cannot be converted to the target currency: no data found")
+	found := make(map[int64]*model.Transaction, len(transactions))
+	for i := range transactions {
+		found[transactions[i].ID] = &transactions[i]
 	}
 
-	if !s.isAbleToConvertToTargetCurrency(trx.TransactionDate, *exchangeRate) {
-		s.throwError(http.StatusBadGateway, "purchase cannot be converted to the target currency: not found effective rate to convert")
+	for _, transactionID := range transactionIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		trx, ok := found[transactionID]
+		if !ok || trx.Deleted {
+			emit(presentation.TransactionCurrencyResultDTO{
+				TransactionID: transactionID,
+				Country:       country,
+				Error:         presentation.NewApiErrorFromError(model.NewNotFoundError("transaction not found")),
+			})
+			continue
+		}
+
+		emit(s.convertInPeriod(ctx, country, trx, periodRates))
+	}
+
+	return nil
+}
+
+// periodExchangeRate is the exchange rate recovered for a rate period, or the error recovering it
+type periodExchangeRate struct {
+	exchangeRate *model.ExchangeRate
+	err          error
+}
+
+// convertInPeriod converts the transaction with the rate of its rate period, recovered only once per period using
+// the last day of the period. When that rate is effective after the transaction, as happens with rates published
+// in the middle of the quarter, or there is no rate, the rate of the transaction date is recovered instead
+func (s *TransactionCurrencyServiceImpl) convertInPeriod(ctx context.Context, country string, trx *model.Transaction, periodRates map[time.Time]*periodExchangeRate) presentation.TransactionCurrencyResultDTO {
+	period := util.ExchangeRatePeriod(trx.TransactionDate)
+
+	rate, found := periodRates[period]
+	if !found {
+		lastDay := util.NextExchangeRatePeriod(period).AddDate(0, 0, -1)
+		exchangeRate, err := s.getExchangeRate(ctx, country, lastDay)
+		rate = &periodExchangeRate{exchangeRate: exchangeRate, err: err}
+		periodRates[period] = rate
+	}
+
+	result := presentation.TransactionCurrencyResultDTO{
+		TransactionID: trx.ID,
+		Country:       country,
+	}
+
+	var err error
+	switch {
+	case rate.err != nil:
+		err = rate.err
+	case rate.exchangeRate != nil && rate.exchangeRate.EffectiveDate.Format(exchangeRateDateFormat) <= trx.TransactionDate.Format(exchangeRateDateFormat):
+		result.Conversion, err = s.convertWithExchangeRate(trx, rate.exchangeRate)
+	default:
+		result.Conversion, err = s.convert(ctx, trx, country)
 	}
 
-	exchangeRateConverted, err := strconv.ParseFloat(exchangeRate.Data[0].ExchangeRate, 32)
 	if err != nil {
-		s.throwError(http.StatusBadGateway, "purchase cannot be converted to the target currency: invalid exchange rate. rate="+exchangeRate.Data[0].ExchangeRate)
+		result.Error = presentation.NewApiErrorFromError(err)
 	}
 
-	return &presentation.TransactionCurrencyDTO{
-		TransactionID:           trx.ID,
-		Description:             trx.Description,
-		TransactionDate:         util.FormatDate(trx.TransactionDate),
-		PurchaseAmount:          trx.PurchaseAmount,
-		ExchangeRate:            float32(exchangeRateConverted),
-		ConvertedPurchaseAmount: util.RoundPurchaseAmount(trx.PurchaseAmount * float32(exchangeRateConverted)),
+	return result
+}
+
+func (s *TransactionCurrencyServiceImpl) convert(ctx context.Context, trx *model.Transaction, country string) (*presentation.TransactionCurrencyDTO, error) {
+	exchangeRate, err := s.getExchangeRate(ctx, country, trx.TransactionDate)
+	if err != nil {
+		return nil, err
 	}
+
+	return s.convertWithExchangeRate(trx, exchangeRate)
 }
 
-// isAbleToConvertToTargetCurrency validates if the transaction date is within 6 months of the effective rate date
-func (s *TransactionCurrencyServiceImpl) isAbleToConvertToTargetCurrency(transactionDate time.Time, exchangeRate model.TreasuryRatesExchange) bool {
-	effectiveDateParsed, err := util.ParseDateWithFormat(exchangeRate.Data[0].EffectiveDate, exchangeRateDateFormat)
+// getExchangeRate is traced apart as it may be answered by the cache or any of the configured providers
+func (s *TransactionCurrencyServiceImpl) getExchangeRate(ctx context.Context, country string, date time.Time) (rate *model.ExchangeRate, err error) {
+	ctx, span := s.tracer.Start(ctx, "TransactionCurrencyService.getExchangeRate",
+		trace.WithAttributes(attribute.String("country", country), attribute.String("date", date.Format(exchangeRateDateFormat))))
+	defer func() { util.EndSpan(span, err) }()
+
+	exchangeRate, err := s.exchangeRateProvider.GetExchangeRate(ctx, country, date)
+	if err != nil {
+		util.Logger(ctx, s.log).Error("error getting exchange rate", "country", country, "error", err)
+		return nil, model.NewUpstreamUnavailableError("exchange rate unavailable, try again later", err)
+	}
+
+	if exchangeRate != nil {
+		span.SetAttributes(attribute.String("exchange_rate.source", exchangeRate.Source))
+	}
+
+	return exchangeRate, nil
+}
+
+func (s *TransactionCurrencyServiceImpl) convertWithExchangeRate(trx *model.Transaction, exchangeRate *model.ExchangeRate) (*presentation.TransactionCurrencyDTO, error) {
+	if exchangeRate == nil {
+		s.rejected.Inc()
+		return nil, model.NewRateNotFoundError("purchase cannot be converted to the target currency: no data found")
+	}
+
+	if !s.isAbleToConvertToTargetCurrency(trx.TransactionDate, exchangeRate.EffectiveDate) {
+		s.rejected.Inc()
+		return nil, model.NewRateNotFoundError("purchase cannot be converted to the target currency: not found effective rate to convert")
+	}
+
+	rate := util.FormatDecimal(exchangeRate.Rate)
+	convertedPurchaseAmount, err := util.ConvertAmount(trx.PurchaseAmount, exchangeRate.Rate)
 	if err != nil {
-		return false
+		return nil, model.NewUpstreamUnavailableError("purchase cannot be converted to the target currency: invalid exchange rate. rate="+rate, err)
 	}
 
-	return effectiveDateParsed.
-		AddDate(0, 6, 0).
-		Compare(transactionDate) >= 0
+	return &presentation.TransactionCurrencyDTO{
+		TransactionID:           trx.ID,
+		Description:             trx.Description,
+		TransactionDate:         util.FormatDate(trx.TransactionDate),
+		PurchaseAmount:          presentation.Amount(trx.PurchaseAmount),
+		ExchangeRate:            json.Number(rate),
+		ConvertedPurchaseAmount: presentation.Amount(convertedPurchaseAmount),
+		EffectiveDate:           exchangeRate.EffectiveDate.Format(exchangeRateDateFormat),
+		Source:                  exchangeRate.Source,
+	}, nil
 }
 
-func (s *TransactionCurrencyServiceImpl) throwError(status int, message string) {
-	panic(presentation.NewApiError(status, message))
+// isAbleToConvertToTargetCurrency validates if the effective rate date is on or before the transaction date
+// and no more than 6 months earlier
+func (s *TransactionCurrencyServiceImpl) isAbleToConvertToTargetCurrency(transactionDate time.Time, effectiveDate time.Time) bool {
+	effectiveDay := time.Date(effectiveDate.Year(), effectiveDate.Month(), effectiveDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	// effective dates have no time zone, so compare only with the calendar day of the transaction
+	transactionDay := time.Date(transactionDate.Year(), transactionDate.Month(), transactionDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	return effectiveDay.Compare(transactionDay) <= 0 &&
+		effectiveDay.AddDate(0, 6, 0).Compare(transactionDay) >= 0
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -9,35 +10,42 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/metrics"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
 	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func Test_GetTransactionCurrencyConverted(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 
-	treasuryRepository := mock_repository.NewMockTreasuryRepository(mockCtrl)
+	exchangeRateProvider := mock_repository.NewMockExchangeRateProvider(mockCtrl)
 	transactionRepository := mock_repository.NewMockTransactionRepository(mockCtrl)
+	rejected := metrics.New().ConversionsRejected
 	log := slog.Default()
 	context := context.Background()
 
-	service := NewTransactionCurrencyService(treasuryRepository, transactionRepository, log)
+	service := NewTransactionCurrencyService(exchangeRateProvider, transactionRepository, rejected, log)
 
 	t.Run("GetTransactionCurrencyConverted failed because invalid transaction id", func(t *testing.T) {
 		// given
 		transactionID := int64(0)
 		country := ""
 		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid transaction id")
-		defer assertPanicErrors(t, expectedError)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
 	})
 
 	t.Run("GetTransactionCurrencyConverted failed because invalid country name", func(t *testing.T) {
@@ -45,13 +53,13 @@ func Test_GetTransactionCurrencyConverted(t *testing.T) {
 		transactionID := int64(1)
 		country := ""
 		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid country name")
-		defer assertPanicErrors(t, expectedError)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
 	})
 
 	t.Run("GetTransactionCurrencyConverted failed because transaction repository failed", func(t *testing.T) {
@@ -59,16 +67,17 @@ func Test_GetTransactionCurrencyConverted(t *testing.T) {
 		transactionID := int64(1)
 		country := "Brazil"
 		errorMessage := "mock error"
-		expectedError := presentation.NewApiError(http.StatusFailedDependency, errorMessage)
-		defer assertPanicErrors(t, expectedError)
+		expectedError := presentation.NewApiError(http.StatusInternalServerError, "internal server error")
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(nil, errors.New(errorMessage))
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(nil, errors.New(errorMessage))
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assert.EqualError(t, err, "error getting transaction: "+errorMessage)
+		assertApiError(t, expectedError, err)
 	})
 
 	t.Run("GetTransactionCurrencyConverted failed because transaction not found", func(t *testing.T) {
@@ -77,129 +86,114 @@ func Test_GetTransactionCurrencyConverted(t *testing.T) {
 		country := "Brazil"
 		errorMessage := "transaction not found"
 		expectedError := presentation.NewApiError(http.StatusNotFound, errorMessage)
-		defer assertPanicErrors(t, expectedError)
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(nil, nil)
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(nil, nil)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
 	})
 
-	t.Run("GetTransactionCurrencyConverted failed because treasury repository failed", func(t *testing.T) {
+	t.Run("GetTransactionCurrencyConverted failed because exchange rate provider failed", func(t *testing.T) {
 		// given
 		transactionID := int64(1)
 		country := "Brazil"
-		errorMessage := "treasury repository error"
-		expectedError := presentation.NewApiError(http.StatusBadGateway, errorMessage)
-		defer assertPanicErrors(t, expectedError)
+		errorMessage := "exchange rate provider error"
+		expectedError := presentation.NewApiError(http.StatusBadGateway, "exchange rate unavailable, try again later")
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(&model.Transaction{}, nil)
-		treasuryRepository.EXPECT().GetExchangeRateByCountry(context, country).Return(nil, errors.New(errorMessage))
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(&model.Transaction{}, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), country, gomock.Any()).Return(nil, errors.New(errorMessage))
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
 	})
 
-	t.Run("GetTransactionCurrencyConverted failed because treasury repository returns no data", func(t *testing.T) {
+	t.Run("GetTransactionCurrencyConverted failed because exchange rate provider returns no data", func(t *testing.T) {
 		// given
 		transactionID := int64(1)
 		country := "Brazil"
 		errorMessage := "purchase cannot be converted to the target currency: no data found"
-		expectedError := presentation.NewApiError(http.StatusBadGateway, errorMessage)
-		defer assertPanicErrors(t, expectedError)
+		expectedError := &presentation.ApiError{Code: http.StatusBadGateway, Message: errorMessage, ErrorCode: presentation.ErrorCodeExchangeRateNotFound}
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(&model.Transaction{}, nil)
-		treasuryRepository.EXPECT().GetExchangeRateByCountry(context, country).Return(&model.TreasuryRatesExchange{
-			Data: []model.Data{},
-		}, nil)
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(&model.Transaction{}, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), country, gomock.Any()).Return(nil, nil)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
 	})
 
-	t.Run("GetTransactionCurrencyConverted failed because treasury repository returns an invalid effective date format", func(t *testing.T) {
+	t.Run("GetTransactionCurrencyConverted failed because not found effective rate to convert", func(t *testing.T) {
 		// given
 		transactionID := int64(1)
 		country := "Brazil"
 		errorMessage := "purchase cannot be converted to the target currency: not found effective rate to convert"
-		expectedError := presentation.NewApiError(http.StatusBadGateway, errorMessage)
-		defer assertPanicErrors(t, expectedError)
+		expectedError := &presentation.ApiError{Code: http.StatusBadGateway, Message: errorMessage, ErrorCode: presentation.ErrorCodeExchangeRateNotFound}
+		rejectedBefore := testutil.ToFloat64(rejected)
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(&model.Transaction{
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(&model.Transaction{
 			TransactionDate: time.Now(),
 		}, nil)
-		treasuryRepository.EXPECT().GetExchangeRateByCountry(context, country).Return(&model.TreasuryRatesExchange{
-			Data: []model.Data{
-				{
-					EffectiveDate: "2021-01-01T00:00:00Z",
-				},
-			}}, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), country, gomock.Any()).Return(newTestExchangeRate("2021-01-01", "1"), nil)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
+		assert.Equal(t, rejectedBefore+1, testutil.ToFloat64(rejected))
 	})
 
-	t.Run("GetTransactionCurrencyConverted failed because not found effective rate to convert", func(t *testing.T) {
+	t.Run("GetTransactionCurrencyConverted failed because effective rate is after the transaction date", func(t *testing.T) {
 		// given
 		transactionID := int64(1)
 		country := "Brazil"
 		errorMessage := "purchase cannot be converted to the target currency: not found effective rate to convert"
-		expectedError := presentation.NewApiError(http.StatusBadGateway, errorMessage)
-		defer assertPanicErrors(t, expectedError)
+		expectedError := &presentation.ApiError{Code: http.StatusBadGateway, Message: errorMessage, ErrorCode: presentation.ErrorCodeExchangeRateNotFound}
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(&model.Transaction{
-			TransactionDate: time.Now(),
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(&model.Transaction{
+			TransactionDate: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
 		}, nil)
-		treasuryRepository.EXPECT().GetExchangeRateByCountry(context, country).Return(&model.TreasuryRatesExchange{
-			Data: []model.Data{
-				{
-					EffectiveDate: "2021-01-01",
-				},
-			}}, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), country, gomock.Any()).Return(newTestExchangeRate("2025-01-01", "6.18"), nil)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
 	})
 
-	t.Run("GetTransactionCurrencyConverted failed because invalid exchange rate", func(t *testing.T) {
+	t.Run("GetTransactionCurrencyConverted failed because converted amount out of range", func(t *testing.T) {
 		// given
 		transactionID := int64(1)
 		country := "Brazil"
-		errorMessage := "purchase cannot be converted to the target currency: invalid exchange rate. rate=mock"
+		errorMessage := "purchase cannot be converted to the target currency: invalid exchange rate. rate=1000000000000000000000000000000"
 		expectedError := presentation.NewApiError(http.StatusBadGateway, errorMessage)
-		defer assertPanicErrors(t, expectedError)
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(&model.Transaction{
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(&model.Transaction{
 			TransactionDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			PurchaseAmount:  100,
 		}, nil)
-		treasuryRepository.EXPECT().GetExchangeRateByCountry(context, country).Return(&model.TreasuryRatesExchange{
-			Data: []model.Data{
-				{
-					EffectiveDate: "2025-01-01",
-					ExchangeRate:  "mock",
-				},
-			}}, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), country, gomock.Any()).Return(newTestExchangeRate("2025-01-01", "1000000000000000000000000000000"), nil)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
 		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
 	})
 
 	t.Run("GetTransactionCurrencyConverted with sucess", func(t *testing.T) {
@@ -208,36 +202,322 @@ func Test_GetTransactionCurrencyConverted(t *testing.T) {
 		country := "Brazil"
 		transaction := &model.Transaction{
 			TransactionDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-			PurchaseAmount:  1.74,
+			PurchaseAmount:  174,
 		}
-		exchangeRate := &model.TreasuryRatesExchange{
-			Data: []model.Data{
-				{
-					EffectiveDate: "2025-01-01",
-					ExchangeRate:  "6.18",
-				},
-			}}
+		exchangeRate := newTestExchangeRate("2025-01-01", "6.18")
 		expectedResponse := &presentation.TransactionCurrencyDTO{
-			ConvertedPurchaseAmount: 10.75,
-			PurchaseAmount:          transaction.PurchaseAmount,
+			ConvertedPurchaseAmount: 1075,
+			PurchaseAmount:          presentation.Amount(transaction.PurchaseAmount),
 			TransactionDate:         util.FormatDate(transaction.TransactionDate),
-			ExchangeRate:            6.18,
+			ExchangeRate:            "6.18",
+			EffectiveDate:           "2025-01-01",
+			Source:                  "treasury",
 		}
 
-		transactionRepository.EXPECT().GetTransaction(transactionID).Return(transaction, nil)
-		treasuryRepository.EXPECT().GetExchangeRateByCountry(context, country).Return(exchangeRate, nil)
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(transaction, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), country, gomock.Any()).Return(exchangeRate, nil)
 
 		// when
-		response := service.GetTransactionCurrencyConverted(context, transactionID, country)
+		response, err := service.GetTransactionCurrencyConverted(context, transactionID, country)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 		assert.Equal(t, expectedResponse, response)
 	})
 }
 
-func assertPanicErrors(t *testing.T, expectedError *presentation.ApiError) {
-	if r := recover(); r != nil {
-		assert.Equal(t, expectedError, r)
+func Test_GetTransactionCurrenciesConverted(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	exchangeRateProvider := mock_repository.NewMockExchangeRateProvider(mockCtrl)
+	transactionRepository := mock_repository.NewMockTransactionRepository(mockCtrl)
+	rejected := metrics.New().ConversionsRejected
+	log := slog.Default()
+	context := context.Background()
+
+	service := NewTransactionCurrencyService(exchangeRateProvider, transactionRepository, rejected, log)
+
+	t.Run("GetTransactionCurrenciesConverted failed because invalid transaction id", func(t *testing.T) {
+		// given
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid transaction id")
+
+		// when
+		response, err := service.GetTransactionCurrenciesConverted(context, 0, []string{"Brazil"})
+
+		// then
+		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
+	})
+
+	t.Run("GetTransactionCurrenciesConverted failed because no countries", func(t *testing.T) {
+		// given
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid country names")
+
+		// when
+		response, err := service.GetTransactionCurrenciesConverted(context, 1, []string{})
+
+		// then
+		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
+	})
+
+	t.Run("GetTransactionCurrenciesConverted failed because transaction not found", func(t *testing.T) {
+		// given
+		transactionID := int64(1)
+		expectedError := presentation.NewApiError(http.StatusNotFound, "transaction not found")
+
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(nil, nil)
+
+		// when
+		response, err := service.GetTransactionCurrenciesConverted(context, transactionID, []string{"Brazil"})
+
+		// then
+		assert.Nil(t, response)
+		assertApiError(t, expectedError, err)
+	})
+
+	t.Run("GetTransactionCurrenciesConverted with success and per country errors", func(t *testing.T) {
+		// given
+		transactionID := int64(1)
+		transaction := &model.Transaction{
+			ID:              transactionID,
+			TransactionDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			PurchaseAmount:  174,
+		}
+		countries := []string{"Brazil", "Canada", "Mexico"}
+
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Brazil", transaction.TransactionDate).Return(newTestExchangeRate("2025-01-01", "6.18"), nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Canada", transaction.TransactionDate).Return(nil, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Mexico", transaction.TransactionDate).Return(nil, errors.New("exchange rate provider error"))
+
+		// when
+		response, err := service.GetTransactionCurrenciesConverted(context, transactionID, countries)
+
+		// then
+		assert.NoError(t, err)
+		expectedResponse := []presentation.TransactionCurrencyResultDTO{
+			{
+				TransactionID: transactionID,
+				Country:       "Brazil",
+				Conversion: &presentation.TransactionCurrencyDTO{
+					TransactionID:           transactionID,
+					TransactionDate:         util.FormatDate(transaction.TransactionDate),
+					PurchaseAmount:          174,
+					ExchangeRate:            "6.18",
+					ConvertedPurchaseAmount: 1075,
+					EffectiveDate:           "2025-01-01",
+					Source:                  "treasury",
+				},
+			},
+			{
+				TransactionID: transactionID,
+				Country:       "Canada",
+				Error:         &presentation.ApiError{Code: http.StatusBadGateway, Message: "purchase cannot be converted to the target currency: no data found", ErrorCode: presentation.ErrorCodeExchangeRateNotFound},
+			},
+			{
+				TransactionID: transactionID,
+				Country:       "Mexico",
+				Error:         presentation.NewApiError(http.StatusBadGateway, "exchange rate unavailable, try again later"),
+			},
+		}
+		assert.Equal(t, expectedResponse, response)
+	})
+}
+
+func Test_ConvertTransactionsCurrency(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	exchangeRateProvider := mock_repository.NewMockExchangeRateProvider(mockCtrl)
+	transactionRepository := mock_repository.NewMockTransactionRepository(mockCtrl)
+	rejected := metrics.New().ConversionsRejected
+	log := slog.Default()
+	context := context.Background()
+
+	service := NewTransactionCurrencyService(exchangeRateProvider, transactionRepository, rejected, log)
+
+	collect := func(results *[]presentation.TransactionCurrencyResultDTO) func(presentation.TransactionCurrencyResultDTO) {
+		return func(result presentation.TransactionCurrencyResultDTO) {
+			*results = append(*results, result)
+		}
+	}
+
+	t.Run("ConvertTransactionsCurrency failed because invalid country", func(t *testing.T) {
+		// given
+		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid country name")
+
+		// when
+		err := service.ConvertTransactionsCurrency(context, "", []int64{1}, nil, func(presentation.TransactionCurrencyResultDTO) {})
+
+		// then
+		assertApiError(t, expectedError, err)
+	})
+
+	t.Run("ConvertTransactionsCurrency failed because repository error", func(t *testing.T) {
+		// given
+		expectedError := presentation.NewApiError(http.StatusInternalServerError, "internal server error")
+
+		transactionRepository.EXPECT().GetTransactionsByIDs(gomock.Any(), []int64{1}).Return(nil, errors.New("database error"))
+
+		// when
+		err := service.ConvertTransactionsCurrency(context, "Brazil", []int64{1}, nil, func(presentation.TransactionCurrencyResultDTO) {})
+
+		// then
+		assertApiError(t, expectedError, err)
+	})
+
+	t.Run("ConvertTransactionsCurrency by ids fetches one rate per period", func(t *testing.T) {
+		// given
+		transactions := []model.Transaction{
+			{ID: 1, TransactionDate: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), PurchaseAmount: 100},
+			{ID: 2, TransactionDate: time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC), PurchaseAmount: 200},
+			{ID: 4, TransactionDate: time.Date(2025, 2, 21, 0, 0, 0, 0, time.UTC), PurchaseAmount: 300, Deleted: true},
+		}
+
+		transactionRepository.EXPECT().GetTransactionsByIDs(gomock.Any(), []int64{2, 1, 3, 4}).Return(transactions, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Brazil", time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)).Return(newTestExchangeRate("2024-12-31", "6.00"), nil).Times(1)
+
+		// when
+		var results []presentation.TransactionCurrencyResultDTO
+		err := service.ConvertTransactionsCurrency(context, "Brazil", []int64{2, 1, 3, 4}, nil, collect(&results))
+
+		// then
+		assert.NoError(t, err)
+		assert.Len(t, results, 4)
+		assert.Equal(t, int64(2), results[0].TransactionID)
+		assert.Equal(t, presentation.Amount(1200), results[0].Conversion.ConvertedPurchaseAmount)
+		assert.Equal(t, int64(1), results[1].TransactionID)
+		assert.Equal(t, presentation.Amount(600), results[1].Conversion.ConvertedPurchaseAmount)
+		assert.Equal(t, presentation.TransactionCurrencyResultDTO{
+			TransactionID: 3,
+			Country:       "Brazil",
+			Error:         presentation.NewApiError(http.StatusNotFound, "transaction not found"),
+		}, results[2])
+		assert.Equal(t, presentation.NewApiError(http.StatusNotFound, "transaction not found"), results[3].Error)
+	})
+
+	t.Run("ConvertTransactionsCurrency by ids recovers the transaction rate when period rate is effective later", func(t *testing.T) {
+		// given
+		transactions := []model.Transaction{
+			{ID: 1, TransactionDate: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), PurchaseAmount: 100},
+			{ID: 2, TransactionDate: time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC), PurchaseAmount: 100},
+		}
+
+		transactionRepository.EXPECT().GetTransactionsByIDs(gomock.Any(), []int64{1, 2}).Return(transactions, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Brazil", time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)).Return(newTestExchangeRate("2025-03-15", "5.50"), nil).Times(1)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Brazil", transactions[0].TransactionDate).Return(newTestExchangeRate("2024-12-31", "6.00"), nil).Times(1)
+
+		// when
+		var results []presentation.TransactionCurrencyResultDTO
+		err := service.ConvertTransactionsCurrency(context, "Brazil", []int64{1, 2}, nil, collect(&results))
+
+		// then
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, json.Number("6"), results[0].Conversion.ExchangeRate)
+		assert.Equal(t, json.Number("5.5"), results[1].Conversion.ExchangeRate)
+	})
+
+	t.Run("ConvertTransactionsCurrency by filter pages through transactions by id and reports rate errors per row", func(t *testing.T) {
+		// given
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+		filter := &model.TransactionFilter{TransactionDateFrom: &from, TransactionDateTo: &to, Limit: 1}
+
+		firstPage := *filter
+		secondPage := *filter
+		secondPage.AfterID = 1
+		thirdPage := *filter
+		thirdPage.AfterID = 2
+
+		transactionRepository.EXPECT().ListTransactions(gomock.Any(), &firstPage).Return([]model.Transaction{
+			{ID: 1, TransactionDate: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), PurchaseAmount: 100},
+		}, int64(2), nil)
+		transactionRepository.EXPECT().ListTransactions(gomock.Any(), &secondPage).Return([]model.Transaction{
+			{ID: 2, TransactionDate: time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC), PurchaseAmount: 100},
+		}, int64(2), nil)
+		transactionRepository.EXPECT().ListTransactions(gomock.Any(), &thirdPage).Return([]model.Transaction{}, int64(0), nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Brazil", time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)).Return(newTestExchangeRate("2024-12-31", "6.00"), nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Brazil", time.Date(2025, 6, 29, 0, 0, 0, 0, time.UTC)).Return(nil, errors.New("exchange rate provider error"))
+
+		// when
+		var results []presentation.TransactionCurrencyResultDTO
+		err := service.ConvertTransactionsCurrency(context, "Brazil", nil, filter, collect(&results))
+
+		// then
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, presentation.Amount(600), results[0].Conversion.ConvertedPurchaseAmount)
+		assert.Equal(t, presentation.TransactionCurrencyResultDTO{
+			TransactionID: 2,
+			Country:       "Brazil",
+			Error:         presentation.NewApiError(http.StatusBadGateway, "exchange rate unavailable, try again later"),
+		}, results[1])
+	})
+}
+
+func Test_TransactionCurrencyService_Tracing(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	exchangeRateProvider := mock_repository.NewMockExchangeRateProvider(mockCtrl)
+	transactionRepository := mock_repository.NewMockTransactionRepository(mockCtrl)
+	recorder := tracetest.NewSpanRecorder()
+	service := NewTransactionCurrencyService(exchangeRateProvider, transactionRepository, metrics.New().ConversionsRejected, slog.Default())
+	service.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	t.Run("Trace the conversion with the exchange rate lookup as its child", func(t *testing.T) {
+		// given
+		transaction := &model.Transaction{ID: 1, TransactionDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), PurchaseAmount: 174}
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), int64(1)).Return(transaction, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Canada", transaction.TransactionDate).Return(nil, nil)
+
+		// when
+		_, err := service.GetTransactionCurrencyConverted(context.Background(), 1, "Canada")
+
+		// then
+		assert.Error(t, err)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "TransactionCurrencyService.getExchangeRate", spans[0].Name())
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Equal(t, "TransactionCurrencyService.GetTransactionCurrencyConverted", spans[1].Name())
+		assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+		assert.Contains(t, spans[1].Attributes(), attribute.String("country", "Canada"))
+	})
+
+	t.Run("Trace the source of the exchange rate", func(t *testing.T) {
+		// given
+		transaction := &model.Transaction{ID: 2, TransactionDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), PurchaseAmount: 174}
+		transactionRepository.EXPECT().GetTransaction(gomock.Any(), int64(2)).Return(transaction, nil)
+		exchangeRateProvider.EXPECT().GetExchangeRate(gomock.Any(), "Brazil", transaction.TransactionDate).Return(newTestExchangeRate("2025-01-01", "6.18"), nil)
+
+		// when
+		_, err := service.GetTransactionCurrencyConverted(context.Background(), 2, "Brazil")
+
+		// then
+		assert.NoError(t, err)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 4)
+		assert.Equal(t, "TransactionCurrencyService.getExchangeRate", spans[2].Name())
+		assert.Contains(t, spans[2].Attributes(), attribute.String("exchange_rate.source", "treasury"))
+	})
+}
+
+func assertApiError(t *testing.T, expectedError *presentation.ApiError, err error) {
+	assert.Error(t, err)
+	assert.Equal(t, expectedError, presentation.NewApiErrorFromError(err))
+}
+
+func newTestExchangeRate(effectiveDate, rate string) *model.ExchangeRate {
+	parsedDate, _ := time.Parse(exchangeRateDateFormat, effectiveDate)
+	parsedRate, _ := util.ParseDecimal(rate)
+
+	return &model.ExchangeRate{
+		Country:       "Brazil",
+		Rate:          parsedRate,
+		EffectiveDate: parsedDate,
+		Source:        model.ExchangeRateSourceTreasury,
 	}
 }
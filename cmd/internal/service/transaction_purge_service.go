@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+// purgeActor is the actor recorded in the events of the purged transactions
+const purgeActor = "purge-job"
+
+type TransactionPurgeService interface {
+	Purge(ctx context.Context) (int, error)
+}
+
+//go:generate mockgen -source=./transaction_purge_service.go -destination=./mocks/transaction_purge_service_mock.go
+
+type TransactionPurgeServiceImpl struct {
+	repository repository.TransactionRepository
+	cache      repository.TransactionCache
+	retention  time.Duration
+	interval   time.Duration
+	log        *slog.Logger
+	now        func() time.Time
+}
+
+func NewTransactionPurgeService(
+	repository repository.TransactionRepository,
+	cache repository.TransactionCache,
+	retention time.Duration,
+	interval time.Duration,
+	log *slog.Logger) *TransactionPurgeServiceImpl {
+
+	return &TransactionPurgeServiceImpl{
+		repository: repository,
+		cache:      cache,
+		retention:  retention,
+		interval:   interval,
+		log:        log,
+		now:        time.Now,
+	}
+}
+
+// Purge removes for good the transactions deleted longer than the retention and returns how many were removed
+func (s *TransactionPurgeServiceImpl) Purge(ctx context.Context) (int, error) {
+	now := s.now()
+	purgedIDs, err := s.repository.PurgeDeletedTransactions(ctx, now.Add(-s.retention), model.Audit{Actor: purgeActor, At: now})
+	if err != nil {
+		return 0, fmt.Errorf("error purging deleted transactions: %w", err)
+	}
+
+	for _, transactionID := range purgedIDs {
+		s.cache.Delete(ctx, transactionID)
+	}
+
+	return len(purgedIDs), nil
+}
+
+// Start runs the purge right away and then once per interval until the context is done, a retention of zero
+// disables the purge
+func (s *TransactionPurgeServiceImpl) Start(ctx context.Context) {
+	if s.retention <= 0 {
+		s.log.Info("Purge of deleted transactions disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if purged, err := s.Purge(ctx); err != nil {
+			s.log.Error("error purging deleted transactions", "error", err)
+		} else {
+			s.log.Info("Deleted transactions purged", "purged", purged, "retention", s.retention.String())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionPurgeService_Purge(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+	mockCache := mock_repository.NewMockTransactionCache(mockController)
+
+	purgeService := NewTransactionPurgeService(mockRepository, mockCache, 30*24*time.Hour, time.Hour, slog.Default())
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	purgeService.now = func() time.Time { return now }
+
+	t.Run("Purge removes the transactions deleted before the retention from db and cache", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().PurgeDeletedTransactions(gomock.Any(), time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC), model.Audit{Actor: "purge-job", At: now}).Return([]int64{3, 7}, nil)
+		mockCache.EXPECT().Delete(gomock.Any(), int64(3))
+		mockCache.EXPECT().Delete(gomock.Any(), int64(7))
+
+		// when
+		purged, err := purgeService.Purge(context.TODO())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 2, purged)
+	})
+
+	t.Run("Purge error on repository", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().PurgeDeletedTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("database is locked"))
+
+		// when
+		purged, err := purgeService.Purge(context.TODO())
+
+		// then
+		assert.EqualError(t, err, "error purging deleted transactions: database is locked")
+		assert.Equal(t, 0, purged)
+	})
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
+	"time"
 
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
@@ -11,11 +13,17 @@ import (
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
 )
 
+var errVersionMismatch = model.NewPreconditionFailedError("transaction was changed, If-Match does not match its current version")
+
 type TransactionService interface {
-	GetTransactionByID(transactionID int64) *presentation.TransactionDTO
-	SaveTransaction(transaction *model.Transaction) *presentation.TransactionDTO
-	UpdateTransactionByID(transactionID int64, transaction *model.Transaction) *presentation.TransactionDTO
-	DeleteTransactionByID(transactionID int64)
+	GetTransactionByID(ctx context.Context, transactionID int64) (*presentation.TransactionDTO, error)
+	SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*presentation.TransactionDTO, error)
+	UpdateTransactionByID(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error)
+	PatchTransactionByID(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error)
+	DeleteTransactionByID(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) error
+	RestoreTransactionByID(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error)
+	ListTransactions(ctx context.Context, filter *model.TransactionFilter) (*presentation.TransactionPageDTO, error)
+	GetTransactionHistory(ctx context.Context, transactionID int64, asOf *time.Time) (*presentation.TransactionHistoryDTO, error)
 }
 
 //go:generate mockgen -source=./transaction_service.go -destination=./mocks/transaction_service_mock.go
@@ -24,6 +32,7 @@ type TransactionServiceImpl struct {
 	log        *slog.Logger
 	repository repository.TransactionRepository
 	cache      repository.TransactionCache
+	now        func() time.Time
 }
 
 func NewTransactionService(
@@ -35,130 +44,244 @@ func NewTransactionService(
 		log:        log,
 		repository: repository,
 		cache:      cache,
+		now:        time.Now,
 	}
 }
 
-func (t *TransactionServiceImpl) GetTransactionByID(transactionID int64) *presentation.TransactionDTO {
+func (t *TransactionServiceImpl) GetTransactionByID(ctx context.Context, transactionID int64) (*presentation.TransactionDTO, error) {
 
 	if transactionID <= 0 {
-		t.throwError(http.StatusBadRequest, fmt.Sprintf("invalid transaction id: %d", transactionID))
+		return nil, model.NewValidationError(fmt.Sprintf("invalid transaction id: %d", transactionID))
 	}
 
 	// recover from cache
-	if trx := t.cache.Get(transactionID); trx != nil {
-		t.log.Debug("Transaction found in cache", "transaction_id", transactionID)
-		return &presentation.TransactionDTO{
-			TransactionID:   transactionID,
-			Description:     trx.Description,
-			TransactionDate: util.FormatDate(trx.TransactionDate),
-			PurchaseAmount:  trx.PurchaseAmount,
-			Deleted:         trx.Deleted,
-		}
+	if trx := t.cache.Get(ctx, transactionID); trx != nil {
+		util.Logger(ctx, t.log).Debug("Transaction found in cache", "transaction_id", transactionID)
+		return presentation.NewTransactionDTO(trx), nil
 	}
 
 	// if not found on cache, go to database
-	t.log.Debug("Transaction not found in cache, searching on db", "transaction_id", transactionID)
-	trx, err := t.repository.GetTransaction(transactionID)
+	util.Logger(ctx, t.log).Debug("Transaction not found in cache, searching on db", "transaction_id", transactionID)
+	trx, err := t.repository.GetTransaction(ctx, transactionID)
 	if err != nil {
-		t.throwError(http.StatusInternalServerError, "error getting transaction")
+		return nil, fmt.Errorf("error getting transaction: %w", err)
 	}
 
 	if trx == nil {
-		t.throwError(http.StatusNotFound, "transaction not found")
+		return nil, model.NewNotFoundError("transaction not found")
 	}
 
 	trx.ID = transactionID
-	if err := t.cache.Save(transactionID, trx); err != nil {
-		t.log.Error("error saving transaction cache ", "transaction_id", trx.ID)
+	if err := t.cache.Save(ctx, transactionID, trx); err != nil {
+		util.Logger(ctx, t.log).Error("error saving transaction cache ", "transaction_id", trx.ID)
 	}
 
-	return &presentation.TransactionDTO{
-		TransactionID:   transactionID,
-		Description:     trx.Description,
-		TransactionDate: util.FormatDate(trx.TransactionDate),
-		PurchaseAmount:  trx.PurchaseAmount,
-		Deleted:         trx.Deleted,
+	return presentation.NewTransactionDTO(trx), nil
+}
+
+func (t *TransactionServiceImpl) SaveTransaction(ctx context.Context, transaction *model.Transaction, audit model.Audit) (*presentation.TransactionDTO, error) {
+
+	audit.At = t.now()
+	trx, err := t.repository.SaveTransaction(ctx, transaction, audit)
+	if errors.Is(err, repository.ErrIdempotencyKeyNotReserved) {
+		return nil, model.NewConflictError("the idempotency key expired before the transaction was created, retry the request")
 	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error saving transaction: %w", err)
+	}
+
+	if err := t.cache.Save(ctx, trx.ID, trx); err != nil {
+		util.Logger(ctx, t.log).Error("error saving transaction cache ", "transaction_id", trx.ID)
+	}
+
+	util.Logger(ctx, t.log).Debug("Transaction saved", "transaction_id", trx.ID)
+	return presentation.NewTransactionDTO(trx), nil
 }
 
-func (t *TransactionServiceImpl) SaveTransaction(transaction *model.Transaction) *presentation.TransactionDTO {
+// UpdateTransactionByID updates the transaction when its version is the expected one, any version when it is 0
+func (t *TransactionServiceImpl) UpdateTransactionByID(ctx context.Context, transactionID int64, transaction *model.Transaction, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error) {
 
-	trx, err := t.repository.SaveTransaction(transaction)
+	audit.At = t.now()
+	trx, err := t.repository.UpdateTransaction(ctx, transactionID, transaction, expectedVersion, audit)
 	if err != nil {
-		t.throwError(http.StatusInternalServerError, "error saving transaction")
+		return nil, fmt.Errorf("error updating transaction: %w", err)
 	}
 
-	if err := t.cache.Save(trx.ID, trx); err != nil {
-		t.log.Error("error saving transaction cache ", "transaction_id", trx.ID)
+	if trx == nil {
+		return nil, t.notUpdatedError(ctx, transactionID)
 	}
 
-	t.log.Debug("Transaction saved", "transaction_id", trx.ID)
-	return &presentation.TransactionDTO{
-		TransactionID:   trx.ID,
-		Description:     trx.Description,
-		TransactionDate: util.FormatDate(trx.TransactionDate),
-		PurchaseAmount:  trx.PurchaseAmount,
+	if err := t.cache.Save(ctx, transactionID, trx); err != nil {
+		util.Logger(ctx, t.log).Error("error saving transaction cache ", "transaction_id", trx.ID)
 	}
+
+	util.Logger(ctx, t.log).Debug("Transaction updated", "transaction_id", trx.ID)
+	return presentation.NewTransactionDTO(trx), nil
 }
 
-func (t *TransactionServiceImpl) UpdateTransactionByID(transactionID int64, transaction *model.Transaction) *presentation.TransactionDTO {
+// PatchTransactionByID changes only the fields present in the patch, with the same version check of UpdateTransactionByID
+func (t *TransactionServiceImpl) PatchTransactionByID(ctx context.Context, transactionID int64, patch *model.TransactionPatch, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error) {
 
-	trx, err := t.repository.UpdateTransaction(transactionID, transaction)
+	audit.At = t.now()
+	trx, err := t.repository.PatchTransaction(ctx, transactionID, patch, expectedVersion, audit)
 	if err != nil {
-		t.throwError(http.StatusInternalServerError, "error updating transaction")
+		return nil, fmt.Errorf("error patching transaction: %w", err)
 	}
 
 	if trx == nil {
-		t.throwError(http.StatusNotFound, "transaction not found")
+		return nil, t.notUpdatedError(ctx, transactionID)
 	}
 
-	trx.ID = transactionID
-	if err := t.cache.Save(transactionID, trx); err != nil {
-		t.log.Error("error saving transaction cache ", "transaction_id", trx.ID)
+	if err := t.cache.Save(ctx, transactionID, trx); err != nil {
+		util.Logger(ctx, t.log).Error("error saving transaction cache ", "transaction_id", trx.ID)
 	}
 
-	t.log.Debug("Transaction updated", "transaction_id", trx.ID)
-	return &presentation.TransactionDTO{
-		TransactionID:   transactionID,
-		Description:     trx.Description,
-		TransactionDate: util.FormatDate(trx.TransactionDate),
-		PurchaseAmount:  trx.PurchaseAmount,
-	}
+	util.Logger(ctx, t.log).Debug("Transaction patched", "transaction_id", trx.ID)
+	return presentation.NewTransactionDTO(trx), nil
 }
 
-func (t *TransactionServiceImpl) DeleteTransactionByID(transactionID int64) {
+// DeleteTransactionByID deletes the transaction when its version is the expected one, any version when it is 0
+func (t *TransactionServiceImpl) DeleteTransactionByID(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) error {
 
 	if transactionID <= 0 {
-		t.throwError(http.StatusBadRequest, fmt.Sprintf("invalid transaction id: %d", transactionID))
+		return model.NewValidationError(fmt.Sprintf("invalid transaction id: %d", transactionID))
 	}
 
-	if trx := t.cache.Get(transactionID); trx != nil {
-		t.log.Debug("Transaction found in cache", "transaction_id", transactionID)
+	if trx := t.cache.Get(ctx, transactionID); trx != nil {
+		util.Logger(ctx, t.log).Debug("Transaction found in cache", "transaction_id", transactionID)
 		if trx.Deleted {
-			t.throwError(http.StatusNotFound, "transaction not found")
+			return model.NewNotFoundError("transaction not found")
 		}
 	}
 
-	transaction, err := t.repository.GetTransaction(transactionID)
+	transaction, err := t.repository.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return fmt.Errorf("error deleting transaction: %w", err)
+	}
+
+	if transaction == nil || transaction.Deleted {
+		return model.NewNotFoundError("transaction not found")
+	}
+
+	if expectedVersion != 0 && transaction.Version != expectedVersion {
+		return errVersionMismatch
+	}
+
+	audit.At = t.now()
+	deleted, err := t.repository.LogicalDeleteTransaction(ctx, transactionID, transaction.Version, audit)
+	if err != nil {
+		return fmt.Errorf("error deleting transaction: %w", err)
+	}
+
+	// changed by another request after it was read
+	if deleted == nil {
+		return t.notUpdatedError(ctx, transactionID)
+	}
+
+	if err := t.cache.Save(ctx, transactionID, deleted); err != nil {
+		util.Logger(ctx, t.log).Error("error saving transaction cache ", "transaction_id", transactionID)
+	}
+
+	return nil
+}
+
+// RestoreTransactionByID undeletes the transaction recording the actor of the audit as who restored it, with the same version check of
+// DeleteTransactionByID
+func (t *TransactionServiceImpl) RestoreTransactionByID(ctx context.Context, transactionID int64, expectedVersion int64, audit model.Audit) (*presentation.TransactionDTO, error) {
+
+	if transactionID <= 0 {
+		return nil, model.NewValidationError(fmt.Sprintf("invalid transaction id: %d", transactionID))
+	}
+
+	audit.At = t.now()
+	trx, err := t.repository.RestoreTransaction(ctx, transactionID, expectedVersion, audit)
+	if err != nil {
+		return nil, fmt.Errorf("error restoring transaction: %w", err)
+	}
+
+	if trx == nil {
+		return nil, t.notRestoredError(ctx, transactionID)
+	}
+
+	// replaces the copy cached with deleted = true, the restored version is always newer
+	if err := t.cache.Save(ctx, transactionID, trx); err != nil {
+		util.Logger(ctx, t.log).Error("error saving transaction cache, removing the deleted copy", "transaction_id", transactionID)
+		t.cache.Delete(ctx, transactionID)
+	}
+
+	util.Logger(ctx, t.log).Info("Transaction restored", "transaction_id", transactionID, "restored_by", audit.Actor)
+	return presentation.NewTransactionDTO(trx), nil
+}
+
+func (t *TransactionServiceImpl) notRestoredError(ctx context.Context, transactionID int64) error {
+	transaction, err := t.repository.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return fmt.Errorf("error getting transaction: %w", err)
+	}
+
+	if transaction == nil {
+		return model.NewNotFoundError("transaction not found")
+	}
+
+	if !transaction.Deleted {
+		return model.NewConflictError("transaction is not deleted")
+	}
+
+	return errVersionMismatch
+}
+
+// notUpdatedError tells whether a transaction was not changed because it does not exist or because its version
+// is not the expected one
+func (t *TransactionServiceImpl) notUpdatedError(ctx context.Context, transactionID int64) error {
+	transaction, err := t.repository.GetTransaction(ctx, transactionID)
 	if err != nil {
-		t.throwError(http.StatusInternalServerError, "error deleting transaction")
+		return fmt.Errorf("error getting transaction: %w", err)
 	}
 
 	if transaction == nil || transaction.Deleted {
-		t.throwError(http.StatusNotFound, "transaction not found")
+		return model.NewNotFoundError("transaction not found")
 	}
 
-	_, err = t.repository.LogicalDeleteTransaction(transactionID)
+	return errVersionMismatch
+}
+
+func (t *TransactionServiceImpl) ListTransactions(ctx context.Context, filter *model.TransactionFilter) (*presentation.TransactionPageDTO, error) {
+
+	transactions, total, err := t.repository.ListTransactions(ctx, filter)
 	if err != nil {
-		t.throwError(http.StatusInternalServerError, "error deleting transaction")
+		return nil, fmt.Errorf("error listing transactions: %w", err)
 	}
 
-	transaction.Deleted = true
-	if err := t.cache.Save(transactionID, transaction); err != nil {
-		t.log.Error("error saving transaction cache ", "transaction_id", transactionID)
+	data := make([]presentation.TransactionDTO, 0, len(transactions))
+	for _, trx := range transactions {
+		data = append(data, *presentation.NewTransactionDTO(&trx))
 	}
+
+	util.Logger(ctx, t.log).Debug("Transactions listed", "total", total, "limit", filter.Limit, "offset", filter.Offset)
+	return &presentation.TransactionPageDTO{
+		Data:   data,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
 }
 
-func (s *TransactionServiceImpl) throwError(status int, message string) {
-	panic(presentation.NewApiError(status, message))
+// GetTransactionHistory returns the events of the transaction and the transaction as it was at asOf, now when it is nil
+func (t *TransactionServiceImpl) GetTransactionHistory(ctx context.Context, transactionID int64, asOf *time.Time) (*presentation.TransactionHistoryDTO, error) {
+
+	if transactionID <= 0 {
+		return nil, model.NewValidationError(fmt.Sprintf("invalid transaction id: %d", transactionID))
+	}
+
+	events, err := t.repository.GetTransactionEvents(ctx, transactionID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("error getting transaction history: %w", err)
+	}
+
+	if len(events) == 0 {
+		return nil, model.NewNotFoundError("transaction not found")
+	}
+
+	return presentation.NewTransactionHistoryDTO(transactionID, asOf, events), nil
 }
@@ -1,15 +1,17 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
-	"net/http"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
 	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/util"
 	"github.com/stretchr/testify/assert"
@@ -29,24 +31,25 @@ func Test_TransactionService_GetTransactionByID(t *testing.T) {
 			ID:              int64(1),
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockCache.EXPECT().Save(mockTransaction.ID, &mockTransaction).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(&mockTransaction, nil)
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockCache.EXPECT().Save(gomock.Any(), mockTransaction.ID, &mockTransaction).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&mockTransaction, nil)
 
-		response := transactionService.GetTransactionByID(mockTransaction.ID)
+		response, err := transactionService.GetTransactionByID(context.TODO(), mockTransaction.ID)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 
 		expectedTransaction := &presentation.TransactionDTO{
 			TransactionID:   mockTransaction.ID,
 			Description:     mockTransaction.Description,
 			TransactionDate: util.FormatDate(mockTransaction.TransactionDate),
-			PurchaseAmount:  mockTransaction.PurchaseAmount,
+			PurchaseAmount:  presentation.Amount(mockTransaction.PurchaseAmount),
 		}
 		assert.Equal(t, expectedTransaction, response)
 	})
@@ -56,21 +59,22 @@ func Test_TransactionService_GetTransactionByID(t *testing.T) {
 			ID:              int64(1),
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(&mockTransaction)
-		response := transactionService.GetTransactionByID(mockTransaction.ID)
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(&mockTransaction)
+		response, err := transactionService.GetTransactionByID(context.TODO(), mockTransaction.ID)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 
 		expectedTransaction := &presentation.TransactionDTO{
 			TransactionID:   mockTransaction.ID,
 			Description:     mockTransaction.Description,
 			TransactionDate: util.FormatDate(mockTransaction.TransactionDate),
-			PurchaseAmount:  mockTransaction.PurchaseAmount,
+			PurchaseAmount:  presentation.Amount(mockTransaction.PurchaseAmount),
 		}
 		assert.Equal(t, expectedTransaction, response)
 	})
@@ -80,24 +84,25 @@ func Test_TransactionService_GetTransactionByID(t *testing.T) {
 			ID:              int64(1),
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockCache.EXPECT().Save(mockTransaction.ID, &mockTransaction).Return(errors.New("mock error"))
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(&mockTransaction, nil)
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockCache.EXPECT().Save(gomock.Any(), mockTransaction.ID, &mockTransaction).Return(errors.New("mock error"))
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&mockTransaction, nil)
 
-		response := transactionService.GetTransactionByID(mockTransaction.ID)
+		response, err := transactionService.GetTransactionByID(context.TODO(), mockTransaction.ID)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 
 		expectedTransaction := &presentation.TransactionDTO{
 			TransactionID:   mockTransaction.ID,
 			Description:     mockTransaction.Description,
 			TransactionDate: util.FormatDate(mockTransaction.TransactionDate),
-			PurchaseAmount:  mockTransaction.PurchaseAmount,
+			PurchaseAmount:  presentation.Amount(mockTransaction.PurchaseAmount),
 		}
 		assert.Equal(t, expectedTransaction, response)
 	})
@@ -106,45 +111,48 @@ func Test_TransactionService_GetTransactionByID(t *testing.T) {
 		mockTransaction := model.Transaction{
 			ID: int64(1),
 		}
-		expectedError := presentation.NewApiError(http.StatusNotFound, "transaction not found")
+		expectedError := model.NewNotFoundError("transaction not found")
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(nil, nil)
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(nil, nil)
 
-		// then
-		defer assertPanicApiErrors(t, expectedError)
+		response, err := transactionService.GetTransactionByID(context.TODO(), mockTransaction.ID)
 
-		_ = transactionService.GetTransactionByID(mockTransaction.ID)
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
 	})
 	t.Run("Get transaction by id error getting transaction", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			ID: int64(1),
 		}
-		expectedError := presentation.NewApiError(http.StatusInternalServerError, "error getting transaction")
+		expectedError := fmt.Errorf("error getting transaction: %w", errors.New("mock error"))
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(nil, errors.New("mock error"))
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(nil, errors.New("mock error"))
 
-		// then
-		defer assertPanicApiErrors(t, expectedError)
+		response, err := transactionService.GetTransactionByID(context.TODO(), mockTransaction.ID)
 
-		_ = transactionService.GetTransactionByID(mockTransaction.ID)
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
 	})
 	t.Run("Get transaction by id error invalid transaction id", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			ID: int64(0),
 		}
-		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid transaction id: 0")
-
-		// then
-		defer assertPanicApiErrors(t, expectedError)
+		expectedError := model.NewValidationError("invalid transaction id: 0")
 
 		// when
-		_ = transactionService.GetTransactionByID(mockTransaction.ID)
+		response, err := transactionService.GetTransactionByID(context.TODO(), mockTransaction.ID)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
 	})
 }
 
@@ -155,31 +163,36 @@ func Test_TransactionService_SaveTransaction(t *testing.T) {
 	mockCache := mock_repository.NewMockTransactionCache(mockController)
 
 	transactionService := NewTransactionService(slog.Default(), mockRepository, mockCache)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	transactionService.now = func() time.Time { return now }
+	audit := model.Audit{Actor: "alice", RequestID: "request-1"}
+	recordedAudit := model.Audit{Actor: "alice", RequestID: "request-1", At: now}
 
 	t.Run("Save transaction with success", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 		savedTransaction := mockTransaction
 		savedTransaction.ID = int64(1)
 
 		// when
-		mockRepository.EXPECT().SaveTransaction(&mockTransaction).Return(&savedTransaction, nil)
-		mockCache.EXPECT().Save(savedTransaction.ID, &savedTransaction).Return(nil)
+		mockRepository.EXPECT().SaveTransaction(gomock.Any(), &mockTransaction, recordedAudit).Return(&savedTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), savedTransaction.ID, &savedTransaction).Return(nil)
 
-		response := transactionService.SaveTransaction(&mockTransaction)
+		response, err := transactionService.SaveTransaction(context.TODO(), &mockTransaction, audit)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 
 		expectedTransaction := &presentation.TransactionDTO{
 			TransactionID:   savedTransaction.ID,
 			Description:     savedTransaction.Description,
 			TransactionDate: util.FormatDate(savedTransaction.TransactionDate),
-			PurchaseAmount:  savedTransaction.PurchaseAmount,
+			PurchaseAmount:  presentation.Amount(savedTransaction.PurchaseAmount),
 		}
 		assert.Equal(t, expectedTransaction, response)
 	})
@@ -188,25 +201,26 @@ func Test_TransactionService_SaveTransaction(t *testing.T) {
 		mockTransaction := model.Transaction{
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 		savedTransaction := mockTransaction
 		savedTransaction.ID = int64(1)
 
 		// when
-		mockRepository.EXPECT().SaveTransaction(&mockTransaction).Return(&savedTransaction, nil)
-		mockCache.EXPECT().Save(savedTransaction.ID, &savedTransaction).Return(errors.New("mock error"))
+		mockRepository.EXPECT().SaveTransaction(gomock.Any(), &mockTransaction, recordedAudit).Return(&savedTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), savedTransaction.ID, &savedTransaction).Return(errors.New("mock error"))
 
-		response := transactionService.SaveTransaction(&mockTransaction)
+		response, err := transactionService.SaveTransaction(context.TODO(), &mockTransaction, audit)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 
 		expectedTransaction := &presentation.TransactionDTO{
 			TransactionID:   savedTransaction.ID,
 			Description:     savedTransaction.Description,
 			TransactionDate: util.FormatDate(savedTransaction.TransactionDate),
-			PurchaseAmount:  savedTransaction.PurchaseAmount,
+			PurchaseAmount:  presentation.Amount(savedTransaction.PurchaseAmount),
 		}
 		assert.Equal(t, expectedTransaction, response)
 	})
@@ -215,17 +229,36 @@ func Test_TransactionService_SaveTransaction(t *testing.T) {
 		mockTransaction := model.Transaction{
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
-		expectedError := presentation.NewApiError(http.StatusInternalServerError, "error saving transaction")
+		expectedError := fmt.Errorf("error saving transaction: %w", errors.New("mock error"))
 
 		// when
-		mockRepository.EXPECT().SaveTransaction(&mockTransaction).Return(nil, errors.New("mock error"))
+		mockRepository.EXPECT().SaveTransaction(gomock.Any(), &mockTransaction, recordedAudit).Return(nil, errors.New("mock error"))
+
+		response, err := transactionService.SaveTransaction(context.TODO(), &mockTransaction, audit)
 
 		// then
-		defer assertPanicApiErrors(t, expectedError)
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
+	})
+	t.Run("Save transaction with error idempotency key no longer reserved", func(t *testing.T) {
+		// given
+		mockTransaction := model.Transaction{
+			Description:     "mock description",
+			TransactionDate: time.Now(),
+			PurchaseAmount:  100,
+		}
+
+		// when
+		mockRepository.EXPECT().SaveTransaction(gomock.Any(), &mockTransaction, recordedAudit).Return(nil, repository.ErrIdempotencyKeyNotReserved)
+
+		response, err := transactionService.SaveTransaction(context.TODO(), &mockTransaction, audit)
 
-		_ = transactionService.SaveTransaction(&mockTransaction)
+		// then
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, model.ErrConflict)
+		assert.EqualError(t, err, "the idempotency key expired before the transaction was created, retry the request")
 	})
 }
 
@@ -236,31 +269,36 @@ func Test_TransactionService_UpdateTransactionByID(t *testing.T) {
 	mockCache := mock_repository.NewMockTransactionCache(mockController)
 
 	transactionService := NewTransactionService(slog.Default(), mockRepository, mockCache)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	transactionService.now = func() time.Time { return now }
+	audit := model.Audit{Actor: "alice", RequestID: "request-1"}
+	recordedAudit := model.Audit{Actor: "alice", RequestID: "request-1", At: now}
 
 	t.Run("Update transaction by id with success", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 		updatedTransaction := mockTransaction
 		updatedTransaction.ID = int64(1)
 
 		// when
-		mockRepository.EXPECT().UpdateTransaction(updatedTransaction.ID, &mockTransaction).Return(&updatedTransaction, nil)
-		mockCache.EXPECT().Save(updatedTransaction.ID, &updatedTransaction).Return(nil)
+		mockRepository.EXPECT().UpdateTransaction(gomock.Any(), updatedTransaction.ID, &mockTransaction, int64(0), recordedAudit).Return(&updatedTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), updatedTransaction.ID, &updatedTransaction).Return(nil)
 
-		response := transactionService.UpdateTransactionByID(updatedTransaction.ID, &mockTransaction)
+		response, err := transactionService.UpdateTransactionByID(context.TODO(), updatedTransaction.ID, &mockTransaction, 0, audit)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 
 		expectedTransaction := &presentation.TransactionDTO{
 			TransactionID:   updatedTransaction.ID,
 			Description:     updatedTransaction.Description,
 			TransactionDate: util.FormatDate(updatedTransaction.TransactionDate),
-			PurchaseAmount:  updatedTransaction.PurchaseAmount,
+			PurchaseAmount:  presentation.Amount(updatedTransaction.PurchaseAmount),
 		}
 		assert.Equal(t, expectedTransaction, response)
 	})
@@ -269,25 +307,26 @@ func Test_TransactionService_UpdateTransactionByID(t *testing.T) {
 		mockTransaction := model.Transaction{
 			Description:     "mock description",
 			TransactionDate: time.Now(),
-			PurchaseAmount:  1.0,
+			PurchaseAmount:  100,
 		}
 		updatedTransaction := mockTransaction
 		updatedTransaction.ID = int64(1)
 
 		// when
-		mockRepository.EXPECT().UpdateTransaction(updatedTransaction.ID, &mockTransaction).Return(&updatedTransaction, nil)
-		mockCache.EXPECT().Save(updatedTransaction.ID, &updatedTransaction).Return(errors.New("mock error"))
+		mockRepository.EXPECT().UpdateTransaction(gomock.Any(), updatedTransaction.ID, &mockTransaction, int64(0), recordedAudit).Return(&updatedTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), updatedTransaction.ID, &updatedTransaction).Return(errors.New("mock error"))
 
-		response := transactionService.UpdateTransactionByID(updatedTransaction.ID, &mockTransaction)
+		response, err := transactionService.UpdateTransactionByID(context.TODO(), updatedTransaction.ID, &mockTransaction, 0, audit)
 
 		// then
+		assert.NoError(t, err)
 		assert.NotNil(t, response)
 
 		expectedTransaction := &presentation.TransactionDTO{
 			TransactionID:   updatedTransaction.ID,
 			Description:     updatedTransaction.Description,
 			TransactionDate: util.FormatDate(updatedTransaction.TransactionDate),
-			PurchaseAmount:  updatedTransaction.PurchaseAmount,
+			PurchaseAmount:  presentation.Amount(updatedTransaction.PurchaseAmount),
 		}
 		assert.Equal(t, expectedTransaction, response)
 	})
@@ -296,30 +335,116 @@ func Test_TransactionService_UpdateTransactionByID(t *testing.T) {
 		mockTransaction := model.Transaction{
 			ID: int64(1),
 		}
-		expectedError := presentation.NewApiError(http.StatusNotFound, "transaction not found")
+		expectedError := model.NewNotFoundError("transaction not found")
 
 		// when
-		mockRepository.EXPECT().UpdateTransaction(mockTransaction.ID, &mockTransaction).Return(nil, nil)
+		mockRepository.EXPECT().UpdateTransaction(gomock.Any(), mockTransaction.ID, &mockTransaction, int64(0), recordedAudit).Return(nil, nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(nil, nil)
+
+		response, err := transactionService.UpdateTransactionByID(context.TODO(), mockTransaction.ID, &mockTransaction, 0, audit)
 
 		// then
-		defer assertPanicApiErrors(t, expectedError)
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
+	})
+	t.Run("Update transaction by id error version does not match", func(t *testing.T) {
+		// given
+		mockTransaction := model.Transaction{
+			ID: int64(1),
+		}
+		expectedError := model.NewPreconditionFailedError("transaction was changed, If-Match does not match its current version")
 
-		_ = transactionService.UpdateTransactionByID(mockTransaction.ID, &mockTransaction)
+		// when
+		mockRepository.EXPECT().UpdateTransaction(gomock.Any(), mockTransaction.ID, &mockTransaction, int64(2), recordedAudit).Return(nil, nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&model.Transaction{ID: 1, Version: 3}, nil)
+
+		response, err := transactionService.UpdateTransactionByID(context.TODO(), mockTransaction.ID, &mockTransaction, 2, audit)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
 	})
 	t.Run("Update transaction by id error updating transaction", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			ID: int64(1),
 		}
-		expectedError := presentation.NewApiError(http.StatusInternalServerError, "error updating transaction")
+		expectedError := fmt.Errorf("error updating transaction: %w", errors.New("mock error"))
+
+		// when
+		mockRepository.EXPECT().UpdateTransaction(gomock.Any(), mockTransaction.ID, &mockTransaction, int64(0), recordedAudit).Return(nil, errors.New("mock error"))
+
+		response, err := transactionService.UpdateTransactionByID(context.TODO(), mockTransaction.ID, &mockTransaction, 0, audit)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
+	})
+}
+
+func Test_TransactionService_PatchTransactionByID(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+	mockCache := mock_repository.NewMockTransactionCache(mockController)
+
+	transactionService := NewTransactionService(slog.Default(), mockRepository, mockCache)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	transactionService.now = func() time.Time { return now }
+	audit := model.Audit{Actor: "alice", RequestID: "request-1"}
+	recordedAudit := model.Audit{Actor: "alice", RequestID: "request-1", At: now}
+	description := "fixed typo"
+	patch := &model.TransactionPatch{Description: &description}
+
+	t.Run("Patch transaction by id with success", func(t *testing.T) {
+		// given
+		patchedTransaction := &model.Transaction{
+			ID:              int64(1),
+			Description:     description,
+			TransactionDate: time.Now(),
+			PurchaseAmount:  100,
+			Version:         2,
+		}
+
+		// when
+		mockRepository.EXPECT().PatchTransaction(gomock.Any(), int64(1), patch, int64(1), recordedAudit).Return(patchedTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), int64(1), patchedTransaction).Return(nil)
 
+		response, err := transactionService.PatchTransactionByID(context.TODO(), 1, patch, 1, audit)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, &presentation.TransactionDTO{
+			TransactionID:   1,
+			Description:     description,
+			TransactionDate: util.FormatDate(patchedTransaction.TransactionDate),
+			PurchaseAmount:  100,
+			Version:         2,
+		}, response)
+	})
+	t.Run("Patch transaction by id error transaction not found", func(t *testing.T) {
 		// when
-		mockRepository.EXPECT().UpdateTransaction(mockTransaction.ID, &mockTransaction).Return(nil, errors.New("mock error"))
+		mockRepository.EXPECT().PatchTransaction(gomock.Any(), int64(1), patch, int64(0), recordedAudit).Return(nil, nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), int64(1)).Return(&model.Transaction{ID: 1, Deleted: true}, nil)
+
+		response, err := transactionService.PatchTransactionByID(context.TODO(), 1, patch, 0, audit)
 
 		// then
-		defer assertPanicApiErrors(t, expectedError)
+		assert.Nil(t, response)
+		assert.Equal(t, model.NewNotFoundError("transaction not found"), err)
+	})
+	t.Run("Patch transaction by id error patching transaction", func(t *testing.T) {
+		// given
+		expectedError := fmt.Errorf("error patching transaction: %w", errors.New("mock error"))
 
-		_ = transactionService.UpdateTransactionByID(mockTransaction.ID, &mockTransaction)
+		// when
+		mockRepository.EXPECT().PatchTransaction(gomock.Any(), int64(1), patch, int64(0), recordedAudit).Return(nil, errors.New("mock error"))
+
+		response, err := transactionService.PatchTransactionByID(context.TODO(), 1, patch, 0, audit)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
 	})
 }
 
@@ -330,6 +455,10 @@ func Test_TransactionService_DeleteTransactionByID(t *testing.T) {
 	mockCache := mock_repository.NewMockTransactionCache(mockController)
 
 	transactionService := NewTransactionService(slog.Default(), mockRepository, mockCache)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	transactionService.now = func() time.Time { return now }
+	audit := model.Audit{Actor: "alice", RequestID: "request-1"}
+	recordedAudit := model.Audit{Actor: "alice", RequestID: "request-1", At: now}
 
 	t.Run("Delete transaction by id with success", func(t *testing.T) {
 		// given
@@ -338,15 +467,14 @@ func Test_TransactionService_DeleteTransactionByID(t *testing.T) {
 		}
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(&mockTransaction, nil)
-		mockRepository.EXPECT().LogicalDeleteTransaction(mockTransaction.ID).Return(&mockTransaction.ID, nil)
-		mockCache.EXPECT().Save(mockTransaction.ID, &mockTransaction).Return(nil)
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&mockTransaction, nil)
+		mockRepository.EXPECT().LogicalDeleteTransaction(gomock.Any(), mockTransaction.ID, int64(0), recordedAudit).Return(&mockTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), mockTransaction.ID, &mockTransaction).Return(nil)
 
 		// then
-		assert.NotPanics(t, func() {
-			transactionService.DeleteTransactionByID(mockTransaction.ID)
-		})
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 0, audit)
+		assert.NoError(t, err)
 	})
 	t.Run("Delete transaction by id with success but error on save cache", func(t *testing.T) {
 		// given
@@ -355,14 +483,13 @@ func Test_TransactionService_DeleteTransactionByID(t *testing.T) {
 		}
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(&mockTransaction, nil)
-		mockRepository.EXPECT().LogicalDeleteTransaction(mockTransaction.ID).Return(&mockTransaction.ID, nil)
-		mockCache.EXPECT().Save(mockTransaction.ID, &mockTransaction).Return(errors.New("mock error"))
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&mockTransaction, nil)
+		mockRepository.EXPECT().LogicalDeleteTransaction(gomock.Any(), mockTransaction.ID, int64(0), recordedAudit).Return(&mockTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), mockTransaction.ID, &mockTransaction).Return(errors.New("mock error"))
 
-		assert.NotPanics(t, func() {
-			transactionService.DeleteTransactionByID(mockTransaction.ID)
-		})
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 0, audit)
+		assert.NoError(t, err)
 	})
 	t.Run("Delete transaction by id error transaction already deleted in cache", func(t *testing.T) {
 		// given
@@ -370,82 +497,347 @@ func Test_TransactionService_DeleteTransactionByID(t *testing.T) {
 			ID:      int64(1),
 			Deleted: true,
 		}
-		expectedError := presentation.NewApiError(http.StatusNotFound, "transaction not found")
+		expectedError := model.NewNotFoundError("transaction not found")
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(&mockTransaction)
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(&mockTransaction)
 
-		// then
-		defer assertPanicApiErrors(t, expectedError)
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 0, audit)
 
-		transactionService.DeleteTransactionByID(mockTransaction.ID)
+		// then
+		assert.Equal(t, expectedError, err)
 	})
 	t.Run("Delete transaction by id error transaction not found in db", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			ID: int64(1),
 		}
-		expectedError := presentation.NewApiError(http.StatusNotFound, "transaction not found")
+		expectedError := model.NewNotFoundError("transaction not found")
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(nil, nil)
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(nil, nil)
 
-		// then
-		defer assertPanicApiErrors(t, expectedError)
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 0, audit)
 
-		transactionService.DeleteTransactionByID(mockTransaction.ID)
+		// then
+		assert.Equal(t, expectedError, err)
 	})
 	t.Run("Delete transaction by id error deleting transaction on get transaction", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			ID: int64(1),
 		}
-		expectedError := presentation.NewApiError(http.StatusInternalServerError, "error deleting transaction")
+		expectedError := fmt.Errorf("error deleting transaction: %w", errors.New("mock error"))
 
 		// when
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(nil, errors.New("mock error"))
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(nil, errors.New("mock error"))
+
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 0, audit)
 
 		// then
-		defer assertPanicApiErrors(t, expectedError)
+		assert.Equal(t, expectedError, err)
+	})
+	t.Run("Delete transaction by id error version does not match", func(t *testing.T) {
+		// given
+		mockTransaction := model.Transaction{
+			ID:      int64(1),
+			Version: 3,
+		}
+		expectedError := model.NewPreconditionFailedError("transaction was changed, If-Match does not match its current version")
+
+		// when
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&mockTransaction, nil)
 
-		transactionService.DeleteTransactionByID(mockTransaction.ID)
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 2, audit)
+
+		// then
+		assert.Equal(t, expectedError, err)
 	})
-	t.Run("Delete transaction by id error invalid transaction id", func(t *testing.T) {
+	t.Run("Delete transaction by id error changed after it was read", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
-			ID: int64(0),
+			ID:      int64(1),
+			Version: 2,
 		}
-		expectedError := presentation.NewApiError(http.StatusBadRequest, "invalid transaction id: 0")
+		expectedError := model.NewPreconditionFailedError("transaction was changed, If-Match does not match its current version")
+
+		// when
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&mockTransaction, nil)
+		mockRepository.EXPECT().LogicalDeleteTransaction(gomock.Any(), mockTransaction.ID, int64(2), recordedAudit).Return(nil, nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&model.Transaction{ID: 1, Version: 3}, nil)
+
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 2, audit)
 
 		// then
-		defer assertPanicApiErrors(t, expectedError)
+		assert.Equal(t, expectedError, err)
+	})
+	t.Run("Delete transaction by id error invalid transaction id", func(t *testing.T) {
+		// given
+		mockTransaction := model.Transaction{
+			ID: int64(0),
+		}
+		expectedError := model.NewValidationError("invalid transaction id: 0")
 
 		// when
-		transactionService.DeleteTransactionByID(mockTransaction.ID)
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 0, audit)
+
+		// then
+		assert.Equal(t, expectedError, err)
 	})
 	t.Run("Delete transaction by id error on logical delete repository", func(t *testing.T) {
 		// given
 		mockTransaction := model.Transaction{
 			ID: int64(1),
 		}
-		expectedError := presentation.NewApiError(http.StatusInternalServerError, "error deleting transaction")
+		expectedError := fmt.Errorf("error deleting transaction: %w", errors.New("mock error"))
+
+		mockCache.EXPECT().Get(gomock.Any(), mockTransaction.ID).Return(nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), mockTransaction.ID).Return(&mockTransaction, nil)
+		mockRepository.EXPECT().LogicalDeleteTransaction(gomock.Any(), mockTransaction.ID, int64(0), recordedAudit).Return(nil, errors.New("mock error"))
+
+		// when
+		err := transactionService.DeleteTransactionByID(context.TODO(), mockTransaction.ID, 0, audit)
+
+		// then
+		assert.Equal(t, expectedError, err)
+	})
+}
+
+func Test_TransactionService_RestoreTransactionByID(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+	mockCache := mock_repository.NewMockTransactionCache(mockController)
+
+	transactionService := NewTransactionService(slog.Default(), mockRepository, mockCache)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	transactionService.now = func() time.Time { return now }
+	audit := model.Audit{Actor: "alice", RequestID: "request-1"}
+	recordedAudit := model.Audit{Actor: "alice", RequestID: "request-1", At: now}
+
+	t.Run("Restore transaction by id replaces the deleted copy in cache", func(t *testing.T) {
+		// given
+		restoredTransaction := &model.Transaction{
+			ID:              int64(1),
+			Description:     "mock description",
+			TransactionDate: now,
+			PurchaseAmount:  100,
+			Version:         4,
+			RestoredAt:      &now,
+			RestoredBy:      "alice",
+		}
+
+		// when
+		mockRepository.EXPECT().RestoreTransaction(gomock.Any(), int64(1), int64(3), recordedAudit).Return(restoredTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), int64(1), restoredTransaction).Return(nil)
+
+		response, err := transactionService.RestoreTransactionByID(context.TODO(), 1, 3, audit)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, presentation.NewTransactionDTO(restoredTransaction), response)
+		assert.False(t, response.Deleted)
+		assert.Equal(t, "alice", response.RestoredBy)
+	})
+	t.Run("Restore transaction by id removes the deleted copy when the cache fails", func(t *testing.T) {
+		// given
+		restoredTransaction := &model.Transaction{ID: int64(1), Version: 4, RestoredAt: &now, RestoredBy: "alice"}
+
+		// when
+		mockRepository.EXPECT().RestoreTransaction(gomock.Any(), int64(1), int64(0), recordedAudit).Return(restoredTransaction, nil)
+		mockCache.EXPECT().Save(gomock.Any(), int64(1), restoredTransaction).Return(errors.New("mock error"))
+		mockCache.EXPECT().Delete(gomock.Any(), int64(1))
+
+		response, err := transactionService.RestoreTransactionByID(context.TODO(), 1, 0, audit)
+
+		// then
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+	})
+	t.Run("Restore transaction by id error transaction not deleted", func(t *testing.T) {
+		// when
+		mockRepository.EXPECT().RestoreTransaction(gomock.Any(), int64(1), int64(0), recordedAudit).Return(nil, nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), int64(1)).Return(&model.Transaction{ID: 1, Version: 2}, nil)
+
+		response, err := transactionService.RestoreTransactionByID(context.TODO(), 1, 0, audit)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, model.NewConflictError("transaction is not deleted"), err)
+	})
+	t.Run("Restore transaction by id error version does not match", func(t *testing.T) {
+		// when
+		mockRepository.EXPECT().RestoreTransaction(gomock.Any(), int64(1), int64(2), recordedAudit).Return(nil, nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), int64(1)).Return(&model.Transaction{ID: 1, Deleted: true, Version: 3}, nil)
+
+		response, err := transactionService.RestoreTransactionByID(context.TODO(), 1, 2, audit)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, model.NewPreconditionFailedError("transaction was changed, If-Match does not match its current version"), err)
+	})
+	t.Run("Restore transaction by id error transaction not found", func(t *testing.T) {
+		// when
+		mockRepository.EXPECT().RestoreTransaction(gomock.Any(), int64(9), int64(0), recordedAudit).Return(nil, nil)
+		mockRepository.EXPECT().GetTransaction(gomock.Any(), int64(9)).Return(nil, nil)
+
+		response, err := transactionService.RestoreTransactionByID(context.TODO(), 9, 0, audit)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, model.NewNotFoundError("transaction not found"), err)
+	})
+}
+
+func Test_TransactionService_GetTransactionHistory(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+	mockCache := mock_repository.NewMockTransactionCache(mockController)
+
+	transactionService := NewTransactionService(slog.Default(), mockRepository, mockCache)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	created := model.Transaction{ID: 1, Description: "created", TransactionDate: now, PurchaseAmount: 100, Version: 1}
+	updated := model.Transaction{ID: 1, Description: "updated", TransactionDate: now, PurchaseAmount: 100, Version: 2}
+
+	t.Run("Get transaction history reconstructs the transaction from the last event", func(t *testing.T) {
+		// given
+		asOf := now.Add(time.Hour)
+		events := []model.TransactionEvent{
+			{ID: 1, TransactionID: 1, Operation: model.OperationCreate, Actor: "alice", OccurredAt: now, NewValue: &created},
+			{ID: 2, TransactionID: 1, Operation: model.OperationUpdate, Actor: "bob", RequestID: "request-2", OccurredAt: now.Add(time.Minute), OldValue: &created, NewValue: &updated},
+		}
+
+		// when
+		mockRepository.EXPECT().GetTransactionEvents(gomock.Any(), int64(1), &asOf).Return(events, nil)
+
+		response, err := transactionService.GetTransactionHistory(context.TODO(), 1, &asOf)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, presentation.NewTransactionDTO(&updated), response.Transaction)
+		assert.Len(t, response.Events, 2)
+		assert.Equal(t, "update", response.Events[1].Operation)
+		assert.Equal(t, presentation.NewTransactionDTO(&created), response.Events[1].OldValue)
+	})
+	t.Run("Get transaction history of a purged transaction", func(t *testing.T) {
+		// given
+		events := []model.TransactionEvent{
+			{ID: 1, TransactionID: 1, Operation: model.OperationCreate, Actor: "alice", OccurredAt: now, NewValue: &created},
+			{ID: 2, TransactionID: 1, Operation: model.OperationPurge, Actor: "purge-job", OccurredAt: now.Add(time.Minute), OldValue: &created},
+		}
 
-		mockCache.EXPECT().Get(mockTransaction.ID).Return(nil)
-		mockRepository.EXPECT().GetTransaction(mockTransaction.ID).Return(&mockTransaction, nil)
-		mockRepository.EXPECT().LogicalDeleteTransaction(mockTransaction.ID).Return(nil, errors.New("mock error"))
+		// when
+		mockRepository.EXPECT().GetTransactionEvents(gomock.Any(), int64(1), nil).Return(events, nil)
+
+		response, err := transactionService.GetTransactionHistory(context.TODO(), 1, nil)
 
 		// then
-		defer assertPanicApiErrors(t, expectedError)
+		assert.NoError(t, err)
+		assert.Nil(t, response.Transaction)
+		assert.Len(t, response.Events, 2)
+	})
+	t.Run("Get transaction history error no event until as of", func(t *testing.T) {
+		// given
+		asOf := now.Add(-time.Hour)
 
 		// when
-		transactionService.DeleteTransactionByID(mockTransaction.ID)
+		mockRepository.EXPECT().GetTransactionEvents(gomock.Any(), int64(1), &asOf).Return([]model.TransactionEvent{}, nil)
+
+		response, err := transactionService.GetTransactionHistory(context.TODO(), 1, &asOf)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, model.NewNotFoundError("transaction not found"), err)
+	})
+	t.Run("Get transaction history error on repository", func(t *testing.T) {
+		// when
+		mockRepository.EXPECT().GetTransactionEvents(gomock.Any(), int64(1), nil).Return(nil, errors.New("mock error"))
+
+		response, err := transactionService.GetTransactionHistory(context.TODO(), 1, nil)
+
+		// then
+		assert.Nil(t, response)
+		assert.EqualError(t, err, "error getting transaction history: mock error")
 	})
 }
 
-func assertPanicApiErrors(t *testing.T, expectedError *presentation.ApiError) {
-	if r := recover(); r != nil {
-		assert.Equal(t, expectedError, r)
-	}
+func Test_TransactionService_ListTransactions(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockTransactionRepository(mockController)
+	mockCache := mock_repository.NewMockTransactionCache(mockController)
+
+	transactionService := NewTransactionService(slog.Default(), mockRepository, mockCache)
+
+	t.Run("List transactions with success", func(t *testing.T) {
+		// given
+		filter := &model.TransactionFilter{Limit: 2, Offset: 0}
+		mockTransactions := []model.Transaction{
+			{ID: 1, Description: "first", TransactionDate: time.Now(), PurchaseAmount: 100},
+			{ID: 2, Description: "second", TransactionDate: time.Now(), PurchaseAmount: 200, Deleted: true},
+		}
+
+		// when
+		mockRepository.EXPECT().ListTransactions(gomock.Any(), filter).Return(mockTransactions, int64(5), nil)
+
+		response, err := transactionService.ListTransactions(context.TODO(), filter)
+
+		// then
+		assert.NoError(t, err)
+		expectedPage := &presentation.TransactionPageDTO{
+			Data: []presentation.TransactionDTO{
+				{
+					TransactionID:   1,
+					Description:     "first",
+					TransactionDate: util.FormatDate(mockTransactions[0].TransactionDate),
+					PurchaseAmount:  100,
+				},
+				{
+					TransactionID:   2,
+					Description:     "second",
+					TransactionDate: util.FormatDate(mockTransactions[1].TransactionDate),
+					PurchaseAmount:  200,
+					Deleted:         true,
+				},
+			},
+			Total:  5,
+			Limit:  2,
+			Offset: 0,
+		}
+		assert.Equal(t, expectedPage, response)
+	})
+	t.Run("List transactions with success empty page", func(t *testing.T) {
+		// given
+		filter := &model.TransactionFilter{Limit: 2, Offset: 10}
+
+		// when
+		mockRepository.EXPECT().ListTransactions(gomock.Any(), filter).Return([]model.Transaction{}, int64(5), nil)
+
+		response, err := transactionService.ListTransactions(context.TODO(), filter)
+
+		// then
+		assert.NoError(t, err)
+		assert.NotNil(t, response.Data)
+		assert.Empty(t, response.Data)
+		assert.Equal(t, int64(5), response.Total)
+	})
+	t.Run("List transactions error on repository", func(t *testing.T) {
+		// given
+		filter := &model.TransactionFilter{Limit: 2}
+		expectedError := fmt.Errorf("error listing transactions: %w", errors.New("mock error"))
+
+		// when
+		mockRepository.EXPECT().ListTransactions(gomock.Any(), filter).Return(nil, int64(0), errors.New("mock error"))
+
+		response, err := transactionService.ListTransactions(context.TODO(), filter)
+
+		// then
+		assert.Nil(t, response)
+		assert.Equal(t, expectedError, err)
+	})
 }
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+const (
+	treasurySyncPageSize = 1000
+	// treasuryDatasetStartDate is used as the first record date when the local table is empty
+	treasuryDatasetStartDate = "2001-01-01"
+)
+
+type TreasurySyncService interface {
+	Sync(ctx context.Context) (int, error)
+}
+
+//go:generate mockgen -source=./treasury_sync_service.go -destination=./mocks/treasury_sync_service_mock.go
+
+type TreasurySyncServiceImpl struct {
+	treasuryRepository     repository.TreasuryDatasetRepository
+	exchangeRateRepository repository.ExchangeRateRepository
+	interval               time.Duration
+	log                    *slog.Logger
+}
+
+func NewTreasurySyncService(
+	treasuryRepository repository.TreasuryDatasetRepository,
+	exchangeRateRepository repository.ExchangeRateRepository,
+	interval time.Duration,
+	log *slog.Logger) *TreasurySyncServiceImpl {
+
+	return &TreasurySyncServiceImpl{
+		treasuryRepository:     treasuryRepository,
+		exchangeRateRepository: exchangeRateRepository,
+		interval:               interval,
+		log:                    log,
+	}
+}
+
+// Sync pages through the Treasury dataset starting from the last record date stored locally and upserts
+// every rate found, returning how many rates were saved
+func (s *TreasurySyncServiceImpl) Sync(ctx context.Context) (int, error) {
+	recordDateFrom, err := s.exchangeRateRepository.GetLastRecordDate()
+	if err != nil {
+		return 0, err
+	}
+
+	if recordDateFrom == "" {
+		recordDateFrom = treasuryDatasetStartDate
+	}
+
+	saved := 0
+	for pageNumber := 1; ; pageNumber++ {
+		if err := ctx.Err(); err != nil {
+			return saved, err
+		}
+
+		page, err := s.treasuryRepository.GetExchangeRatesPage(ctx, recordDateFrom, pageNumber, treasurySyncPageSize)
+		if err != nil {
+			return saved, err
+		}
+
+		if err := s.exchangeRateRepository.SaveExchangeRates(page.Data); err != nil {
+			return saved, err
+		}
+
+		saved += len(page.Data)
+		if !s.hasNextPage(page, pageNumber) {
+			break
+		}
+	}
+
+	return saved, nil
+}
+
+// Start runs the sync right away and then once per interval until the context is done
+func (s *TreasurySyncServiceImpl) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if saved, err := s.Sync(ctx); err != nil {
+			s.log.Error("error synchronizing treasury exchange rates", "saved", saved, "error", err)
+		} else {
+			s.log.Info("Treasury exchange rates synchronized", "saved", saved, "duration", time.Since(start).String())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hasNextPage uses the next link when the response has links, otherwise the total of pages
+func (s *TreasurySyncServiceImpl) hasNextPage(page *model.TreasuryRatesExchange, pageNumber int) bool {
+	if next, found := page.Links["next"]; found {
+		return next != nil && *next != ""
+	}
+
+	return page.Meta != nil && pageNumber < page.Meta.TotalPages
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TreasurySyncService_Sync(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	treasuryRepository := mock_repository.NewMockTreasuryDatasetRepository(mockCtrl)
+	exchangeRateRepository := mock_repository.NewMockExchangeRateRepository(mockCtrl)
+	ctx := context.Background()
+
+	service := NewTreasurySyncService(treasuryRepository, exchangeRateRepository, time.Hour, slog.Default())
+
+	firstPage := []model.Data{{RecordDate: "2024-09-30", Country: "Brazil", ExchangeRate: "5.434"}}
+	secondPage := []model.Data{{RecordDate: "2024-09-30", Country: "Canada", ExchangeRate: "1.351"}}
+	nextLink := "&page%5Bnumber%5D=2&page%5Bsize%5D=1000"
+
+	t.Run("Sync all pages using next link", func(t *testing.T) {
+		// given
+		exchangeRateRepository.EXPECT().GetLastRecordDate().Return("2024-06-30", nil)
+		treasuryRepository.EXPECT().GetExchangeRatesPage(ctx, "2024-06-30", 1, treasurySyncPageSize).Return(&model.TreasuryRatesExchange{
+			Data:  firstPage,
+			Links: map[string]*string{"next": &nextLink},
+		}, nil)
+		exchangeRateRepository.EXPECT().SaveExchangeRates(firstPage).Return(nil)
+		treasuryRepository.EXPECT().GetExchangeRatesPage(ctx, "2024-06-30", 2, treasurySyncPageSize).Return(&model.TreasuryRatesExchange{
+			Data:  secondPage,
+			Links: map[string]*string{"next": nil},
+		}, nil)
+		exchangeRateRepository.EXPECT().SaveExchangeRates(secondPage).Return(nil)
+
+		// when
+		saved, err := service.Sync(ctx)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 2, saved)
+	})
+
+	t.Run("Sync from the dataset start using total pages", func(t *testing.T) {
+		// given
+		exchangeRateRepository.EXPECT().GetLastRecordDate().Return("", nil)
+		treasuryRepository.EXPECT().GetExchangeRatesPage(ctx, treasuryDatasetStartDate, 1, treasurySyncPageSize).Return(&model.TreasuryRatesExchange{
+			Data: firstPage,
+			Meta: &model.Meta{TotalPages: 1},
+		}, nil)
+		exchangeRateRepository.EXPECT().SaveExchangeRates(firstPage).Return(nil)
+
+		// when
+		saved, err := service.Sync(ctx)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 1, saved)
+	})
+
+	t.Run("Sync error on treasury api keeps the pages already saved", func(t *testing.T) {
+		// given
+		exchangeRateRepository.EXPECT().GetLastRecordDate().Return("2024-06-30", nil)
+		treasuryRepository.EXPECT().GetExchangeRatesPage(ctx, "2024-06-30", 1, treasurySyncPageSize).Return(&model.TreasuryRatesExchange{
+			Data:  firstPage,
+			Links: map[string]*string{"next": &nextLink},
+		}, nil)
+		exchangeRateRepository.EXPECT().SaveExchangeRates(firstPage).Return(nil)
+		treasuryRepository.EXPECT().GetExchangeRatesPage(ctx, "2024-06-30", 2, treasurySyncPageSize).Return(nil, errors.New("mock error"))
+
+		// when
+		saved, err := service.Sync(ctx)
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, 1, saved)
+	})
+
+	t.Run("Sync error getting last record date", func(t *testing.T) {
+		// given
+		exchangeRateRepository.EXPECT().GetLastRecordDate().Return("", errors.New("mock error"))
+
+		// when
+		saved, err := service.Sync(ctx)
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, 0, saved)
+	})
+
+	t.Run("Sync error on save exchange rates", func(t *testing.T) {
+		// given
+		exchangeRateRepository.EXPECT().GetLastRecordDate().Return("2024-06-30", nil)
+		treasuryRepository.EXPECT().GetExchangeRatesPage(ctx, "2024-06-30", 1, treasurySyncPageSize).Return(&model.TreasuryRatesExchange{
+			Data: firstPage,
+		}, nil)
+		exchangeRateRepository.EXPECT().SaveExchangeRates(firstPage).Return(errors.New("mock error"))
+
+		// when
+		saved, err := service.Sync(ctx)
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, 0, saved)
+	})
+}
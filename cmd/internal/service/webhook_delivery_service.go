@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+const (
+	// WebhookTimestampHeader is when the delivery was signed, the subscribers reject old timestamps to prevent replays
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader is sha256= followed by the hex HMAC-SHA256 of "<timestamp>.<body>" with the webhook secret
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery-ID"
+
+	webhookBatchSize = 100
+	// the retries of a delivery wait 10s, 20s, 40s... up to webhookMaxBackoff
+	webhookBaseBackoff = 10 * time.Second
+	webhookMaxBackoff  = time.Hour
+)
+
+type WebhookDeliveryService interface {
+	Deliver(ctx context.Context) (int, error)
+}
+
+//go:generate mockgen -source=./webhook_delivery_service.go -destination=./mocks/webhook_delivery_service_mock.go
+
+type WebhookDeliveryServiceImpl struct {
+	repository   repository.WebhookRepository
+	client       repository.WebhookClient
+	interval     time.Duration
+	maxAttempts  int
+	disableAfter int
+	log          *slog.Logger
+	now          func() time.Time
+}
+
+func NewWebhookDeliveryService(
+	repository repository.WebhookRepository,
+	client repository.WebhookClient,
+	interval time.Duration,
+	maxAttempts int,
+	disableAfter int,
+	log *slog.Logger) *WebhookDeliveryServiceImpl {
+
+	return &WebhookDeliveryServiceImpl{
+		repository:   repository,
+		client:       client,
+		interval:     interval,
+		maxAttempts:  maxAttempts,
+		disableAfter: disableAfter,
+		log:          log,
+		now:          time.Now,
+	}
+}
+
+// Deliver sends the pending deliveries and returns how many were accepted by the subscribers
+func (w *WebhookDeliveryServiceImpl) Deliver(ctx context.Context) (int, error) {
+	deliveries, err := w.repository.GetPendingDeliveries(w.now(), webhookBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("error getting pending webhook deliveries: %w", err)
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sent, err := w.send(ctx, &delivery)
+		if err != nil {
+			return delivered, fmt.Errorf("error updating webhook delivery %d: %w", delivery.ID, err)
+		}
+
+		if sent {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// send posts the signed delivery and records the result, a failed delivery is retried with backoff until it
+// reaches the max attempts, and the webhook is disabled after too many consecutive failures
+func (w *WebhookDeliveryServiceImpl) send(ctx context.Context, delivery *model.PendingWebhookDelivery) (bool, error) {
+	signedAt := w.now()
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	headers := map[string]string{
+		WebhookTimestampHeader:     timestamp,
+		WebhookSignatureHeader:     webhookSignature(delivery.Secret, timestamp, delivery.Payload),
+		WebhookDeliveryHeader:      strconv.FormatInt(delivery.ID, 10),
+		repository.EventIDHeader:   strconv.FormatInt(delivery.EventID, 10),
+		repository.EventTypeHeader: string(delivery.EventType),
+	}
+
+	status, err := w.client.Post(ctx, delivery.URL, headers, delivery.Payload)
+	if err == nil && (status < 200 || status > 299) {
+		err = fmt.Errorf("webhook responded with status %d", status)
+	}
+
+	attempt := &model.WebhookAttempt{
+		DeliveryID:     delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		Attempts:       delivery.Attempts + 1,
+		StatusCode:     status,
+		At:             w.now(),
+	}
+
+	if err == nil {
+		return true, w.repository.RecordDeliverySuccess(attempt)
+	}
+
+	attempt.Error = err.Error()
+	if attempt.Attempts < w.maxAttempts {
+		nextAttemptAt := attempt.At.Add(retryBackoff(attempt.Attempts, webhookBaseBackoff, webhookMaxBackoff))
+		attempt.NextAttemptAt = &nextAttemptAt
+	}
+
+	disabled, recordErr := w.repository.RecordDeliveryFailure(attempt, w.disableAfter)
+	if recordErr != nil {
+		return false, recordErr
+	}
+
+	if attempt.NextAttemptAt == nil {
+		w.log.Error("Webhook delivery failed", "webhook_id", delivery.SubscriptionID, "delivery_id", delivery.ID, "attempts", attempt.Attempts, "error", err)
+	} else {
+		w.log.Warn("Error sending webhook delivery, it will be retried", "webhook_id", delivery.SubscriptionID, "delivery_id", delivery.ID, "attempts", attempt.Attempts, "next_attempt_at", *attempt.NextAttemptAt, "error", err)
+	}
+
+	if disabled {
+		w.log.Error("Webhook disabled after consecutive failures", "webhook_id", delivery.SubscriptionID, "failures", w.disableAfter)
+	}
+
+	return false, nil
+}
+
+// Start sends the pending deliveries right away and then once per interval until the context is done
+func (w *WebhookDeliveryServiceImpl) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if delivered, err := w.Deliver(ctx); err != nil {
+			w.log.Error("error sending webhook deliveries", "error", err)
+		} else if delivered > 0 {
+			w.log.Debug("Webhook deliveries sent", "delivered", delivered)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func webhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
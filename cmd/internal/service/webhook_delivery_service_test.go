@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WebhookDeliveryService_Deliver(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockWebhookRepository(mockController)
+	mockClient := mock_repository.NewMockWebhookClient(mockController)
+
+	deliveryService := NewWebhookDeliveryService(mockRepository, mockClient, time.Second, 3, 20, slog.Default())
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	deliveryService.now = func() time.Time { return now }
+
+	pending := model.PendingWebhookDelivery{
+		WebhookDelivery: model.WebhookDelivery{ID: 9, SubscriptionID: 3, EventID: 7, EventType: model.EventTransactionCreated, Payload: []byte(`{"event_id":7}`), Status: model.DeliveryPending},
+		URL:             "https://example.com/hook",
+		Secret:          "0123456789abcdef",
+	}
+
+	t.Run("Deliver signs the payload and records the success", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetPendingDeliveries(now, webhookBatchSize).Return([]model.PendingWebhookDelivery{pending}, nil)
+		mockClient.EXPECT().Post(gomock.Any(), "https://example.com/hook", map[string]string{
+			"X-Webhook-Timestamp":   "1746878400",
+			"X-Webhook-Signature":   webhookSignature("0123456789abcdef", "1746878400", []byte(`{"event_id":7}`)),
+			"X-Webhook-Delivery-ID": "9",
+			"X-Event-ID":            "7",
+			"X-Event-Type":          "transaction.created",
+		}, []byte(`{"event_id":7}`)).Return(http.StatusOK, nil)
+		mockRepository.EXPECT().RecordDeliverySuccess(&model.WebhookAttempt{DeliveryID: 9, SubscriptionID: 3, Attempts: 1, StatusCode: http.StatusOK, At: now}).Return(nil)
+
+		// when
+		delivered, err := deliveryService.Deliver(context.Background())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+	})
+
+	t.Run("Deliver retries a rejected delivery with backoff", func(t *testing.T) {
+		// given
+		nextAttemptAt := now.Add(webhookBaseBackoff)
+		mockRepository.EXPECT().GetPendingDeliveries(now, webhookBatchSize).Return([]model.PendingWebhookDelivery{pending}, nil)
+		mockClient.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(http.StatusInternalServerError, nil)
+		mockRepository.EXPECT().RecordDeliveryFailure(&model.WebhookAttempt{
+			DeliveryID:     9,
+			SubscriptionID: 3,
+			Attempts:       1,
+			StatusCode:     http.StatusInternalServerError,
+			Error:          "webhook responded with status 500",
+			At:             now,
+			NextAttemptAt:  &nextAttemptAt,
+		}, 20).Return(false, nil)
+
+		// when
+		delivered, err := deliveryService.Deliver(context.Background())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Deliver fails the delivery at the max attempts", func(t *testing.T) {
+		// given
+		failing := pending
+		failing.Attempts = 2
+		mockRepository.EXPECT().GetPendingDeliveries(now, webhookBatchSize).Return([]model.PendingWebhookDelivery{failing}, nil)
+		mockClient.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(0, errors.New("connection refused"))
+		mockRepository.EXPECT().RecordDeliveryFailure(&model.WebhookAttempt{DeliveryID: 9, SubscriptionID: 3, Attempts: 3, Error: "connection refused", At: now}, 20).Return(true, nil)
+
+		// when
+		delivered, err := deliveryService.Deliver(context.Background())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Deliver error recording the attempt", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetPendingDeliveries(now, webhookBatchSize).Return([]model.PendingWebhookDelivery{pending}, nil)
+		mockClient.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(http.StatusNoContent, nil)
+		mockRepository.EXPECT().RecordDeliverySuccess(gomock.Any()).Return(errors.New("database is locked"))
+
+		// when
+		delivered, err := deliveryService.Deliver(context.Background())
+
+		// then
+		assert.EqualError(t, err, "error updating webhook delivery 9: database is locked")
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Deliver error getting pending deliveries", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetPendingDeliveries(now, webhookBatchSize).Return(nil, errors.New("database is locked"))
+
+		// when
+		delivered, err := deliveryService.Deliver(context.Background())
+
+		// then
+		assert.EqualError(t, err, "error getting pending webhook deliveries: database is locked")
+		assert.Equal(t, 0, delivered)
+	})
+}
+
+func Test_WebhookSignature(t *testing.T) {
+	// the expected value is the output of: printf '1746878400.{"event_id":7}' | openssl dgst -sha256 -hmac 0123456789abcdef
+	signature := webhookSignature("0123456789abcdef", "1746878400", []byte(`{"event_id":7}`))
+
+	assert.Equal(t, "sha256=f3f23d928cc8bc32f8a28f90de98c778646c98d37d73e6bef7444ba908a188c1", signature)
+}
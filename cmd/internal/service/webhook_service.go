@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/repository"
+)
+
+var errWebhookNotFound = model.NewNotFoundError("webhook not found")
+
+type WebhookService interface {
+	CreateSubscription(subscription *model.WebhookSubscription) (*presentation.WebhookSubscriptionDTO, error)
+	GetSubscription(subscriptionID int64) (*presentation.WebhookSubscriptionDTO, error)
+	ListSubscriptions() ([]presentation.WebhookSubscriptionDTO, error)
+	DeleteSubscription(subscriptionID int64) error
+	EnableSubscription(subscriptionID int64) (*presentation.WebhookSubscriptionDTO, error)
+	ListDeliveries(subscriptionID int64, limit, offset int) (*presentation.WebhookDeliveryPageDTO, error)
+}
+
+//go:generate mockgen -source=./webhook_service.go -destination=./mocks/webhook_service_mock.go
+
+type WebhookServiceImpl struct {
+	log        *slog.Logger
+	repository repository.WebhookRepository
+	now        func() time.Time
+}
+
+func NewWebhookService(log *slog.Logger, repository repository.WebhookRepository) *WebhookServiceImpl {
+	return &WebhookServiceImpl{
+		log:        log,
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+func (w *WebhookServiceImpl) CreateSubscription(subscription *model.WebhookSubscription) (*presentation.WebhookSubscriptionDTO, error) {
+	subscription.CreatedAt = w.now()
+	saved, err := w.repository.SaveSubscription(subscription)
+	if err != nil {
+		return nil, fmt.Errorf("error saving webhook: %w", err)
+	}
+
+	w.log.Debug("Webhook created", "webhook_id", saved.ID)
+	return presentation.NewWebhookSubscriptionDTO(saved), nil
+}
+
+func (w *WebhookServiceImpl) GetSubscription(subscriptionID int64) (*presentation.WebhookSubscriptionDTO, error) {
+	subscription, err := w.repository.GetSubscription(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting webhook: %w", err)
+	}
+
+	if subscription == nil {
+		return nil, errWebhookNotFound
+	}
+
+	return presentation.NewWebhookSubscriptionDTO(subscription), nil
+}
+
+func (w *WebhookServiceImpl) ListSubscriptions() ([]presentation.WebhookSubscriptionDTO, error) {
+	subscriptions, err := w.repository.ListSubscriptions()
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhooks: %w", err)
+	}
+
+	subscriptionDTOs := make([]presentation.WebhookSubscriptionDTO, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		subscriptionDTOs = append(subscriptionDTOs, *presentation.NewWebhookSubscriptionDTO(&subscription))
+	}
+
+	return subscriptionDTOs, nil
+}
+
+func (w *WebhookServiceImpl) DeleteSubscription(subscriptionID int64) error {
+	deleted, err := w.repository.DeleteSubscription(subscriptionID)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+
+	if !deleted {
+		return errWebhookNotFound
+	}
+
+	w.log.Debug("Webhook deleted", "webhook_id", subscriptionID)
+	return nil
+}
+
+// EnableSubscription reactivates a webhook disabled after too many failed deliveries
+func (w *WebhookServiceImpl) EnableSubscription(subscriptionID int64) (*presentation.WebhookSubscriptionDTO, error) {
+	subscription, err := w.repository.EnableSubscription(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error enabling webhook: %w", err)
+	}
+
+	if subscription == nil {
+		return nil, errWebhookNotFound
+	}
+
+	w.log.Info("Webhook enabled", "webhook_id", subscriptionID)
+	return presentation.NewWebhookSubscriptionDTO(subscription), nil
+}
+
+// ListDeliveries returns one page of the delivery log of the webhook, the newest deliveries first
+func (w *WebhookServiceImpl) ListDeliveries(subscriptionID int64, limit, offset int) (*presentation.WebhookDeliveryPageDTO, error) {
+	if _, err := w.GetSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := w.repository.ListDeliveries(subscriptionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhook deliveries: %w", err)
+	}
+
+	page := &presentation.WebhookDeliveryPageDTO{
+		Data:   make([]presentation.WebhookDeliveryDTO, 0, len(deliveries)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	for _, delivery := range deliveries {
+		page.Data = append(page.Data, *presentation.NewWebhookDeliveryDTO(&delivery))
+	}
+
+	return page, nil
+}
+
+// Publish fans the event out to a delivery per subscribed webhook, so the outbox dispatcher can use the service as
+// an event publisher. The deliveries are sent by the WebhookDeliveryService
+func (w *WebhookServiceImpl) Publish(_ context.Context, message *model.EventMessage) error {
+	enqueued, err := w.repository.EnqueueDeliveries(message.ID, message.Type, message.Body, w.now())
+	if err != nil {
+		return fmt.Errorf("error enqueuing webhook deliveries: %w", err)
+	}
+
+	if enqueued > 0 {
+		w.log.Debug("Webhook deliveries enqueued", "event_id", message.ID, "event_type", message.Type, "deliveries", enqueued)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/model"
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/presentation"
+	mock_repository "github.com/pablorodrigo52/transaction-api/cmd/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WebhookService_Subscriptions(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockWebhookRepository(mockController)
+
+	webhookService := NewWebhookService(slog.Default(), mockRepository)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	webhookService.now = func() time.Time { return now }
+
+	subscription := &model.WebhookSubscription{
+		ID:         3,
+		URL:        "https://example.com/hook",
+		EventTypes: []model.EventType{model.AllEvents},
+		Secret:     "0123456789abcdef",
+		Active:     true,
+		CreatedAt:  now,
+	}
+	expected := &presentation.WebhookSubscriptionDTO{
+		WebhookID: 3,
+		URL:       "https://example.com/hook",
+		Events:    []string{"*"},
+		Active:    true,
+		CreatedAt: now.UTC().Format(time.RFC3339Nano),
+	}
+
+	t.Run("CreateSubscription with success does not return the secret", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().SaveSubscription(&model.WebhookSubscription{
+			URL:        "https://example.com/hook",
+			EventTypes: []model.EventType{model.AllEvents},
+			Secret:     "0123456789abcdef",
+			CreatedAt:  now,
+		}).Return(subscription, nil)
+
+		// when
+		result, err := webhookService.CreateSubscription(&model.WebhookSubscription{
+			URL:        "https://example.com/hook",
+			EventTypes: []model.EventType{model.AllEvents},
+			Secret:     "0123456789abcdef",
+		})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("GetSubscription not found", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetSubscription(int64(4)).Return(nil, nil)
+
+		// when
+		result, err := webhookService.GetSubscription(4)
+
+		// then
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("ListSubscriptions with success", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().ListSubscriptions().Return([]model.WebhookSubscription{*subscription}, nil)
+
+		// when
+		result, err := webhookService.ListSubscriptions()
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, []presentation.WebhookSubscriptionDTO{*expected}, result)
+	})
+
+	t.Run("DeleteSubscription not found", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().DeleteSubscription(int64(4)).Return(false, nil)
+
+		// when
+		err := webhookService.DeleteSubscription(4)
+
+		// then
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("DeleteSubscription error on repository", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().DeleteSubscription(int64(3)).Return(false, errors.New("database is locked"))
+
+		// when
+		err := webhookService.DeleteSubscription(3)
+
+		// then
+		assert.EqualError(t, err, "error deleting webhook: database is locked")
+	})
+
+	t.Run("EnableSubscription with success", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().EnableSubscription(int64(3)).Return(subscription, nil)
+
+		// when
+		result, err := webhookService.EnableSubscription(3)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+}
+
+func Test_WebhookService_ListDeliveries(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockWebhookRepository(mockController)
+
+	webhookService := NewWebhookService(slog.Default(), mockRepository)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("ListDeliveries with success", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetSubscription(int64(3)).Return(&model.WebhookSubscription{ID: 3, CreatedAt: now}, nil)
+		mockRepository.EXPECT().ListDeliveries(int64(3), 20, 0).Return([]model.WebhookDelivery{
+			{ID: 9, SubscriptionID: 3, EventID: 7, EventType: model.EventTransactionCreated, Status: model.DeliveryFailed, Attempts: 8, NextAttemptAt: now, LastError: "connection refused", CreatedAt: now},
+		}, int64(1), nil)
+
+		// when
+		page, err := webhookService.ListDeliveries(3, 20, 0)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, &presentation.WebhookDeliveryPageDTO{
+			Data: []presentation.WebhookDeliveryDTO{
+				{DeliveryID: 9, EventID: 7, EventType: "transaction.created", Status: "failed", Attempts: 8, LastError: "connection refused", CreatedAt: now.UTC().Format(time.RFC3339Nano)},
+			},
+			Total: 1,
+			Limit: 20,
+		}, page)
+	})
+
+	t.Run("ListDeliveries of a webhook not found", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().GetSubscription(int64(4)).Return(nil, nil)
+
+		// when
+		page, err := webhookService.ListDeliveries(4, 20, 0)
+
+		// then
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.Nil(t, page)
+	})
+}
+
+func Test_WebhookService_Publish(t *testing.T) {
+	t.Parallel()
+	mockController := gomock.NewController(t)
+	mockRepository := mock_repository.NewMockWebhookRepository(mockController)
+
+	webhookService := NewWebhookService(slog.Default(), mockRepository)
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	webhookService.now = func() time.Time { return now }
+	message := &model.EventMessage{ID: 7, Type: model.EventTransactionDeleted, Body: []byte(`{"event_id":7}`)}
+
+	t.Run("Publish enqueues a delivery per subscribed webhook", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().EnqueueDeliveries(int64(7), model.EventTransactionDeleted, []byte(`{"event_id":7}`), now).Return(int64(2), nil)
+
+		// when
+		err := webhookService.Publish(context.Background(), message)
+
+		// then
+		assert.NoError(t, err)
+	})
+
+	t.Run("Publish error on repository is retried by the outbox", func(t *testing.T) {
+		// given
+		mockRepository.EXPECT().EnqueueDeliveries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), errors.New("database is locked"))
+
+		// when
+		err := webhookService.Publish(context.Background(), message)
+
+		// then
+		assert.EqualError(t, err, "error enqueuing webhook deliveries: database is locked")
+	})
+}
@@ -0,0 +1,103 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxDecimalPlaces       = 18
+	maxAmountDecimalPlaces = 2
+)
+
+var (
+	centsPerUnit = big.NewRat(100, 1)
+	maxCents     = new(big.Int).SetUint64(1<<63 - 1)
+	// decimalPattern leaves out the hex, underscore, exponent and fraction forms big.Rat also accepts
+	decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+)
+
+// ParseAmount parses an exact decimal amount (e.g. "123.45") with up to two decimal places and returns it in cents
+func ParseAmount(amount string) (int64, error) {
+	decimal, err := ParseDecimal(amount)
+	if err != nil {
+		return 0, errors.New("invalid amount, it must be a decimal number")
+	}
+
+	if _, fraction, _ := strings.Cut(strings.TrimSpace(amount), "."); len(fraction) > maxAmountDecimalPlaces {
+		return 0, fmt.Errorf("invalid amount, it must have at most %d decimal places", maxAmountDecimalPlaces)
+	}
+
+	return roundToCents(decimal.Mul(decimal, centsPerUnit))
+}
+
+// ParseDecimal parses an exact decimal number (e.g. "5.434") without going through float math
+func ParseDecimal(value string) (*big.Rat, error) {
+	value = strings.TrimSpace(value)
+	if !decimalPattern.MatchString(value) {
+		return nil, fmt.Errorf("invalid decimal number: %q", value)
+	}
+
+	decimal, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal number: %q", value)
+	}
+
+	return decimal, nil
+}
+
+// FormatAmount formats an amount in cents as an exact decimal string with two decimal places (e.g. "123.45")
+func FormatAmount(cents int64) string {
+	sign := ""
+	absolute := new(big.Int).SetInt64(cents)
+	if cents < 0 {
+		sign = "-"
+		absolute.Neg(absolute)
+	}
+
+	units, remainder := new(big.Int).QuoRem(absolute, big.NewInt(100), new(big.Int))
+	return fmt.Sprintf("%s%s.%02d", sign, units.String(), remainder.Int64())
+}
+
+// FormatDecimal formats an exact decimal number with only the decimal places it needs (e.g. "5.434"), rounding it
+// to maxDecimalPlaces when it needs more
+func FormatDecimal(value *big.Rat) string {
+	scale := big.NewInt(1)
+	for places := 0; places < maxDecimalPlaces; places++ {
+		if new(big.Int).Mod(scale, value.Denom()).Sign() == 0 {
+			return value.FloatString(places)
+		}
+		scale.Mul(scale, big.NewInt(10))
+	}
+
+	return value.FloatString(maxDecimalPlaces)
+}
+
+// ConvertAmount multiplies an amount in cents by an exact decimal rate and rounds the result to the nearest cent
+func ConvertAmount(cents int64, rate *big.Rat) (int64, error) {
+	return roundToCents(new(big.Rat).Mul(rate, new(big.Rat).SetInt64(cents)))
+}
+
+// roundToCents rounds a value already expressed in cents to an integer, half away from zero
+func roundToCents(value *big.Rat) (int64, error) {
+	numerator := new(big.Int).Abs(value.Num())
+	denominator := value.Denom()
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Mul(remainder, big.NewInt(2)).Cmp(denominator) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+
+	if quotient.Cmp(maxCents) > 0 {
+		return 0, errors.New("amount out of range")
+	}
+
+	if value.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+
+	return quotient.Int64(), nil
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      int64
+		expectedError string
+	}{
+		{input: "1.0", expected: 100},
+		{input: "1.23", expected: 123},
+		{input: "-0.55", expected: -55},
+		{input: " 7 ", expected: 700},
+		{input: "123456789.99", expected: 12345678999},
+		{input: "1.005", expectedError: "invalid amount, it must have at most 2 decimal places"},
+		{input: "1.230", expectedError: "invalid amount, it must have at most 2 decimal places"},
+		{input: "", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "1/3", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "mock", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "0x10", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "1_000", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "1e2", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "1e100000", expectedError: "invalid amount, it must be a decimal number"},
+		{input: ".5", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "+1", expectedError: "invalid amount, it must be a decimal number"},
+		{input: "100000000000000000000", expectedError: "amount out of range"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			result, err := ParseAmount(test.input)
+
+			if test.expectedError != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedError, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      *big.Rat
+		expectedError string
+	}{
+		{input: "5.434", expected: big.NewRat(5434, 1000)},
+		{input: "-0.25", expected: big.NewRat(-1, 4)},
+		{input: "20", expected: big.NewRat(20, 1)},
+		{input: "0x10", expectedError: `invalid decimal number: "0x10"`},
+		{input: "1_000", expectedError: `invalid decimal number: "1_000"`},
+		{input: "1e100000", expectedError: `invalid decimal number: "1e100000"`},
+		{input: "1/3", expectedError: `invalid decimal number: "1/3"`},
+		{input: "5.", expectedError: `invalid decimal number: "5."`},
+		{input: "", expectedError: `invalid decimal number: ""`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			result, err := ParseDecimal(test.input)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{input: 0, expected: "0.00"},
+		{input: 5, expected: "0.05"},
+		{input: 100, expected: "1.00"},
+		{input: 12345678999, expected: "123456789.99"},
+		{input: -150, expected: "-1.50"},
+		{input: -9223372036854775808, expected: "-92233720368547758.08"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, FormatAmount(test.input))
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	tests := []struct {
+		name          string
+		cents         int64
+		rate          string
+		expected      int64
+		expectedError string
+	}{
+		{name: "Convert small amount", cents: 174, rate: "6.18", expected: 1075},
+		{name: "Convert large amount without losing cents", cents: 12345678999, rate: "5.434", expected: 67086419681},
+		{name: "Convert rounding half up", cents: 1, rate: "0.5", expected: 1},
+		{name: "Convert out of range", cents: 1 << 62, rate: "4", expectedError: "amount out of range"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rate, err := ParseDecimal(test.rate)
+			assert.NoError(t, err)
+
+			result, err := ConvertAmount(test.cents, rate)
+
+			if test.expectedError != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedError, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "Format integer", value: "20", expected: "20"},
+		{name: "Format with the decimal places needed", value: "5.4340", expected: "5.434"},
+		{name: "Format negative", value: "-0.25", expected: "-0.25"},
+		{name: "Format periodic rounded", value: "1/3", expected: "0.333333333333333333"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, _ := new(big.Rat).SetString(test.value)
+
+			assert.Equal(t, test.expected, FormatDecimal(value))
+		})
+	}
+}
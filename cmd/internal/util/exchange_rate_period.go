@@ -0,0 +1,22 @@
+package util
+
+import "time"
+
+// ExchangeRatePeriod returns the last quarter end (Mar 31, Jun 30, Sep 30 or Dec 31) on or before the date,
+// the Treasury publishes one rate per country for each of those dates
+func ExchangeRatePeriod(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	quarter := (int(day.Month()) - 1) / 3
+
+	quarterEnd := time.Date(day.Year(), time.Month(quarter*3+4), 0, 0, 0, 0, 0, time.UTC)
+	if !day.Before(quarterEnd) {
+		return quarterEnd
+	}
+
+	return time.Date(day.Year(), time.Month(quarter*3+1), 0, 0, 0, 0, 0, time.UTC)
+}
+
+// NextExchangeRatePeriod returns the quarter end after the period returned by ExchangeRatePeriod
+func NextExchangeRatePeriod(period time.Time) time.Time {
+	return time.Date(period.Year(), period.Month()+4, 0, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,40 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeRatePeriod(t *testing.T) {
+	tests := []struct {
+		input    time.Time
+		expected time.Time
+	}{
+		{input: time.Date(2024, 9, 29, 23, 0, 0, 0, time.UTC), expected: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{input: time.Date(2024, 9, 30, 10, 0, 0, 0, time.UTC), expected: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)},
+		{input: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), expected: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{input: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), expected: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{input: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), expected: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ExchangeRatePeriod(test.input))
+	}
+}
+
+func TestNextExchangeRatePeriod(t *testing.T) {
+	tests := []struct {
+		input    time.Time
+		expected time.Time
+	}{
+		{input: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), expected: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{input: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC), expected: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{input: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), expected: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NextExchangeRatePeriod(test.input))
+	}
+}
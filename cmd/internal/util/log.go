@@ -0,0 +1,35 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+// WithLogger returns a copy of the context carrying the logger of the request
+func WithLogger(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// Logger returns the logger of the request carried by the context, or log when there is none
+func Logger(ctx context.Context, log *slog.Logger) *slog.Logger {
+	if requestLog, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return requestLog
+	}
+
+	return log
+}
+
+// WithRequestID returns a copy of the context carrying the ID of the request
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the request carried by the context, empty when there is none
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
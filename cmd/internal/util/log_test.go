@@ -0,0 +1,31 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	t.Run("Logger of the request", func(t *testing.T) {
+		// Given
+		requestLog := slog.Default().With("request_id", "abc")
+		ctx := WithLogger(context.Background(), requestLog)
+
+		// When
+		log := Logger(ctx, slog.Default())
+
+		// Then
+		assert.Same(t, requestLog, log)
+	})
+
+	t.Run("Logger fallback without request", func(t *testing.T) {
+		// When
+		log := Logger(context.Background(), slog.Default())
+
+		// Then
+		assert.Same(t, slog.Default(), log)
+	})
+}
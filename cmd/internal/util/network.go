@@ -0,0 +1,24 @@
+package util
+
+import (
+	"net/netip"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, private to the provider network
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublicAddress reports whether the address is not loopback, private, link-local (e.g. the 169.254.169.254 of
+// the cloud metadata), multicast or unspecified, so a request to it cannot reach the internal network
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected bool
+	}{
+		{address: "93.184.216.34", expected: true},
+		{address: "2606:2800:220:1:248:1893:25c8:1946", expected: true},
+		{address: "127.0.0.1", expected: false},
+		{address: "::1", expected: false},
+		{address: "10.0.0.1", expected: false},
+		{address: "172.16.5.4", expected: false},
+		{address: "192.168.1.1", expected: false},
+		{address: "169.254.169.254", expected: false},
+		{address: "fe80::1", expected: false},
+		{address: "fd00::1", expected: false},
+		{address: "100.64.0.1", expected: false},
+		{address: "0.0.0.0", expected: false},
+		{address: "224.0.0.1", expected: false},
+		{address: "::ffff:127.0.0.1", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsPublicAddress(netip.MustParseAddr(test.address)))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEndSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	// When
+	_, ok := tracer.Start(context.Background(), "ok")
+	EndSpan(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	EndSpan(failed, errors.New("database is locked"))
+
+	// Then
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "database is locked", spans[1].Status().Description)
+	assert.Len(t, spans[1].Events(), 1)
+}
@@ -1,42 +1,202 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/pablorodrigo52/transaction-api/cmd/internal/config"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/infrastructure"
 	"github.com/pablorodrigo52/transaction-api/cmd/internal/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
 func main() {
-	config := infrastructure.InitInfrastructure()
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	if cfg.PrintConfig {
+		if err := cfg.Write(os.Stdout); err != nil {
+			slog.Error("Failed to print configuration", "error", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	config, err := infrastructure.InitInfrastructure(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize infrastructure", "error", err)
+		os.Exit(1)
+	}
+
 	dependencies := infrastructure.InitDependencies(config)
 
 	initMiddlewares(config)
-	initHandlers(config, dependencies)
+	initHandlers(cfg.Admin, config, dependencies)
+
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	workersContext, stopWorkers := context.WithCancel(context.Background())
+	workers := initWorkers(workersContext, dependencies)
+
+	server := infrastructure.NewHTTPServer(cfg.Server, config.Router.MuxRouter)
+	serverErr := make(chan error, 1)
+	go func() {
+		config.Log.Info(fmt.Sprintf("Starting server on http://localhost:%d", config.Router.Port))
+		serverErr <- server.ListenAndServe()
+	}()
+	dependencies.HealthController.SetReady(true)
+
+	exitCode := 0
+	select {
+	case err := <-serverErr:
+		config.Log.Error("Server failed to start", "error", err)
+		exitCode = 1
+	case <-signals.Done():
+		// a second signal kills the process without waiting for the drain
+		stopSignals()
+		config.Log.Info("Shutting down, draining requests in flight", "drain_delay", cfg.Server.DrainDelay, "timeout", cfg.Server.ShutdownTimeout)
+	}
+
+	if err := shutdown(cfg.Server, config, dependencies, server, stopWorkers, workers); err != nil {
+		config.Log.Error("Failed to shut down gracefully", "error", err)
+		exitCode = 1
+	}
+
+	config.Log.Info("Server stopped")
+	os.Exit(exitCode)
+}
+
+// shutdown reports the server is not ready, waits the drain delay so no new requests are sent to it, drains the
+// requests in flight, stops the workers and then closes the infrastructure, all within the shutdown timeout
+func shutdown(
+	cfg config.Server,
+	config *infrastructure.Infrastructure,
+	dependencies *infrastructure.Dependencies,
+	server *http.Server,
+	stopWorkers context.CancelFunc,
+	workers *sync.WaitGroup) error {
+
+	dependencies.HealthController.SetReady(false)
+	time.Sleep(cfg.DrainDelay)
 
-	config.Log.Info(fmt.Sprintf("Starting server on http://localhost:%d", config.Router.Port))
-	if r := http.ListenAndServe(fmt.Sprintf(":%d", config.Router.Port), config.Router.MuxRouter); r != nil {
-		config.Log.Error("Server failed to start", "error", r)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	errs := []error{}
+	if err := server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("error draining requests: %w", err))
+	}
+
+	stopWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		errs = append(errs, errors.New("workers did not stop before the shutdown timeout"))
+	}
+
+	if err := config.Close(); err != nil {
+		errs = append(errs, err)
 	}
+
+	return errors.Join(errs...)
 }
 
 func initMiddlewares(config *infrastructure.Infrastructure) {
+	config.Router.MuxRouter.Use(otelmux.Middleware(config.Tracing.ServiceName()))
+	config.Router.MuxRouter.Use(middleware.Logging(config.Log))
+	config.Router.MuxRouter.Use(middleware.Metrics(config.Metrics.HTTPRequests, config.Metrics.HTTPDuration))
 	config.Router.MuxRouter.Use(middleware.ErrorHandler)
 	config.Router.MuxRouter.Use(middleware.JSONContentTypeMiddleware)
 }
 
-func initHandlers(config *infrastructure.Infrastructure, dependencies *infrastructure.Dependencies) {
+// initWorkers starts the background workers, they stop when the context is done
+func initWorkers(ctx context.Context, dependencies *infrastructure.Dependencies) *sync.WaitGroup {
+	var workers sync.WaitGroup
+	start := func(worker func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			worker(ctx)
+		}()
+	}
+
+	// treasury exchange rates synchronizer
+	start(dependencies.TreasurySyncService.Start)
+
+	// hard delete of the transactions deleted longer than the retention
+	start(dependencies.TransactionPurgeService.Start)
+
+	// publisher of the transaction events written to the outbox
+	start(dependencies.OutboxDispatcher.Start)
+
+	// sender of the signed webhook deliveries
+	start(dependencies.WebhookDeliveryService.Start)
+
+	return &workers
+}
+
+func initHandlers(admin config.Admin, config *infrastructure.Infrastructure, dependencies *infrastructure.Dependencies) {
 	// ping handler
 	config.Router.MuxRouter.HandleFunc("/ping", dependencies.PingController.Ping).Methods("GET")
 
+	// prometheus metrics handler
+	config.Router.MuxRouter.Handle("/metrics", config.Metrics.Handler()).Methods("GET")
+
+	// liveness and readiness handlers, not ready while draining on shutdown or with a critical dependency down
+	config.Router.MuxRouter.HandleFunc("/health/live", dependencies.HealthController.Live).Methods("GET")
+	config.Router.MuxRouter.HandleFunc("/health/ready", dependencies.HealthController.Ready).Methods("GET")
+
+	// log level handlers, to change the level without restarting the server, only served with the admin token
+	if admin.Token != "" {
+		config.Router.MuxRouter.HandleFunc("/admin/log-level", middleware.HandleErrors(middleware.RequireToken(admin.Token, dependencies.LogLevelController.GetLogLevel))).Methods("GET")
+		config.Router.MuxRouter.HandleFunc("/admin/log-level", middleware.HandleErrors(middleware.RequireToken(admin.Token, dependencies.LogLevelController.UpdateLogLevel))).Methods("PUT")
+	}
+
 	// transaction handlers
 	r := config.Router.MuxRouter.PathPrefix("/v1").Subrouter()
-	r.HandleFunc("/transaction/{id}", dependencies.TransactionController.GetTransactionByID).Methods("GET")
-	r.HandleFunc("/transaction", dependencies.TransactionController.CreateTransaction).Methods("POST")
-	r.HandleFunc("/transaction/{id}", dependencies.TransactionController.UpdateTransaction).Methods("PUT")
-	r.HandleFunc("/transaction/{id}", dependencies.TransactionController.DeleteTransaction).Methods("DELETE")
+	r.HandleFunc("/transaction/{id}", middleware.HandleErrors(dependencies.TransactionController.GetTransactionByID)).Methods("GET")
+	r.HandleFunc("/transaction", middleware.HandleErrors(dependencies.TransactionController.CreateTransaction)).Methods("POST")
+	r.HandleFunc("/transaction/{id}", middleware.HandleErrors(dependencies.TransactionController.UpdateTransaction)).Methods("PUT")
+	r.HandleFunc("/transaction/{id}", middleware.HandleErrors(dependencies.TransactionController.PatchTransaction)).Methods("PATCH")
+	r.HandleFunc("/transaction/{id}", middleware.HandleErrors(dependencies.TransactionController.DeleteTransaction)).Methods("DELETE")
+	r.HandleFunc("/transaction/{id}/restore", middleware.HandleErrors(dependencies.TransactionController.RestoreTransaction)).Methods("POST")
+	r.HandleFunc("/transaction/{id}/history", middleware.HandleErrors(dependencies.TransactionController.GetTransactionHistory)).Methods("GET")
+	r.HandleFunc("/transactions", middleware.HandleErrors(dependencies.TransactionController.ListTransactions)).Methods("GET")
 
 	// transaction currency handlers
-	r.HandleFunc("/converter/transaction/{id}/currency/{country}", dependencies.TransactionCurrencyController.GetTransactionCurrency).Methods("GET")
+	r.HandleFunc("/converter/transaction/{id}/currency/{country}", middleware.HandleErrors(dependencies.TransactionCurrencyController.GetTransactionCurrency)).Methods("GET")
+	r.HandleFunc("/converter/transaction/{id}", middleware.HandleErrors(dependencies.TransactionCurrencyController.GetTransactionCurrencies)).Methods("GET")
+	r.HandleFunc("/converter/currency/{country}", middleware.HandleErrors(dependencies.TransactionCurrencyController.ConvertTransactionsCurrency)).Methods("POST")
+
+	// webhook handlers
+	r.HandleFunc("/webhooks", middleware.HandleErrors(dependencies.WebhookController.CreateWebhook)).Methods("POST")
+	r.HandleFunc("/webhooks", middleware.HandleErrors(dependencies.WebhookController.ListWebhooks)).Methods("GET")
+	r.HandleFunc("/webhooks/{id}", middleware.HandleErrors(dependencies.WebhookController.GetWebhook)).Methods("GET")
+	r.HandleFunc("/webhooks/{id}", middleware.HandleErrors(dependencies.WebhookController.DeleteWebhook)).Methods("DELETE")
+	r.HandleFunc("/webhooks/{id}/enable", middleware.HandleErrors(dependencies.WebhookController.EnableWebhook)).Methods("POST")
+	r.HandleFunc("/webhooks/{id}/deliveries", middleware.HandleErrors(dependencies.WebhookController.ListWebhookDeliveries)).Methods("GET")
 }
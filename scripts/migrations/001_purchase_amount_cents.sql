@@ -0,0 +1,16 @@
+-- purchase_amount is stored in cents (INTEGER) instead of dollars (REAL) to avoid float rounding
+CREATE TABLE transactions_cents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    description TEXT NOT NULL,
+    transaction_date TEXT NOT NULL,
+    purchase_amount INTEGER NOT NULL,
+    deleted INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO transactions_cents (id, description, transaction_date, purchase_amount, deleted)
+SELECT id, description, transaction_date, CAST(ROUND(purchase_amount * 100) AS INTEGER), deleted
+FROM transactions;
+
+DROP TABLE transactions;
+
+ALTER TABLE transactions_cents RENAME TO transactions;
@@ -0,0 +1,11 @@
+-- local copy of the Treasury Reporting Rates of Exchange dataset, kept up to date by the treasury synchronizer
+CREATE TABLE rates_of_exchange (
+    record_date TEXT NOT NULL,
+    country TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    exchange_rate TEXT NOT NULL, -- kept as text to preserve the exact decimal
+    effective_date TEXT NOT NULL,
+    PRIMARY KEY (record_date, country, currency)
+);
+
+CREATE INDEX idx_rates_of_exchange_country_effective_date ON rates_of_exchange (country, effective_date);
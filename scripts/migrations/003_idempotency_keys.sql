@@ -0,0 +1,11 @@
+-- responses of the requests sent with an Idempotency-Key header, replayed to retries until they expire
+CREATE TABLE idempotency_keys (
+    idempotency_key TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL, -- hash of the request, a retry must send the same request
+    completed INTEGER NOT NULL DEFAULT 0,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_body BLOB,
+    expires_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
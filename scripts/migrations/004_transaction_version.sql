@@ -0,0 +1,2 @@
+-- version of each transaction, incremented on every change and exposed as its ETag
+ALTER TABLE transactions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
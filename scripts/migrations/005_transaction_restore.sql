@@ -0,0 +1,9 @@
+-- when a transaction was deleted, used by the purge job, and who restored it and when
+ALTER TABLE transactions ADD COLUMN deleted_at TEXT;
+ALTER TABLE transactions ADD COLUMN restored_at TEXT;
+ALTER TABLE transactions ADD COLUMN restored_by TEXT;
+
+-- the retention of the transactions deleted before this migration starts now
+UPDATE transactions SET deleted_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE deleted = 1;
+
+CREATE INDEX idx_transactions_deleted_at ON transactions (deleted_at) WHERE deleted = 1;
@@ -0,0 +1,42 @@
+-- append-only audit trail of the transactions, old_value and new_value are JSON snapshots of the transaction
+CREATE TABLE transaction_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER NOT NULL,
+    operation TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    request_id TEXT,
+    occurred_at TEXT NOT NULL, -- UTC with microseconds, fixed width so it can be compared as text
+    old_value TEXT,
+    new_value TEXT
+);
+
+CREATE INDEX idx_transaction_events_transaction_id ON transaction_events (transaction_id, occurred_at);
+
+CREATE TRIGGER transaction_events_no_update BEFORE UPDATE ON transaction_events
+BEGIN
+    SELECT RAISE(ABORT, 'transaction_events is append-only');
+END;
+
+CREATE TRIGGER transaction_events_no_delete BEFORE DELETE ON transaction_events
+BEGIN
+    SELECT RAISE(ABORT, 'transaction_events is append-only');
+END;
+
+-- the history of the existing transactions starts from their state at this migration
+INSERT INTO transaction_events (transaction_id, operation, actor, occurred_at, new_value)
+SELECT
+    id,
+    'baseline',
+    'migration',
+    strftime('%Y-%m-%dT%H:%M:%f000Z', 'now'),
+    json_object(
+        'description', description,
+        'transaction_date', transaction_date,
+        'purchase_amount', purchase_amount,
+        'deleted', json(CASE WHEN deleted = 1 THEN 'true' ELSE 'false' END),
+        'version', version,
+        'deleted_at', deleted_at,
+        'restored_at', restored_at,
+        'restored_by', restored_by
+    )
+FROM transactions;
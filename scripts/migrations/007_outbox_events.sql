@@ -0,0 +1,16 @@
+-- transaction events waiting to be published to the downstream consumers, written in the same database
+-- transaction of the change. payload is the JSON snapshot of the transaction after the change
+CREATE TABLE outbox_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_type TEXT NOT NULL,
+    transaction_id INTEGER NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, delivered or dead after too many failed attempts
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TEXT NOT NULL,
+    last_error TEXT,
+    delivered_at TEXT
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+-- expires_at is compared as text, rewrite it with the fixed width of the event times (microseconds in UTC)
+UPDATE idempotency_keys SET expires_at = strftime('%Y-%m-%dT%H:%M:%f', expires_at) || '000Z';
@@ -0,0 +1,2 @@
+-- transaction created by the request, the key is completed in the same database transaction that creates it
+ALTER TABLE idempotency_keys ADD COLUMN transaction_id INTEGER;